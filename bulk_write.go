@@ -2,18 +2,34 @@ package mongorm
 
 import (
 	"context"
+	"fmt"
+	"maps"
 
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
 )
 
-// BulkWrite executes multiple write models in a single request.
+// BulkWrite executes multiple write models in a single request. Insert models built from
+// *T documents (for example with BulkWriteBuilder.InsertOne) get their empty
+// `sequence:<name>` fields filled before the request is sent. UpdateOne and UpdateMany
+// models run the BeforeUpdateMany hook with their filter and update document and then
+// follow the rules of SaveMulti: updates of primary, readonly and created_at fields are
+// rejected, and the version field and updated_at timestamp are set on a copy of each
+// model. Updates must be bson.M or bson.D documents; pipelines and other shapes are
+// rejected because these rules cannot inspect them. DeleteOne and DeleteMany models run
+// the BeforeDeleteMany hook with their filter. After a successful request, the
+// AfterUpdateMany and AfterDeleteMany hooks run for every such model with the totals of
+// the whole request. Rejected writes are reported as a *WriteErrors keyed by the position
+// of each failed model.
 func (m *MongORM[T]) BulkWrite(
 	ctx context.Context,
 	models []mongo.WriteModel,
 	opts ...options.Lister[options.BulkWriteOptions],
-) (*mongo.BulkWriteResult, error) {
+) (result *mongo.BulkWriteResult, err error) {
+	ctx, telemetry := m.startOperation(ctx, "BulkWrite")
+	defer func() { telemetry.end(result, err) }()
+
 	if err := m.ensureReady(); err != nil {
 		return nil, err
 	}
@@ -22,14 +38,233 @@ func (m *MongORM[T]) BulkWrite(
 		return nil, configErrorf("bulk write requires at least one write model")
 	}
 
-	result, err := m.info.collection.BulkWrite(ctx, models, opts...)
+	if err := m.assignSequencesToModels(ctx, models); err != nil {
+		return nil, err
+	}
+
+	models, targets, err := m.prepareWriteModels(ctx, models)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err = m.info.collection.BulkWrite(ctx, models, opts...)
 	if err != nil {
-		return nil, normalizeError(err)
+		return nil, m.normalizeWriteErrors(err)
+	}
+
+	if err := m.runAfterBulkWriteHooks(ctx, targets, result); err != nil {
+		return nil, err
 	}
 
 	return result, nil
 }
 
+// bulkWriteTarget is the filter and update document a write model was sent with, kept for
+// its after hook. update is nil for delete models.
+//
+// > NOTE: This type is internal only.
+type bulkWriteTarget struct {
+	filter bson.M
+	update bson.M
+}
+
+// prepareWriteModels returns models with the filters and update documents of UpdateOne,
+// UpdateMany, DeleteOne and DeleteMany models replaced by copies that went through the
+// BeforeUpdateMany or BeforeDeleteMany hook and, for updates, prepareUpdateDoc. It also
+// returns those filters and updates for the after hooks. Other models are kept as they
+// are.
+//
+// > NOTE: This method is internal only.
+func (m *MongORM[T]) prepareWriteModels(
+	ctx context.Context,
+	models []mongo.WriteModel,
+) ([]mongo.WriteModel, []bulkWriteTarget, error) {
+	prepared := make([]mongo.WriteModel, len(models))
+	targets := []bulkWriteTarget{}
+
+	for i, model := range models {
+		switch typed := model.(type) {
+		case *mongo.UpdateOneModel:
+			target, err := m.prepareBulkUpdate(ctx, i, typed.Filter, typed.Update)
+			if err != nil {
+				return nil, nil, err
+			}
+			copied := *typed
+			copied.Filter, copied.Update = target.filter, target.update
+			model = &copied
+			targets = append(targets, target)
+		case *mongo.UpdateManyModel:
+			target, err := m.prepareBulkUpdate(ctx, i, typed.Filter, typed.Update)
+			if err != nil {
+				return nil, nil, err
+			}
+			copied := *typed
+			copied.Filter, copied.Update = target.filter, target.update
+			model = &copied
+			targets = append(targets, target)
+		case *mongo.DeleteOneModel:
+			target, err := m.prepareBulkDelete(ctx, i, typed.Filter)
+			if err != nil {
+				return nil, nil, err
+			}
+			copied := *typed
+			copied.Filter = target.filter
+			model = &copied
+			targets = append(targets, target)
+		case *mongo.DeleteManyModel:
+			target, err := m.prepareBulkDelete(ctx, i, typed.Filter)
+			if err != nil {
+				return nil, nil, err
+			}
+			copied := *typed
+			copied.Filter = target.filter
+			model = &copied
+			targets = append(targets, target)
+		}
+
+		prepared[i] = model
+	}
+
+	return prepared, targets, nil
+}
+
+// prepareBulkUpdate copies the filter and update of the update model at position i, runs
+// the BeforeUpdateMany hook on them and applies prepareUpdateDoc to the update.
+//
+// > NOTE: This method is internal only.
+func (m *MongORM[T]) prepareBulkUpdate(ctx context.Context, i int, filter any, update any) (bulkWriteTarget, error) {
+	filterDoc, err := documentOf(filter)
+	if err != nil {
+		return bulkWriteTarget{}, configErrorf("write model %d: filter: %v", i, err)
+	}
+	if filterDoc == nil {
+		filterDoc = bson.M{}
+	}
+
+	updateDoc, err := documentOf(update)
+	if err != nil || updateDoc == nil {
+		return bulkWriteTarget{}, configErrorf("write model %d: update of type %T cannot be checked; use a bson.M or bson.D document", i, update)
+	}
+
+	if hook, ok := m.beforeUpdateManyHook(); ok {
+		if err := hook(ctx, m, &filterDoc, &updateDoc); err != nil {
+			return bulkWriteTarget{}, err
+		}
+	}
+
+	if err := m.prepareUpdateDoc(&updateDoc); err != nil {
+		return bulkWriteTarget{}, configErrorf("write model %d: %v", i, err)
+	}
+
+	return bulkWriteTarget{filter: filterDoc, update: updateDoc}, nil
+}
+
+// prepareBulkDelete copies the filter of the delete model at position i and runs the
+// BeforeDeleteMany hook on it.
+//
+// > NOTE: This method is internal only.
+func (m *MongORM[T]) prepareBulkDelete(ctx context.Context, i int, filter any) (bulkWriteTarget, error) {
+	filterDoc, err := documentOf(filter)
+	if err != nil {
+		return bulkWriteTarget{}, configErrorf("write model %d: filter: %v", i, err)
+	}
+	if filterDoc == nil {
+		filterDoc = bson.M{}
+	}
+
+	if hook, ok := m.beforeDeleteManyHook(); ok {
+		if err := hook(ctx, m, &filterDoc); err != nil {
+			return bulkWriteTarget{}, err
+		}
+	}
+
+	return bulkWriteTarget{filter: filterDoc}, nil
+}
+
+// runAfterBulkWriteHooks runs the AfterUpdateMany or AfterDeleteMany hook for every
+// target, passing the totals of result.
+//
+// > NOTE: This method is internal only.
+func (m *MongORM[T]) runAfterBulkWriteHooks(
+	ctx context.Context,
+	targets []bulkWriteTarget,
+	result *mongo.BulkWriteResult,
+) error {
+	if result == nil {
+		result = &mongo.BulkWriteResult{}
+	}
+
+	updated := &mongo.UpdateResult{
+		MatchedCount:  result.MatchedCount,
+		ModifiedCount: result.ModifiedCount,
+		UpsertedCount: result.UpsertedCount,
+		Acknowledged:  result.Acknowledged,
+	}
+	deleted := &mongo.DeleteResult{
+		DeletedCount: result.DeletedCount,
+		Acknowledged: result.Acknowledged,
+	}
+
+	for _, target := range targets {
+		if target.update != nil {
+			if hook, ok := m.afterUpdateManyHook(); ok {
+				if err := hook(ctx, m, &target.filter, &target.update, updated); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		if hook, ok := m.afterDeleteManyHook(); ok {
+			if err := hook(ctx, m, &target.filter, deleted); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// documentOf copies a bson.M, bson.D or map[string]any document, and the operator
+// documents nested in it, into a bson.M. It returns nil for a nil value and an error for
+// any other type.
+//
+// > NOTE: This function is internal only.
+func documentOf(value any) (bson.M, error) {
+	var doc bson.M
+	switch typed := value.(type) {
+	case nil:
+		return nil, nil
+	case bson.M:
+		doc = maps.Clone(typed)
+	case map[string]any:
+		doc = bson.M(maps.Clone(typed))
+	case bson.D:
+		doc = make(bson.M, len(typed))
+		for _, element := range typed {
+			doc[element.Key] = element.Value
+		}
+	default:
+		return nil, fmt.Errorf("unsupported document type %T", value)
+	}
+
+	for key, nested := range doc {
+		switch typed := nested.(type) {
+		case bson.M, map[string]any, bson.D:
+			if len(key) == 0 || key[0] != '$' {
+				continue
+			}
+			converted, err := documentOf(typed)
+			if err != nil {
+				return nil, err
+			}
+			doc[key] = converted
+		}
+	}
+
+	return doc, nil
+}
+
 // BulkWriteInTransaction executes a bulk write operation inside a transaction.
 func (m *MongORM[T]) BulkWriteInTransaction(
 	ctx context.Context,
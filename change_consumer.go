@@ -0,0 +1,204 @@
+package mongorm
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// DefaultResumeTokensCollection is the collection storing consumer checkpoints when
+// ConsumeOptions.Store is not set.
+const DefaultResumeTokensCollection = "_resume_tokens"
+
+// ResumeTokenStore persists the resume token of named change stream consumers.
+type ResumeTokenStore interface {
+	// LoadResumeToken returns the last saved token of the consumer, or nil when the
+	// consumer has no checkpoint yet.
+	LoadResumeToken(ctx context.Context, name string) (bson.Raw, error)
+	// SaveResumeToken records token as the checkpoint of the consumer.
+	SaveResumeToken(ctx context.Context, name string, token bson.Raw) error
+}
+
+// CollectionResumeTokenStore is a ResumeTokenStore backed by a collection. Each consumer
+// is stored as {_id: <name>, token: <resume token>, updated_at: <time>}.
+type CollectionResumeTokenStore struct {
+	collection Collection
+}
+
+// NewResumeTokenStore returns a ResumeTokenStore keeping checkpoints in collection.
+//
+// Example usage:
+//
+//	store := mongorm.NewResumeTokenStore(backend.Collection("checkpoints"))
+func NewResumeTokenStore(collection Collection) *CollectionResumeTokenStore {
+	return &CollectionResumeTokenStore{collection: collection}
+}
+
+// LoadResumeToken returns the saved token of the consumer, or nil when there is none.
+func (s *CollectionResumeTokenStore) LoadResumeToken(ctx context.Context, name string) (bson.Raw, error) {
+	if s == nil || s.collection == nil {
+		return nil, configErrorf("resume token store has no collection")
+	}
+
+	var checkpoint struct {
+		Token bson.Raw `bson:"token"`
+	}
+
+	if err := s.collection.FindOne(ctx, bson.M{"_id": name}).Decode(&checkpoint); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, normalizeError(err)
+	}
+
+	return checkpoint.Token, nil
+}
+
+// SaveResumeToken records token as the checkpoint of the consumer.
+func (s *CollectionResumeTokenStore) SaveResumeToken(ctx context.Context, name string, token bson.Raw) error {
+	if s == nil || s.collection == nil {
+		return configErrorf("resume token store has no collection")
+	}
+
+	_, err := s.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": name},
+		bson.M{"$set": bson.M{"token": token, "updated_at": time.Now().UTC()}},
+		options.UpdateOne().SetUpsert(true),
+	)
+
+	return normalizeError(err)
+}
+
+// ChangeHandler handles one change event of a consumer.
+type ChangeHandler[T any] func(ctx context.Context, event *ChangeEvent[T]) error
+
+// ConsumeOptions configures Consume.
+type ConsumeOptions struct {
+	// Store keeps the checkpoints. Defaults to a CollectionResumeTokenStore on the
+	// DefaultResumeTokensCollection collection of the model database.
+	Store ResumeTokenStore
+	// CheckpointEvery saves the resume token after this many handled events. Defaults
+	// to 1, which checkpoints after every event. Pending events are checkpointed when
+	// Consume returns.
+	CheckpointEvery int
+	// StreamOptions are passed to Watch, after the resume option of the checkpoint.
+	StreamOptions []options.Lister[options.ChangeStreamOptions]
+}
+
+// Consume watches the collection, using the accumulated Where filters like Watch, and
+// calls handler for every event. The consumer is identified by name: its resume token is
+// checkpointed after handled events and, on the next call, the stream resumes after the
+// last checkpoint.
+//
+// Delivery is at least once. An event is only checkpointed after handler returns nil,
+// so events handled after the last checkpoint are delivered again after a restart, and
+// handlers should be idempotent. Consume returns the handler error after checkpointing
+// the events handled before it, and the context error once ctx is done. It returns nil
+// when the stream is invalidated, for example because the collection was dropped.
+//
+// Example usage:
+//
+//	err := orm.Where(ToDoFields.Done.Eq(true)).Consume(ctx, "todo-notifier",
+//	    func(ctx context.Context, event *mongorm.ChangeEvent[ToDo]) error {
+//	        return notify(ctx, event.FullDocument)
+//	    },
+//	)
+func (m *MongORM[T]) Consume(
+	ctx context.Context,
+	name string,
+	handler ChangeHandler[T],
+	opts ...ConsumeOptions,
+) error {
+	if err := m.ensureReady(); err != nil {
+		return err
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return configErrorf("consumer name cannot be empty")
+	}
+	if handler == nil {
+		return configErrorf("change handler cannot be nil")
+	}
+
+	var opt ConsumeOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	if opt.CheckpointEvery <= 0 {
+		opt.CheckpointEvery = 1
+	}
+	if opt.Store == nil {
+		if m.info.backend == nil {
+			return configErrorf("mongodb database is not initialized; set ConsumeOptions.Store")
+		}
+		coll := newMiddlewareCollection(m.info.backend.Collection(DefaultResumeTokensCollection), m.options)
+		opt.Store = NewResumeTokenStore(coll)
+	}
+
+	token, err := opt.Store.LoadResumeToken(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	streamOpts := make([]options.Lister[options.ChangeStreamOptions], 0, len(opt.StreamOptions)+1)
+	if token != nil {
+		streamOpts = append(streamOpts, options.ChangeStream().SetResumeAfter(token))
+	}
+	streamOpts = append(streamOpts, opt.StreamOptions...)
+
+	stream, err := m.Watch(ctx, streamOpts...)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = stream.Close(context.WithoutCancel(ctx)) }()
+
+	var pending bson.Raw
+	handled := 0
+
+	// checkpoint saves the last handled token. It ignores cancellation of ctx so that
+	// progress made before shutdown is kept.
+	checkpoint := func() error {
+		if pending == nil {
+			return nil
+		}
+		if err := opt.Store.SaveResumeToken(context.WithoutCancel(ctx), name, pending); err != nil {
+			return err
+		}
+		pending = nil
+		return nil
+	}
+
+	for stream.Next(ctx) {
+		event := stream.Event()
+		if err := handler(ctx, event); err != nil {
+			if checkpointErr := checkpoint(); checkpointErr != nil {
+				return errors.Join(err, checkpointErr)
+			}
+			return err
+		}
+
+		pending = event.ResumeToken
+		handled++
+		if handled%opt.CheckpointEvery == 0 {
+			if err := checkpoint(); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := checkpoint(); err != nil {
+		return err
+	}
+	if err := stream.Err(); err != nil {
+		return err
+	}
+
+	return ctx.Err()
+}
@@ -0,0 +1,258 @@
+package mongorm
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// ChangeOperationType is the kind of change reported by a change event.
+type ChangeOperationType string
+
+// Change event operation types.
+const (
+	ChangeOperationInsert       ChangeOperationType = "insert"
+	ChangeOperationUpdate       ChangeOperationType = "update"
+	ChangeOperationReplace      ChangeOperationType = "replace"
+	ChangeOperationDelete       ChangeOperationType = "delete"
+	ChangeOperationDrop         ChangeOperationType = "drop"
+	ChangeOperationRename       ChangeOperationType = "rename"
+	ChangeOperationDropDatabase ChangeOperationType = "dropDatabase"
+	ChangeOperationInvalidate   ChangeOperationType = "invalidate"
+)
+
+// ChangeEvent is a change stream event whose documents are decoded into the schema type.
+type ChangeEvent[T any] struct {
+	// ResumeToken identifies the event. Pass it to SetResumeAfter or SetStartAfter to
+	// continue the stream after this event.
+	ResumeToken   bson.Raw            `bson:"_id"`
+	OperationType ChangeOperationType `bson:"operationType"`
+	// DocumentKey holds the _id (and shard key) of the changed document.
+	DocumentKey bson.M `bson:"documentKey,omitempty"`
+	// FullDocument is the document after the change. Update events only carry it when
+	// the stream looks it up, which Watch enables by default.
+	FullDocument *T `bson:"fullDocument,omitempty"`
+	// FullDocumentBeforeChange is the document before the change, when the collection
+	// records pre-images and the stream requests them.
+	FullDocumentBeforeChange *T                       `bson:"fullDocumentBeforeChange,omitempty"`
+	UpdateDescription        *ChangeUpdateDescription `bson:"updateDescription,omitempty"`
+	Namespace                ChangeNamespace          `bson:"ns"`
+	ClusterTime              bson.Timestamp           `bson:"clusterTime"`
+	WallTime                 *time.Time               `bson:"wallTime,omitempty"`
+}
+
+// ChangeUpdateDescription describes the fields changed by an update event.
+type ChangeUpdateDescription struct {
+	UpdatedFields   bson.M                 `bson:"updatedFields"`
+	RemovedFields   []string               `bson:"removedFields"`
+	TruncatedArrays []ChangeTruncatedArray `bson:"truncatedArrays,omitempty"`
+}
+
+// ChangeTruncatedArray reports an array field shortened by an update.
+type ChangeTruncatedArray struct {
+	Field   string `bson:"field"`
+	NewSize int32  `bson:"newSize"`
+}
+
+// ChangeNamespace is the database and collection of a change event.
+type ChangeNamespace struct {
+	DB   string `bson:"db"`
+	Coll string `bson:"coll"`
+}
+
+// ChangeStream iterates over typed change events. Close it when done to release the
+// server-side cursor.
+//
+// Example usage:
+//
+//	stream, err := orm.Where(ToDoFields.Done.Eq(false)).Watch(ctx)
+//	if err != nil {
+//	    return err
+//	}
+//	defer stream.Close(ctx)
+//
+//	for stream.Next(ctx) {
+//	    event := stream.Event()
+//	    fmt.Println(event.OperationType, event.DocumentKey)
+//	}
+//	return stream.Err()
+type ChangeStream[T any] struct {
+	MongoStream *mongo.ChangeStream `json:"-"`
+	current     *ChangeEvent[T]     `json:"-"`
+	err         error               `json:"-"`
+}
+
+// Watch opens a change stream on the collection. The accumulated Where filters become a
+// $match stage on the changed documents: filters on _id match the document key of every
+// event, and all other fields match fullDocument, so those filters skip delete events.
+// Update events look up the full document unless opts set another FullDocument mode.
+//
+// Example usage:
+//
+//	stream, err := orm.
+//	    Where(ToDoFields.Count.Gt(10)).
+//	    Watch(ctx, options.ChangeStream().SetResumeAfter(token))
+func (m *MongORM[T]) Watch(
+	ctx context.Context,
+	opts ...options.Lister[options.ChangeStreamOptions],
+) (_ *ChangeStream[T], err error) {
+	ctx, telemetry := m.startOperation(ctx, "Watch")
+	defer func() { telemetry.end(nil, err) }()
+
+	if err := m.ensureReady(); err != nil {
+		return nil, err
+	}
+
+	filters, _, err := m.withPrimaryFilters()
+	if err != nil {
+		return nil, err
+	}
+
+	pipeline := bson.A{}
+	if len(filters) > 0 {
+		match, err := changeStreamFilter(filters)
+		if err != nil {
+			return nil, err
+		}
+		pipeline = append(pipeline, bson.M{"$match": match})
+	}
+
+	allOpts := []options.Lister[options.ChangeStreamOptions]{
+		options.ChangeStream().SetFullDocument(options.UpdateLookup),
+	}
+	allOpts = append(allOpts, opts...)
+
+	stream, err := m.info.collection.Watch(ctx, pipeline, allOpts...)
+	if err != nil {
+		return nil, normalizeError(err)
+	}
+
+	return &ChangeStream[T]{MongoStream: stream}, nil
+}
+
+// Next blocks until the next event is available and decodes it. It returns false when
+// the stream is closed, ctx is done or an error occurs; call Err to tell them apart.
+func (s *ChangeStream[T]) Next(ctx context.Context) bool {
+	return s.advance(ctx, false)
+}
+
+// TryNext decodes the next event if one is already available, without waiting for new
+// events. It returns false when there is none; check Err to tell that from a failure.
+func (s *ChangeStream[T]) TryNext(ctx context.Context) bool {
+	return s.advance(ctx, true)
+}
+
+// Event returns the event decoded by the last successful Next or TryNext.
+func (s *ChangeStream[T]) Event() *ChangeEvent[T] {
+	if s == nil {
+		return nil
+	}
+
+	return s.current
+}
+
+// ResumeToken returns the token to resume the stream from: the last decoded event or,
+// between events, the latest post-batch token.
+func (s *ChangeStream[T]) ResumeToken() bson.Raw {
+	if s == nil || s.MongoStream == nil {
+		return nil
+	}
+
+	return s.MongoStream.ResumeToken()
+}
+
+// Err returns the most recent stream error observed by Next or TryNext.
+func (s *ChangeStream[T]) Err() error {
+	if s == nil {
+		return configErrorf("change stream is nil")
+	}
+
+	return s.err
+}
+
+// Close closes the stream.
+func (s *ChangeStream[T]) Close(ctx context.Context) error {
+	if s == nil || s.MongoStream == nil {
+		return configErrorf("change stream is nil")
+	}
+
+	s.current = nil
+
+	return normalizeError(s.MongoStream.Close(ctx))
+}
+
+// advance moves the stream to the next event.
+//
+// > NOTE: This method is internal only.
+func (s *ChangeStream[T]) advance(ctx context.Context, try bool) bool {
+	if s == nil || s.MongoStream == nil {
+		if s != nil {
+			s.current = nil
+			s.err = configErrorf("change stream is nil")
+		}
+		return false
+	}
+
+	s.current = nil
+	s.err = nil
+
+	next := s.MongoStream.Next
+	if try {
+		next = s.MongoStream.TryNext
+	}
+	if !next(ctx) {
+		s.err = normalizeError(s.MongoStream.Err())
+		return false
+	}
+
+	event := &ChangeEvent[T]{}
+	if err := s.MongoStream.Decode(event); err != nil {
+		s.err = normalizeError(err)
+		return false
+	}
+	s.current = event
+
+	return true
+}
+
+// changeStreamFilter rewrites a query filter to match change events: _id paths match the
+// document key and every other path matches the full document.
+//
+// > NOTE: This function is internal only.
+func changeStreamFilter(filter bson.M) (bson.M, error) {
+	rewritten := bson.M{}
+
+	for key, value := range filter {
+		switch {
+		case key == "$and" || key == "$or" || key == "$nor":
+			clauses := bson.A{}
+			for _, clause := range expressionClauses(value) {
+				doc, ok := clause.(bson.M)
+				if !ok {
+					return nil, configErrorf("%s clauses must be documents", key)
+				}
+
+				nested, err := changeStreamFilter(doc)
+				if err != nil {
+					return nil, err
+				}
+				clauses = append(clauses, nested)
+			}
+			rewritten[key] = clauses
+		case key == "$comment":
+			rewritten[key] = value
+		case strings.HasPrefix(key, "$"):
+			return nil, configErrorf("%s filters cannot be used with Watch", key)
+		case key == "_id" || strings.HasPrefix(key, "_id."):
+			rewritten["documentKey."+key] = value
+		default:
+			rewritten["fullDocument."+key] = value
+		}
+	}
+
+	return rewritten, nil
+}
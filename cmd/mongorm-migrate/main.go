@@ -0,0 +1,31 @@
+// Command mongorm-migrate applies, reverts and lists MongORM migrations.
+//
+// This binary only knows the migrations registered by the packages it imports, so it
+// is mostly useful for status and unlock. Services build their own copy that imports
+// their migrations; see mongorm.RunMigrateCommand.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/azayn-labs/mongorm"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	err := mongorm.RunMigrateCommand(ctx, os.Args[1:], os.Stdout)
+	if err == nil || errors.Is(err, flag.ErrHelp) {
+		return
+	}
+
+	fmt.Fprintln(os.Stderr, err)
+	stop()
+	os.Exit(1)
+}
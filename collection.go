@@ -0,0 +1,174 @@
+package mongorm
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// Backend provides the collections a MongORM instance runs against. By default MongORM
+// uses the MongoDB database configured through the schema tags or MongORMOptions. Set
+// MongORMOptions.Backend to run against another implementation, such as the in-memory
+// backend in the memdb package.
+type Backend interface {
+	// Name returns the database name.
+	Name() string
+	// Collection returns the collection with the given name.
+	Collection(name string) Collection
+}
+
+// Collection is the set of collection operations MongORM relies on. It mirrors the
+// methods of *mongo.Collection so that results and errors keep the driver types.
+type Collection interface {
+	Name() string
+	FindOne(ctx context.Context, filter any, opts ...options.Lister[options.FindOneOptions]) *mongo.SingleResult
+	Find(ctx context.Context, filter any, opts ...options.Lister[options.FindOptions]) (*mongo.Cursor, error)
+	FindOneAndUpdate(
+		ctx context.Context,
+		filter any,
+		update any,
+		opts ...options.Lister[options.FindOneAndUpdateOptions],
+	) *mongo.SingleResult
+	InsertOne(
+		ctx context.Context,
+		document any,
+		opts ...options.Lister[options.InsertOneOptions],
+	) (*mongo.InsertOneResult, error)
+	InsertMany(
+		ctx context.Context,
+		documents any,
+		opts ...options.Lister[options.InsertManyOptions],
+	) (*mongo.InsertManyResult, error)
+	UpdateOne(
+		ctx context.Context,
+		filter any,
+		update any,
+		opts ...options.Lister[options.UpdateOneOptions],
+	) (*mongo.UpdateResult, error)
+	UpdateMany(
+		ctx context.Context,
+		filter any,
+		update any,
+		opts ...options.Lister[options.UpdateManyOptions],
+	) (*mongo.UpdateResult, error)
+	DeleteOne(
+		ctx context.Context,
+		filter any,
+		opts ...options.Lister[options.DeleteOneOptions],
+	) (*mongo.DeleteResult, error)
+	DeleteMany(
+		ctx context.Context,
+		filter any,
+		opts ...options.Lister[options.DeleteManyOptions],
+	) (*mongo.DeleteResult, error)
+	BulkWrite(
+		ctx context.Context,
+		models []mongo.WriteModel,
+		opts ...options.Lister[options.BulkWriteOptions],
+	) (*mongo.BulkWriteResult, error)
+	Aggregate(ctx context.Context, pipeline any, opts ...options.Lister[options.AggregateOptions]) (*mongo.Cursor, error)
+	Watch(
+		ctx context.Context,
+		pipeline any,
+		opts ...options.Lister[options.ChangeStreamOptions],
+	) (*mongo.ChangeStream, error)
+	Distinct(
+		ctx context.Context,
+		fieldName string,
+		filter any,
+		opts ...options.Lister[options.DistinctOptions],
+	) DistinctResult
+	CountDocuments(ctx context.Context, filter any, opts ...options.Lister[options.CountOptions]) (int64, error)
+	Indexes() IndexView
+}
+
+// DistinctResult is the result of Collection.Distinct. *mongo.DistinctResult implements it.
+type DistinctResult interface {
+	Decode(v any) error
+	Err() error
+}
+
+// IndexView manages the indexes of a Collection. mongo.IndexView implements it.
+type IndexView interface {
+	List(ctx context.Context, opts ...options.Lister[options.ListIndexesOptions]) (*mongo.Cursor, error)
+	ListSpecifications(
+		ctx context.Context,
+		opts ...options.Lister[options.ListIndexesOptions],
+	) ([]mongo.IndexSpecification, error)
+	CreateOne(
+		ctx context.Context,
+		model mongo.IndexModel,
+		opts ...options.Lister[options.CreateIndexesOptions],
+	) (string, error)
+	CreateMany(
+		ctx context.Context,
+		models []mongo.IndexModel,
+		opts ...options.Lister[options.CreateIndexesOptions],
+	) ([]string, error)
+	DropOne(ctx context.Context, name string, opts ...options.Lister[options.DropIndexesOptions]) error
+	DropAll(ctx context.Context, opts ...options.Lister[options.DropIndexesOptions]) error
+}
+
+// NewCollection wraps a driver collection in the default Collection adapter. Use it as
+// the base of a decorator passed through MongORMOptions.Collection.
+//
+// Example usage:
+//
+//	type countingCollection struct {
+//	    mongorm.Collection
+//	    finds int
+//	}
+//
+//	func (c *countingCollection) Find(ctx context.Context, filter any, opts ...options.Lister[options.FindOptions]) (*mongo.Cursor, error) {
+//	    c.finds++
+//	    return c.Collection.Find(ctx, filter, opts...)
+//	}
+//
+//	coll := &countingCollection{Collection: mongorm.NewCollection(db.Collection("todos"))}
+//	orm := mongorm.FromOptions(&ToDo{}, &mongorm.MongORMOptions{Collection: coll})
+func NewCollection(coll *mongo.Collection) Collection {
+	return &mongoCollection{Collection: coll}
+}
+
+// NewBackend wraps a driver database in the default Backend adapter, for APIs such as
+// NewMigrator that work on a whole database.
+func NewBackend(db *mongo.Database) Backend {
+	return &mongoBackend{db: db}
+}
+
+// mongoBackend adapts a *mongo.Database to Backend.
+//
+// > NOTE: This type is internal only.
+type mongoBackend struct {
+	db *mongo.Database
+}
+
+func (b *mongoBackend) Name() string {
+	return b.db.Name()
+}
+
+func (b *mongoBackend) Collection(name string) Collection {
+	return NewCollection(b.db.Collection(name))
+}
+
+// mongoCollection adapts a *mongo.Collection to Collection. Every method except Distinct
+// and Indexes is promoted from the embedded driver collection.
+//
+// > NOTE: This type is internal only.
+type mongoCollection struct {
+	*mongo.Collection
+}
+
+func (c *mongoCollection) Distinct(
+	ctx context.Context,
+	fieldName string,
+	filter any,
+	opts ...options.Lister[options.DistinctOptions],
+) DistinctResult {
+	return c.Collection.Distinct(ctx, fieldName, filter, opts...)
+}
+
+func (c *mongoCollection) Indexes() IndexView {
+	return c.Collection.Indexes()
+}
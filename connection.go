@@ -32,6 +32,49 @@ func NewClient(connectionString string) (*mongo.Client, error) {
 }
 
 func (m *MongORM[T]) initializeClient() error {
+	if m.options != nil && m.options.Backend != nil {
+		m.info.backend = m.options.Backend
+	} else if m.options == nil || m.options.Collection == nil || m.options.MongoClient != nil {
+		if err := m.initializeMongoBackend(); err != nil {
+			return err
+		}
+	}
+
+	if m.info.backend != nil {
+		m.info.dbName = String(m.info.backend.Name())
+	}
+
+	if m.options != nil && m.options.Collection != nil {
+		m.info.collection = m.options.Collection
+	} else if m.options != nil && m.options.CollectionName != nil {
+		m.info.collection = m.info.backend.Collection(*m.options.CollectionName)
+	} else {
+		if err := m.setCollectionFromSchema(); err != nil {
+			return err
+		}
+	}
+
+	if m.info.collection == nil {
+		return configErrorf("mongodb collection is not provided in options or schema")
+	}
+	m.info.collection = newMiddlewareCollection(m.info.collection, m.options)
+
+	if err := m.setTimestampRequirementsFromSchema(); err != nil {
+		return err
+	}
+
+	if err := m.validateSoftDeleteField(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// initializeMongoBackend connects to the MongoDB database configured in the options or
+// schema tags and uses it as the backend.
+//
+// > NOTE: This method is internal only.
+func (m *MongORM[T]) initializeMongoBackend() error {
 	var client *mongo.Client
 	var err error
 	if m.options != nil && m.options.MongoClient != nil {
@@ -59,23 +102,7 @@ func (m *MongORM[T]) initializeClient() error {
 		return configErrorf("mongodb database is not provided in options or schema")
 	}
 
-	m.info.dbName = String(m.info.db.Name())
-
-	if m.options != nil && m.options.CollectionName != nil {
-		m.info.collection = m.info.db.Collection(*m.options.CollectionName)
-	} else {
-		if err := m.setCollectionFromSchema(); err != nil {
-			return err
-		}
-	}
-
-	if m.info.collection == nil {
-		return configErrorf("mongodb collection is not provided in options or schema")
-	}
-
-	if err := m.setTimestampRequirementsFromSchema(); err != nil {
-		return err
-	}
+	m.info.backend = NewBackend(m.info.db)
 
 	return nil
 }
@@ -155,7 +182,7 @@ func (m *MongORM[T]) setDatabaseFromSchema(client *mongo.Client) error {
 }
 
 func (m *MongORM[T]) setCollectionFromSchema() error {
-	if m.info.db == nil {
+	if m.info.backend == nil {
 		return configErrorf("database is not configured")
 	}
 
@@ -176,7 +203,7 @@ func (m *MongORM[T]) setCollectionFromSchema() error {
 				return configErrorf("field %s is missing the collection name tag value", fieldType.Name)
 			}
 
-			m.info.collection = m.info.db.Collection(tags[0])
+			m.info.collection = m.info.backend.Collection(tags[0])
 			return nil
 		}
 	}
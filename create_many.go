@@ -0,0 +1,293 @@
+package mongorm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// CreateManyResult reports the outcome of CreateMany for every input document. Both
+// slices have the same length and order as the documents passed to CreateMany.
+type CreateManyResult struct {
+	// InsertedIDs holds the primary key of each inserted document, or nil when the
+	// document was not inserted.
+	InsertedIDs []any `json:"insertedIds"`
+	// Errors holds the error for each document that failed, or nil when the document
+	// was inserted. Documents that were never sent because an ordered insert stopped
+	// early report ErrNotAttempted. A document whose AfterCreate hook fails is still
+	// inserted and reports the hook error here.
+	Errors []error `json:"-"`
+	// InsertedCount is the number of documents that were inserted.
+	InsertedCount int64 `json:"insertedCount"`
+}
+
+// Err returns an error summarizing the failed documents, or nil when no document failed.
+// The individual errors remain reachable with errors.Is and errors.As.
+func (r *CreateManyResult) Err() error {
+	if r == nil {
+		return nil
+	}
+
+	failed := []error{}
+	for i, err := range r.Errors {
+		if err != nil {
+			failed = append(failed, fmt.Errorf("document %d: %w", i, err))
+		}
+	}
+
+	if len(failed) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf(
+		"mongorm: %d of %d documents failed: %w",
+		len(failed),
+		len(r.Errors),
+		errors.Join(failed...),
+	)
+}
+
+// CreateMany inserts docs in a single InsertMany request. Each document goes through the
+// same steps as a single insert: version initialization, ID generation, sequence
+// assignment, the BeforeCreate hook and timestamps. After the request, every inserted
+// document is decoded back into its struct (filling generated IDs and timestamps) and its
+// AfterCreate hook runs.
+//
+// Inserts are ordered by default: the first failure stops the batch and the remaining
+// documents report ErrNotAttempted. Pass options.InsertMany().SetOrdered(false) to insert
+// every valid document regardless of failures. When any document fails, CreateMany
+// returns the result together with the error from CreateManyResult.Err.
+//
+// Example usage:
+//
+//	docs := []*ToDo{
+//	    {Text: mongorm.String("first")},
+//	    {Text: mongorm.String("second")},
+//	}
+//	res, err := mongorm.New(&ToDo{}).CreateMany(ctx, docs, options.InsertMany().SetOrdered(false))
+//	if err != nil {
+//	    for i, docErr := range res.Errors {
+//	        // Handle docErr for docs[i]
+//	    }
+//	}
+func (m *MongORM[T]) CreateMany(
+	ctx context.Context,
+	docs []*T,
+	opts ...options.Lister[options.InsertManyOptions],
+) (result *CreateManyResult, err error) {
+	ctx, telemetry := m.startOperation(ctx, "CreateMany")
+	defer func() { telemetry.end(result, err) }()
+
+	if err := m.ensureReady(); err != nil {
+		return nil, err
+	}
+
+	if len(docs) == 0 {
+		return nil, configErrorf("createMany requires at least one document")
+	}
+
+	ordered, err := insertManyOrdered(opts)
+	if err != nil {
+		return nil, configErrorf("invalid insert many options: %v", err)
+	}
+
+	_, primaryFieldName, err := m.getFieldByTag(ModelTagPrimary)
+	if err != nil {
+		return nil, err
+	}
+
+	result = &CreateManyResult{
+		InsertedIDs: make([]any, len(docs)),
+		Errors:      make([]error, len(docs)),
+	}
+
+	models := make([]*MongORM[T], len(docs))
+	payload := make([]any, 0, len(docs))
+	sent := make([]int, 0, len(docs))
+	stopped := false
+
+	for i, doc := range docs {
+		if stopped {
+			result.Errors[i] = ErrNotAttempted
+			continue
+		}
+
+		model, insertDoc, err := m.prepareCreateManyDocument(ctx, doc, primaryFieldName)
+		if err != nil {
+			result.Errors[i] = err
+			stopped = ordered
+			continue
+		}
+
+		models[i] = model
+		payload = append(payload, insertDoc)
+		sent = append(sent, i)
+	}
+
+	if len(payload) > 0 {
+		_, insertErr := m.info.collection.InsertMany(ctx, payload, opts...)
+		if insertErr != nil {
+			m.mapCreateManyError(insertErr, ordered, sent, result)
+		}
+
+		for position, i := range sent {
+			if result.Errors[i] != nil {
+				continue
+			}
+
+			insertDoc := payload[position].(bson.M)
+			if err := models[i].finishCreateManyDocument(ctx, insertDoc); err != nil {
+				result.Errors[i] = err
+			}
+
+			result.InsertedIDs[i] = insertDoc[primaryFieldName]
+			result.InsertedCount++
+		}
+	}
+
+	return result, result.Err()
+}
+
+// prepareCreateManyDocument runs the per-document insert preparation and returns the
+// document to send. The returned MongORM instance wraps doc for the hooks.
+//
+// > NOTE: This method is internal only.
+func (m *MongORM[T]) prepareCreateManyDocument(
+	ctx context.Context,
+	doc *T,
+	primaryFieldName string,
+) (*MongORM[T], bson.M, error) {
+	if doc == nil {
+		return nil, nil, configErrorf("document is nil")
+	}
+
+	model := m.clone()
+	model.schema = doc
+
+	if err := model.initializeVersionForInsert(); err != nil {
+		return nil, nil, err
+	}
+
+	if err := model.generatePrimaryID(); err != nil {
+		return nil, nil, err
+	}
+
+	if err := model.assignSequences(ctx, doc); err != nil {
+		return nil, nil, err
+	}
+
+	model.rebuildModifiedFromSchema()
+	if hook, ok := model.beforeCreateHook(); ok {
+		if err := hook(ctx, model); err != nil {
+			return nil, nil, err
+		}
+		model.rebuildModifiedFromSchema()
+	}
+
+	insertDoc, err := model.documentForInsertWithTimestamps()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Assign the identifier up front so it can be reported and filled back even when
+	// other documents in the batch fail. Only ObjectID keys can be generated here; other
+	// key types must be set on the document or by an IDGenerator.
+	if _, hasID := insertDoc[primaryFieldName]; !hasID {
+		if !m.primaryAcceptsObjectID() {
+			return nil, nil, configErrorf("document has no %s; set it or configure an IDGenerator", primaryFieldName)
+		}
+		insertDoc[primaryFieldName] = bson.NewObjectID()
+	}
+
+	return model, insertDoc, nil
+}
+
+// finishCreateManyDocument decodes the inserted document back into the schema and runs
+// the AfterCreate hook.
+//
+// > NOTE: This method is internal only.
+func (m *MongORM[T]) finishCreateManyDocument(ctx context.Context, insertDoc bson.M) error {
+	raw, err := bson.Marshal(insertDoc)
+	if err != nil {
+		return err
+	}
+
+	var stored T
+	if err := bson.Unmarshal(raw, &stored); err != nil {
+		return err
+	}
+
+	if err := m.applySchema(ctx, &stored); err != nil {
+		return err
+	}
+
+	if hook, ok := m.afterCreateHook(); ok {
+		if err := hook(ctx, m); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// mapCreateManyError distributes an InsertMany error over the sent documents. Write
+// errors are mapped by index; with ordered inserts, documents after the first failure
+// were not attempted. Errors without per-document detail mark every sent document.
+//
+// > NOTE: This method is internal only.
+func (m *MongORM[T]) mapCreateManyError(err error, ordered bool, sent []int, result *CreateManyResult) {
+	var bulkErr mongo.BulkWriteException
+	if !errors.As(err, &bulkErr) || len(bulkErr.WriteErrors) == 0 {
+		for _, i := range sent {
+			result.Errors[i] = m.normalizeWriteError(err)
+		}
+		return
+	}
+
+	firstFailure := len(sent)
+	for _, writeErr := range bulkErr.WriteErrors {
+		if writeErr.Index < 0 || writeErr.Index >= len(sent) {
+			continue
+		}
+
+		result.Errors[sent[writeErr.Index]] = m.normalizeWriteError(writeErr.WriteError)
+		firstFailure = min(firstFailure, writeErr.Index)
+	}
+
+	if ordered {
+		for position := firstFailure + 1; position < len(sent); position++ {
+			if result.Errors[sent[position]] == nil {
+				result.Errors[sent[position]] = ErrNotAttempted
+			}
+		}
+	}
+}
+
+// insertManyOrdered reports whether the InsertMany options request an ordered insert,
+// which is the MongoDB default.
+//
+// > NOTE: This function is internal only.
+func insertManyOrdered(opts []options.Lister[options.InsertManyOptions]) (bool, error) {
+	args := &options.InsertManyOptions{}
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+
+		for _, setter := range opt.List() {
+			if err := setter(args); err != nil {
+				return false, err
+			}
+		}
+	}
+
+	if args.Ordered == nil {
+		return true, nil
+	}
+
+	return *args.Ordered, nil
+}
@@ -11,6 +11,10 @@ import (
 // cursor and a reference to the MongORM instance to facilitate decoding documents into
 // the appropriate schema. The cursor should be closed when done to free up resources.
 //
+// Every document read by Next or All runs the BeforeFinalize, AfterFinalize and AfterFind
+// hooks of the schema, like a document loaded by First. Call WithoutCursorHooks before
+// FindAll or Aggregate to skip them.
+//
 // Example usage:
 //
 //	cursor, err := mongormInstance.FindAll(ctx)
@@ -28,6 +32,19 @@ type MongORMCursor[T any] struct {
 	m           *MongORM[T]   `json:"-"`
 	current     *T            `json:"-"`
 	err         error         `json:"-"`
+	skipHooks   bool          `json:"-"`
+}
+
+// WithoutCursorHooks skips the BeforeFinalize, AfterFinalize and AfterFind hooks for the
+// documents read by the next FindAll, Aggregate, FindAllAs or AggregateAs call, trading
+// them for raw decoding speed.
+//
+// Example usage:
+//
+//	cursor, err := orm.WithoutCursorHooks().FindAll(ctx)
+func (m *MongORM[T]) WithoutCursorHooks() *MongORM[T] {
+	m.operations.skipHooks = true
+	return m
 }
 
 // Next advances the cursor to the next document and decodes it into a new MongORM instance.
@@ -65,7 +82,18 @@ func (c *MongORMCursor[T]) Next(ctx context.Context) bool {
 		c.err = normalizeError(err)
 		return false
 	}
-	c.current = &u
+
+	if c.skipHooks || c.m == nil {
+		c.current = &u
+		return true
+	}
+
+	loaded, err := c.m.loadDocument(ctx, &u)
+	if err != nil {
+		c.err = err
+		return false
+	}
+	c.current = loaded.schema
 
 	return true
 }
@@ -101,6 +129,7 @@ func (c *MongORMCursor[T]) Current() *MongORM[T] {
 
 	clone := c.m.clone()
 	clone.schema = c.current
+	clone.takeSnapshot(c.current)
 	return clone
 }
 
@@ -144,8 +173,19 @@ func (c *MongORMCursor[T]) All(ctx context.Context) ([]*MongORM[T], error) {
 
 	clones := make([]*MongORM[T], len(results))
 	for i := range results {
+		if !c.skipHooks {
+			loaded, err := c.m.loadDocument(ctx, &results[i])
+			if err != nil {
+				c.err = err
+				return nil, err
+			}
+			clones[i] = loaded
+			continue
+		}
+
 		clone := c.m.clone()
 		clone.schema = &results[i]
+		clone.takeSnapshot(clone.schema)
 		clones[i] = clone
 	}
 
@@ -176,3 +216,78 @@ func (c *MongORMCursor[T]) Close(ctx context.Context) error {
 
 	return normalizeError(c.MongoCursor.Close(ctx))
 }
+
+// decodeAllAs decodes the remaining documents of cursor into R. Unless hooks were skipped
+// with WithoutCursorHooks, every document is loaded like a document read by Next: when R
+// is T it runs the hooks of the schema, otherwise loadResultAs runs the hooks of R.
+//
+// > NOTE: This function is internal only.
+func decodeAllAs[T any, R any](ctx context.Context, m *MongORM[T], cursor *mongo.Cursor) ([]R, error) {
+	results := []R{}
+
+	// WithoutCursorHooks applies to this call only.
+	skipHooks := m.operations.skipHooks
+	m.operations.skipHooks = false
+
+	if skipHooks {
+		if err := cursor.All(ctx, &results); err != nil {
+			return nil, err
+		}
+		return results, nil
+	}
+
+	for cursor.Next(ctx) {
+		var doc R
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+
+		if model, ok := any(&doc).(*T); ok {
+			loaded, err := m.loadDocument(ctx, model)
+			if err != nil {
+				return nil, err
+			}
+			doc = any(*loaded.schema).(R)
+		} else if err := loadResultAs(ctx, m, &doc); err != nil {
+			return nil, err
+		}
+
+		results = append(results, doc)
+	}
+
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// loadResultAs runs the BeforeFinalize, AfterFinalize and AfterFind hooks that *R
+// implements for models of T on doc, a decoded document, followed by the hooks registered
+// for those events with doc as HookArgs.Document. They receive a clone of m with an empty
+// schema, so they cannot change the state of m.
+//
+// > NOTE: This function is internal only.
+func loadResultAs[T any, R any](ctx context.Context, m *MongORM[T], doc *R) error {
+	clone := m.clone()
+	clone.schema = new(T)
+
+	before, _ := beforeFinalizeHookOf[T](doc)
+	after, _ := afterFinalizeHookOf[T](doc)
+	find, _ := afterFindHookOf[T](doc)
+
+	for _, hook := range []modelHook[T]{
+		before.then(registeredDocumentHook[T](HookBeforeFinalize, doc)),
+		after.then(registeredDocumentHook[T](HookAfterFinalize, doc)),
+		find.then(registeredDocumentHook[T](HookAfterFind, doc)),
+	} {
+		if hook == nil {
+			continue
+		}
+		if err := hook(ctx, clone); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -17,6 +17,11 @@ import (
 // The caller can provide additional options for the UpdateMany operation using the opts parameter.
 // It returns an UpdateResult containing information about the operation, such as the number of documents matched and modified, or an error if the operation fails.
 //
+// SaveMulti runs the BeforeUpdateMany and AfterUpdateMany hooks. After BeforeUpdateMany,
+// it rejects updates of primary, readonly and created_at fields outside $setOnInsert,
+// increments the version field and sets the updated_at timestamp, like single-document
+// updates.
+//
 // Example usage:
 //
 //	updateResult, err := mongormInstance.SaveMulti(ctx)
@@ -28,7 +33,10 @@ import (
 func (m *MongORM[T]) SaveMulti(
 	ctx context.Context,
 	opts ...options.Lister[options.UpdateManyOptions],
-) (*mongo.UpdateResult, error) {
+) (result *mongo.UpdateResult, err error) {
+	ctx, telemetry := m.startOperation(ctx, "SaveMulti")
+	defer func() { telemetry.end(result, err) }()
+
 	if err := m.ensureReady(); err != nil {
 		return nil, err
 	}
@@ -36,23 +44,82 @@ func (m *MongORM[T]) SaveMulti(
 	m.operations.fixQuery()
 	m.operations.fixUpdate()
 
-	if len(m.operations.update) == 0 {
+	filter := m.operations.query
+	update := m.operations.update
+
+	if hook, ok := m.beforeUpdateManyHook(); ok {
+		if err := hook(ctx, m, &filter, &update); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(update) == 0 {
 		return nil, configErrorf("no update operations specified")
 	}
 
+	if err := m.prepareUpdateDoc(&update); err != nil {
+		return nil, err
+	}
+
 	res, err := m.info.collection.UpdateMany(
 		ctx,
-		m.operations.query,
-		m.operations.update,
+		filter,
+		update,
 		opts...,
 	)
 	if err != nil {
-		return nil, normalizeError(err)
+		return nil, m.normalizeWriteErrors(err)
+	}
+
+	if hook, ok := m.afterUpdateManyHook(); ok {
+		if err := hook(ctx, m, &filter, &update, res); err != nil {
+			return nil, err
+		}
 	}
 
 	return res, nil
 }
 
+// prepareUpdateDoc applies the rules of single-document updates to an update document
+// written to many documents at once: it rejects primary, readonly and created_at fields
+// outside $setOnInsert, increments the version field unless the update sets it, and sets
+// the updated_at timestamp.
+//
+// > NOTE: This method is internal only.
+func (m *MongORM[T]) prepareUpdateDoc(update *bson.M) error {
+	paths := map[string]struct{}{}
+	for operator, value := range *update {
+		if operator == "$setOnInsert" {
+			continue
+		}
+
+		for _, path := range extractFieldPaths(value) {
+			if m.pathHasAnyModelTag(path, ModelTagPrimary, ModelTagReadonly, ModelTagTimestampCreatedAt) {
+				return configErrorf("field %s cannot be updated", path)
+			}
+			paths[path] = struct{}{}
+		}
+	}
+
+	_, versionKey, hasVersion, err := m.getVersionField()
+	if err != nil {
+		return err
+	}
+
+	if hasVersion && !pathOverlapsAny(versionKey, paths) {
+		inc, ok := (*update)["$inc"].(bson.M)
+		if !ok || inc == nil {
+			inc = bson.M{}
+		}
+		inc[versionKey] = int64(1)
+		(*update)["$inc"] = inc
+	}
+
+	m.applyTimestampsToUpdateDoc(update)
+
+	return nil
+}
+
 // FindAll retrieves all documents that match the specified query criteria and returns
 // a cursor for iterating over the results. It uses the Find method of the MongoDB
 // collection to execute the query and obtain a cursor for the matching documents.
@@ -72,7 +139,10 @@ func (m *MongORM[T]) SaveMulti(
 func (m *MongORM[T]) FindAll(
 	ctx context.Context,
 	opts ...options.Lister[options.FindOptions],
-) (*MongORMCursor[T], error) {
+) (_ *MongORMCursor[T], err error) {
+	ctx, telemetry := m.startOperation(ctx, "FindAll")
+	defer func() { telemetry.end(nil, err) }()
+
 	if err := m.ensureReady(); err != nil {
 		return nil, err
 	}
@@ -82,6 +152,8 @@ func (m *MongORM[T]) FindAll(
 		return nil, normalizeError(err)
 	}
 
+	filters = m.withSoftDeleteScope(filters, softDeleteScopeWithoutTrashed)
+
 	allOpts := []options.Lister[options.FindOptions]{
 		m.operations.findOptions(),
 	}
@@ -97,15 +169,28 @@ func (m *MongORM[T]) FindAll(
 		return nil, normalizeError(err)
 	}
 
-	return &MongORMCursor[T]{MongoCursor: cursor, m: m}, nil
+	// WithoutCursorHooks applies to this cursor only.
+	skipHooks := m.operations.skipHooks
+	m.operations.skipHooks = false
+
+	return &MongORMCursor[T]{MongoCursor: cursor, m: m, skipHooks: skipHooks}, nil
 }
 
 // DeleteMulti removes all documents that match the current filters.
 // It returns a DeleteResult containing the number of removed documents.
+//
+// When the model has a `soft_delete` field, matching documents are marked as deleted
+// instead, opts are ignored, and DeletedCount reports the number of documents marked.
+//
+// DeleteMulti runs BeforeDelete, BeforeDeleteMany, the delete, AfterDeleteMany and then
+// AfterDelete.
 func (m *MongORM[T]) DeleteMulti(
 	ctx context.Context,
 	opts ...options.Lister[options.DeleteManyOptions],
-) (*mongo.DeleteResult, error) {
+) (result *mongo.DeleteResult, err error) {
+	ctx, telemetry := m.startOperation(ctx, "DeleteMulti")
+	defer func() { telemetry.end(result, err) }()
+
 	if err := m.ensureReady(); err != nil {
 		return nil, err
 	}
@@ -115,22 +200,43 @@ func (m *MongORM[T]) DeleteMulti(
 		return nil, err
 	}
 
-	schema := any(m.schema)
-	if hook, ok := schema.(BeforeDeleteHook[T]); ok {
-		if err := hook.BeforeDelete(m, &filter); err != nil {
+	fieldName, softDelete := m.softDeleteFieldName()
+	if softDelete {
+		filter = m.withSoftDeleteScope(filter, softDeleteScopeWithoutTrashed)
+	}
+
+	if hook, ok := m.beforeDeleteHook(); ok {
+		if err := hook(ctx, m, &filter); err != nil {
+			return nil, err
+		}
+	}
+
+	if hook, ok := m.beforeDeleteManyHook(); ok {
+		if err := hook(ctx, m, &filter); err != nil {
 			return nil, err
 		}
 	}
 
-	res, err := m.info.collection.DeleteMany(ctx, filter, opts...)
+	var res *mongo.DeleteResult
+	if softDelete {
+		res, err = m.softDeleteMany(ctx, fieldName, filter)
+	} else {
+		res, err = m.info.collection.DeleteMany(ctx, filter, opts...)
+	}
 	if err != nil {
-		return nil, normalizeError(err)
+		return nil, m.normalizeWriteErrors(err)
 	}
 
 	m.operations.reset()
 
-	if hook, ok := schema.(AfterDeleteHook[T]); ok {
-		if err := hook.AfterDelete(m); err != nil {
+	if hook, ok := m.afterDeleteManyHook(); ok {
+		if err := hook(ctx, m, &filter, res); err != nil {
+			return nil, err
+		}
+	}
+
+	if hook, ok := m.afterDeleteHook(); ok {
+		if err := hook(ctx, m); err != nil {
 			return nil, err
 		}
 	}
@@ -142,7 +248,10 @@ func (m *MongORM[T]) DeleteMulti(
 func (m *MongORM[T]) Count(
 	ctx context.Context,
 	opts ...options.Lister[options.CountOptions],
-) (int64, error) {
+) (result int64, err error) {
+	ctx, telemetry := m.startOperation(ctx, "Count")
+	defer func() { telemetry.end(result, err) }()
+
 	if err := m.ensureReady(); err != nil {
 		return 0, err
 	}
@@ -152,6 +261,8 @@ func (m *MongORM[T]) Count(
 		return 0, err
 	}
 
+	filter = m.withSoftDeleteScope(filter, softDeleteScopeWithoutTrashed)
+
 	count, err := m.info.collection.CountDocuments(ctx, filter, opts...)
 	if err != nil {
 		return 0, normalizeError(err)
@@ -166,7 +277,10 @@ func (m *MongORM[T]) Distinct(
 	ctx context.Context,
 	field Field,
 	opts ...options.Lister[options.DistinctOptions],
-) ([]any, error) {
+) (result []any, err error) {
+	ctx, telemetry := m.startOperation(ctx, "Distinct")
+	defer func() { telemetry.end(result, err) }()
+
 	if err := m.ensureReady(); err != nil {
 		return nil, err
 	}
@@ -176,10 +290,12 @@ func (m *MongORM[T]) Distinct(
 		return nil, err
 	}
 
-	result := m.info.collection.Distinct(ctx, field.BSONName(), filter, opts...)
+	filter = m.withSoftDeleteScope(filter, softDeleteScopeWithoutTrashed)
+
+	distinct := m.info.collection.Distinct(ctx, field.BSONName(), filter, opts...)
 
 	values := []any{}
-	if err := result.Decode(&values); err != nil {
+	if err := distinct.Decode(&values); err != nil {
 		return nil, normalizeError(err)
 	}
 
@@ -272,7 +388,11 @@ func (m *MongORM[T]) Aggregate(
 		return nil, err
 	}
 
-	return &MongORMCursor[T]{MongoCursor: cursor, m: m}, nil
+	// WithoutCursorHooks applies to this cursor only.
+	skipHooks := m.operations.skipHooks
+	m.operations.skipHooks = false
+
+	return &MongORMCursor[T]{MongoCursor: cursor, m: m, skipHooks: skipHooks}, nil
 }
 
 // AggregateRaw runs an aggregation pipeline and returns a raw MongoDB cursor.
@@ -280,7 +400,10 @@ func (m *MongORM[T]) AggregateRaw(
 	ctx context.Context,
 	pipeline bson.A,
 	opts ...options.Lister[options.AggregateOptions],
-) (*mongo.Cursor, error) {
+) (_ *mongo.Cursor, err error) {
+	ctx, telemetry := m.startOperation(ctx, "Aggregate")
+	defer func() { telemetry.end(nil, err) }()
+
 	if err := m.ensureReady(); err != nil {
 		return nil, err
 	}
@@ -290,6 +413,8 @@ func (m *MongORM[T]) AggregateRaw(
 		return nil, err
 	}
 
+	filters = m.withSoftDeleteScope(filters, softDeleteScopeWithoutTrashed)
+
 	finalPipeline := bson.A{}
 	if len(filters) > 0 {
 		finalPipeline = append(finalPipeline, bson.M{"$match": filters})
@@ -323,8 +448,8 @@ func AggregateAs[T any, R any](
 		return nil, err
 	}
 
-	results := []R{}
-	if err := cursor.All(ctx, &results); err != nil {
+	results, err := decodeAllAs[T, R](ctx, m, cursor)
+	if err != nil {
 		if closeErr := cursor.Close(ctx); closeErr != nil {
 			return nil, errors.Join(normalizeError(err), normalizeError(closeErr))
 		}
@@ -56,7 +56,10 @@ func (m *MongORM[T]) Find(
 func (m *MongORM[T]) First(
 	ctx context.Context,
 	opts ...options.Lister[options.FindOneOptions],
-) error {
+) (err error) {
+	ctx, telemetry := m.startOperation(ctx, "First")
+	defer func() { telemetry.end(int64(1), err) }()
+
 	if err := m.ensureReady(); err != nil {
 		return err
 	}
@@ -66,6 +69,8 @@ func (m *MongORM[T]) First(
 		return err
 	}
 
+	filter = m.withSoftDeleteScope(filter, softDeleteScopeWithoutTrashed)
+
 	allOpts := []options.Lister[options.FindOneOptions]{
 		m.operations.findOneOptions(),
 	}
@@ -82,6 +87,10 @@ func (m *MongORM[T]) First(
 // any necessary timestamps and executes any defined hooks before and after the save
 // operation. It returns an error if the operation fails.
 //
+// When the document was loaded through First or a cursor, Save compares the schema with
+// the loaded snapshot and only writes the changed fields ($set/$unset), matching the
+// document by its primary key. If nothing changed, no write is sent.
+//
 // Example usage:
 //
 //	err := mongormInstance.Save(ctx)
@@ -93,12 +102,14 @@ func (m *MongORM[T]) First(
 func (m *MongORM[T]) Save(
 	ctx context.Context,
 	opts ...options.Lister[options.FindOneAndUpdateOptions],
-) error {
+) (err error) {
+	ctx, telemetry := m.startOperation(ctx, "Save")
+	defer func() { telemetry.end(int64(1), err) }()
+
 	if err := m.ensureReady(); err != nil {
 		return err
 	}
 
-	schema := any(m.schema)
 	hasExplicitUpdate := len(m.operations.update) > 0
 	m.clearModified()
 
@@ -107,14 +118,28 @@ func (m *MongORM[T]) Save(
 		return err
 	}
 
+	// A document loaded by First (or a cursor) is updated by its primary key with only
+	// the fields that changed since the load, instead of using its fields as filters.
+	usesSnapshot := m.hasSnapshotFor(id)
+	if usesSnapshot {
+		filter, _, err = m.withPrimaryFilters()
+		if err != nil {
+			return err
+		}
+
+		if err := m.applySnapshotChanges(); err != nil {
+			return err
+		}
+	}
+
 	hasSelector := len(filter) > 0
 
-	if hasSelector && (hasExplicitUpdate || len(m.operations.query) > 0) {
+	if hasSelector && (hasExplicitUpdate || len(m.operations.query) > 0 || usesSnapshot) {
 		m.operations.fixUpdate()
 		m.rebuildModifiedFromUpdate(m.operations.update)
 
-		if hook, ok := schema.(BeforeSaveHook[T]); ok {
-			if err := hook.BeforeSave(m, &filter); err != nil {
+		if hook, ok := m.beforeSaveHook(); ok {
+			if err := hook(ctx, m, &filter); err != nil {
 				return err
 			}
 
@@ -123,6 +148,14 @@ func (m *MongORM[T]) Save(
 			m.rebuildModifiedFromUpdate(m.operations.update)
 		}
 
+		if usesSnapshot && len(m.operations.update) == 0 {
+			// Nothing changed since the document was loaded.
+			if hook, ok := m.afterSaveHook(); ok {
+				return hook(ctx, m)
+			}
+			return nil
+		}
+
 		optimisticLockEnabled := false
 		if id != nil {
 			optimisticLockEnabled, err = m.applyOptimisticLock(&filter, &m.operations.update)
@@ -158,8 +191,8 @@ func (m *MongORM[T]) Save(
 			return err
 		}
 	} else {
-		if hook, ok := schema.(BeforeSaveHook[T]); ok {
-			if err := hook.BeforeSave(m, nil); err != nil {
+		if hook, ok := m.beforeSaveHook(); ok {
+			if err := hook(ctx, m, nil); err != nil {
 				return err
 			}
 		}
@@ -176,8 +209,8 @@ func (m *MongORM[T]) Save(
 		}
 	}
 
-	if hook, ok := schema.(AfterSaveHook[T]); ok {
-		if err := hook.AfterSave(m); err != nil {
+	if hook, ok := m.afterSaveHook(); ok {
+		if err := hook(ctx, m); err != nil {
 			return err
 		}
 	}
@@ -300,7 +333,10 @@ func splitBSONPath(path string) []string {
 func (m *MongORM[T]) FindOneAndUpdate(
 	ctx context.Context,
 	opts ...options.Lister[options.FindOneAndUpdateOptions],
-) error {
+) (err error) {
+	ctx, telemetry := m.startOperation(ctx, "FindOneAndUpdate")
+	defer func() { telemetry.end(int64(1), err) }()
+
 	if err := m.ensureReady(); err != nil {
 		return err
 	}
@@ -317,6 +353,8 @@ func (m *MongORM[T]) FindOneAndUpdate(
 		return configErrorf("findOneAndUpdate requires a filter or primary key")
 	}
 
+	filter = m.withSoftDeleteScope(filter, softDeleteScopeWithoutTrashed)
+
 	m.rebuildModifiedFromUpdate(m.operations.update)
 
 	optimisticLockEnabled := false
@@ -356,6 +394,9 @@ func (m *MongORM[T]) FindOneAndUpdate(
 // document is deleted. It returns an error if the operation fails or if no document
 // matches the query criteria.
 //
+// When the model has a `soft_delete` field, Delete sets that field to the current time
+// instead of removing the document. Use ForceDelete to remove it permanently.
+//
 // Example usage:
 //
 //	err := mongormInstance.Delete(ctx)
@@ -366,7 +407,10 @@ func (m *MongORM[T]) FindOneAndUpdate(
 //	}
 func (m *MongORM[T]) Delete(
 	ctx context.Context,
-) error {
+) (err error) {
+	ctx, telemetry := m.startOperation(ctx, "Delete")
+	defer func() { telemetry.end(int64(1), err) }()
+
 	if err := m.ensureReady(); err != nil {
 		return err
 	}
@@ -376,5 +420,10 @@ func (m *MongORM[T]) Delete(
 		return err
 	}
 
+	if fieldName, ok := m.softDeleteFieldName(); ok {
+		filter = m.withSoftDeleteScope(filter, softDeleteScopeWithoutTrashed)
+		return m.softDeleteOne(ctx, fieldName, &filter)
+	}
+
 	return m.deleteOne(ctx, &filter)
 }
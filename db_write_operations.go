@@ -0,0 +1,647 @@
+package mongorm
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"slices"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// WriteResult reports the outcome of Create, Update and Upsert.
+//
+// Inserted is true when a new document was written. Matched is true when an existing
+// document matched the filter, and Modified is true when that document actually changed.
+// ID holds the primary key of the written document.
+type WriteResult struct {
+	Inserted bool `json:"inserted"`
+	Matched  bool `json:"matched"`
+	Modified bool `json:"modified"`
+	ID       any  `json:"id,omitempty"`
+}
+
+// Create always inserts the schema as a new document, regardless of its primary key or any
+// accumulated filters. Update operators added with Set()/SetOnInsert()/Unset() are applied
+// to the document before insert. It runs the BeforeSave, BeforeCreate, AfterCreate and
+// AfterSave hooks and returns ErrDuplicateKey if a document with the same key exists.
+//
+// Example usage:
+//
+//	res, err := mongorm.New(&ToDo{Text: mongorm.String("Buy milk")}).Create(ctx)
+//	if err != nil {
+//	    // Handle error
+//	}
+//	fmt.Println(res.ID)
+func (m *MongORM[T]) Create(ctx context.Context) (result *WriteResult, err error) {
+	ctx, telemetry := m.startOperation(ctx, "Create")
+	defer func() { telemetry.end(result, err) }()
+
+	if err := m.ensureReady(); err != nil {
+		return nil, err
+	}
+
+	m.clearModified()
+
+	if hook, ok := m.beforeSaveHook(); ok {
+		if err := hook(ctx, m, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	m.operations.fixUpdate()
+	if len(m.operations.update) > 0 {
+		if err := m.applyUpdateOpsToSchemaForInsert(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := m.insertOne(ctx); err != nil {
+		return nil, err
+	}
+
+	if hook, ok := m.afterSaveHook(); ok {
+		if err := hook(ctx, m); err != nil {
+			return nil, err
+		}
+	}
+
+	id, _ := m.primaryValue()
+
+	return &WriteResult{Inserted: true, ID: id}, nil
+}
+
+// Update applies the accumulated update operators to a single existing document and never
+// inserts. The document is selected like in Save: by primary key, accumulated filters and
+// non-empty schema fields, or, for a document loaded with First, by primary key with the
+// changed fields as the update. It returns ErrNotFound when no document matches (or
+// ErrOptimisticLockConflict when the version is stale) and decodes the updated document
+// back into the schema. It runs the BeforeSave, BeforeUpdate, AfterUpdate and AfterSave
+// hooks.
+//
+// Example usage:
+//
+//	res, err := mongorm.New(&ToDo{}).
+//	    Where(ToDoFields.ID.Eq(id)).
+//	    Set(&ToDo{Done: mongorm.Bool(true)}).
+//	    Update(ctx)
+//	if errors.Is(err, mongorm.ErrNotFound) {
+//	    // Nothing to update
+//	}
+func (m *MongORM[T]) Update(
+	ctx context.Context,
+	opts ...options.Lister[options.UpdateOneOptions],
+) (result *WriteResult, err error) {
+	ctx, telemetry := m.startOperation(ctx, "Update")
+	defer func() { telemetry.end(result, err) }()
+
+	if err := m.ensureReady(); err != nil {
+		return nil, err
+	}
+
+	m.clearModified()
+
+	filter, id, err := m.withPrimaryAndSchemaFilters()
+	if err != nil {
+		return nil, err
+	}
+
+	usesSnapshot := m.hasSnapshotFor(id)
+	if usesSnapshot {
+		filter, _, err = m.withPrimaryFilters()
+		if err != nil {
+			return nil, err
+		}
+
+		if err := m.applySnapshotChanges(); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(filter) == 0 {
+		return nil, configErrorf("update requires a filter or primary key")
+	}
+
+	filter = m.withSoftDeleteScope(filter, softDeleteScopeWithoutTrashed)
+
+	m.operations.fixUpdate()
+	m.rebuildModifiedFromUpdate(m.operations.update)
+
+	if hook, ok := m.beforeSaveHook(); ok {
+		if err := hook(ctx, m, &filter); err != nil {
+			return nil, err
+		}
+		m.operations.fixUpdate()
+		m.rebuildModifiedFromUpdate(m.operations.update)
+	}
+
+	if usesSnapshot && len(m.operations.update) == 0 {
+		// Nothing changed since the document was loaded, like in Save.
+		if hook, ok := m.afterSaveHook(); ok {
+			if err := hook(ctx, m); err != nil {
+				return nil, err
+			}
+		}
+
+		return &WriteResult{Matched: true, ID: id}, nil
+	}
+
+	if len(m.operations.update) == 0 {
+		return nil, configErrorf("no update operations specified")
+	}
+
+	optimisticLockEnabled := false
+	if id != nil {
+		optimisticLockEnabled, err = m.applyOptimisticLock(&filter, &m.operations.update)
+		if err != nil {
+			return nil, err
+		}
+		m.operations.fixUpdate()
+		m.rebuildModifiedFromUpdate(m.operations.update)
+	}
+
+	if hook, ok := m.beforeUpdateHook(); ok {
+		if err := hook(ctx, m, &filter, &m.operations.update); err != nil {
+			return nil, err
+		}
+		m.operations.fixUpdate()
+		m.rebuildModifiedFromUpdate(m.operations.update)
+	}
+
+	m.applyTimestampsToUpdateDoc(&m.operations.update)
+	m.operations.fixUpdate()
+	m.rebuildModifiedFromUpdate(m.operations.update)
+
+	_, primaryFieldName, err := m.getFieldByTag(ModelTagPrimary)
+	if err != nil {
+		return nil, err
+	}
+
+	findOpts, err := findOneAndUpdateOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Update the first matching document atomically and keep its previous version. Its
+	// primary key pins the reload, even when the update changes the fields used to select
+	// the document, and comparing both versions, apart from the fields the update
+	// maintains itself, tells whether it was modified.
+	previous, err := m.info.collection.FindOneAndUpdate(
+		ctx,
+		filter,
+		m.operations.update,
+		findOpts.SetUpsert(false).SetReturnDocument(options.Before),
+	).Raw()
+	if err != nil {
+		return nil, m.withDuplicateKeyFields(mapUpdateOneError(err, optimisticLockEnabled))
+	}
+
+	if id == nil {
+		var target bson.M
+		if err := bson.Unmarshal(previous, &target); err != nil {
+			return nil, err
+		}
+		id = target[primaryFieldName]
+	}
+
+	reloaded := m.info.collection.FindOne(ctx, bson.M{primaryFieldName: id})
+	current, err := reloaded.Raw()
+	if err != nil {
+		return nil, normalizeError(err)
+	}
+
+	var doc T
+	if err := reloaded.Decode(&doc); err != nil {
+		return nil, normalizeError(err)
+	}
+
+	if err := m.applySchema(ctx, &doc); err != nil {
+		return nil, err
+	}
+
+	if hook, ok := m.afterUpdateHook(); ok {
+		if err := hook(ctx, m); err != nil {
+			return nil, err
+		}
+	}
+
+	if hook, ok := m.afterSaveHook(); ok {
+		if err := hook(ctx, m); err != nil {
+			return nil, err
+		}
+	}
+
+	return &WriteResult{
+		Matched:  true,
+		Modified: documentsDiffer(previous, current, m.maintainedFieldNames()...),
+		ID:       id,
+	}, nil
+}
+
+// maintainedFieldNames returns the names of the updated_at timestamp and version fields,
+// which every update changes on its own.
+//
+// > NOTE: This method is internal only.
+func (m *MongORM[T]) maintainedFieldNames() []string {
+	names := []string{}
+	for _, tag := range []ModelTags{ModelTagTimestampUpdatedAt, ModelTagVersion} {
+		if _, name, err := m.getFieldByTag(tag); err == nil {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+// documentsDiffer reports whether a and b differ in any top-level field other than
+// ignored.
+//
+// > NOTE: This function is internal only.
+func documentsDiffer(a, b bson.Raw, ignored ...string) bool {
+	strip := func(doc bson.Raw) []bson.RawElement {
+		elements, err := doc.Elements()
+		if err != nil {
+			return nil
+		}
+
+		return slices.DeleteFunc(elements, func(element bson.RawElement) bool {
+			return slices.Contains(ignored, element.Key())
+		})
+	}
+
+	return !slices.EqualFunc(strip(a), strip(b), func(x, y bson.RawElement) bool {
+		return bytes.Equal(x, y)
+	})
+}
+
+// findOneAndUpdateOptions converts the UpdateOne options accepted by Update to the
+// FindOneAndUpdate options it runs with.
+//
+// > NOTE: This function is internal only.
+func findOneAndUpdateOptions(
+	opts []options.Lister[options.UpdateOneOptions],
+) (*options.FindOneAndUpdateOptionsBuilder, error) {
+	args := &options.UpdateOneOptions{}
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+
+		for _, setter := range opt.List() {
+			if err := setter(args); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	findOpts := options.FindOneAndUpdate()
+	if args.ArrayFilters != nil {
+		findOpts.SetArrayFilters(args.ArrayFilters)
+	}
+	if args.BypassDocumentValidation != nil {
+		findOpts.SetBypassDocumentValidation(*args.BypassDocumentValidation)
+	}
+	if args.Collation != nil {
+		findOpts.SetCollation(args.Collation)
+	}
+	if args.Comment != nil {
+		findOpts.SetComment(args.Comment)
+	}
+	if args.Hint != nil {
+		findOpts.SetHint(args.Hint)
+	}
+	if args.Let != nil {
+		findOpts.SetLet(args.Let)
+	}
+	if args.Sort != nil {
+		findOpts.SetSort(args.Sort)
+	}
+
+	return findOpts, nil
+}
+
+// Upsert writes the schema to the document identified by keyFields, inserting it when no
+// such document exists. The filter is built from the schema values of keyFields (plus any
+// accumulated Where() filters); without keyFields the primary key and accumulated filters
+// are used. Every other field of the encoded schema is written with $set, while the
+// primary key, readonly fields and the created_at timestamp are only written on insert.
+// Accumulated update operators are merged in and take precedence. Sequence fields are not
+// assigned by Upsert.
+//
+// It runs BeforeSave and BeforeUpdate with the filter and update document, like the upsert
+// branch of Save, since whether a document is inserted is only known after the write. It
+// then runs AfterCreate or AfterUpdate depending on the outcome, then AfterSave, and
+// decodes the stored document back into the schema.
+//
+// Example usage:
+//
+//	user := &User{Email: mongorm.String("a@example.com"), Name: mongorm.String("Ann")}
+//	res, err := mongorm.New(user).Upsert(ctx, UserFields.Email)
+//	if err != nil {
+//	    // Handle error
+//	}
+//	if res.Inserted {
+//	    // New user
+//	}
+func (m *MongORM[T]) Upsert(ctx context.Context, keyFields ...Field) (result *WriteResult, err error) {
+	ctx, telemetry := m.startOperation(ctx, "Upsert")
+	defer func() { telemetry.end(result, err) }()
+
+	if err := m.ensureReady(); err != nil {
+		return nil, err
+	}
+
+	m.clearModified()
+
+	filter, err := m.upsertFilter(keyFields)
+	if err != nil {
+		return nil, err
+	}
+
+	// A generated primary key is only written by $setOnInsert, so it identifies the
+	// document only when the upsert inserts one.
+	_, hadID := m.primaryValue()
+	if err := m.generatePrimaryID(); err != nil {
+		return nil, err
+	}
+	_, hasID := m.primaryValue()
+	generatedID := !hadID && hasID
+
+	update, err := m.upsertUpdate(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	m.operations.update = update
+	m.operations.fixUpdate()
+	m.rebuildModifiedFromUpdate(m.operations.update)
+
+	if hook, ok := m.beforeSaveHook(); ok {
+		if err := hook(ctx, m, &filter); err != nil {
+			return nil, err
+		}
+		m.operations.fixUpdate()
+		m.rebuildModifiedFromUpdate(m.operations.update)
+	}
+
+	if hook, ok := m.beforeUpdateHook(); ok {
+		if err := hook(ctx, m, &filter, &m.operations.update); err != nil {
+			return nil, err
+		}
+		m.operations.fixUpdate()
+		m.rebuildModifiedFromUpdate(m.operations.update)
+	}
+
+	if len(m.operations.update) == 0 {
+		return nil, configErrorf("no update operations specified")
+	}
+
+	res, err := m.info.collection.UpdateOne(
+		ctx,
+		filter,
+		m.operations.update,
+		options.UpdateOne().SetUpsert(true),
+	)
+	if err != nil {
+		return nil, m.normalizeWriteError(err)
+	}
+
+	_, primaryFieldName, err := m.getFieldByTag(ModelTagPrimary)
+	if err != nil {
+		return nil, err
+	}
+
+	result = &WriteResult{
+		Inserted: res.UpsertedID != nil,
+		Matched:  res.MatchedCount > 0,
+		Modified: res.ModifiedCount > 0,
+	}
+
+	reloadFilter := filter
+	if res.UpsertedID != nil {
+		reloadFilter = bson.M{primaryFieldName: res.UpsertedID}
+	} else if generatedID {
+		if err := m.clearPrimaryValue(); err != nil {
+			return nil, err
+		}
+	} else if id, ok := m.primaryValue(); ok {
+		reloadFilter = bson.M{primaryFieldName: id}
+	}
+
+	if err := m.reloadSchema(ctx, reloadFilter); err != nil {
+		return nil, err
+	}
+
+	result.ID, _ = m.primaryValue()
+
+	if result.Inserted {
+		if hook, ok := m.afterCreateHook(); ok {
+			if err := hook(ctx, m); err != nil {
+				return nil, err
+			}
+		}
+	} else {
+		if hook, ok := m.afterUpdateHook(); ok {
+			if err := hook(ctx, m); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if hook, ok := m.afterSaveHook(); ok {
+		if err := hook(ctx, m); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// upsertFilter builds the filter used by Upsert from the key fields' schema values and the
+// accumulated query, falling back to the primary key when no key fields are given.
+//
+// > NOTE: This method is internal only.
+func (m *MongORM[T]) upsertFilter(keyFields []Field) (bson.M, error) {
+	if len(keyFields) == 0 {
+		filter, _, err := m.withPrimaryFilters()
+		if err != nil {
+			return nil, err
+		}
+
+		if len(filter) == 0 {
+			return nil, configErrorf("upsert requires key fields, a filter or primary key")
+		}
+
+		return filter, nil
+	}
+
+	m.operations.fixQuery()
+	filter := bson.M{}
+	for key, value := range m.operations.query {
+		filter[key] = value
+	}
+
+	for _, field := range keyFields {
+		if field == nil {
+			return nil, configErrorf("upsert key field cannot be nil")
+		}
+
+		name := field.BSONName()
+		value, ok := m.schemaValueByPath(name)
+		if !ok || value == nil {
+			return nil, configErrorf("upsert key field %s has no value on the schema", name)
+		}
+
+		filter[name] = value
+	}
+
+	return filter, nil
+}
+
+// upsertUpdate encodes the schema into the update document used by Upsert. Fields used as
+// equality keys in filter are left to the upsert itself; primary, readonly and created_at
+// fields go to $setOnInsert; the version field is incremented; everything else is $set.
+//
+// > NOTE: This method is internal only.
+func (m *MongORM[T]) upsertUpdate(filter bson.M) (bson.M, error) {
+	doc, err := snapshotDocument(m.schema)
+	if err != nil {
+		return nil, err
+	}
+
+	m.operations.fixUpdate()
+	explicit := map[string]struct{}{}
+	for _, value := range m.operations.update {
+		for _, path := range extractFieldPaths(value) {
+			explicit[path] = struct{}{}
+		}
+	}
+
+	update := bson.M{}
+	for operator, value := range m.operations.update {
+		update[operator] = value
+	}
+
+	set := bson.M{}
+	setOnInsert := bson.M{}
+	inc := bson.M{}
+
+	modelType := reflect.TypeFor[T]()
+	for i := 0; i < modelType.NumField(); i++ {
+		fieldType := modelType.Field(i)
+		if fieldType.PkgPath != "" {
+			continue
+		}
+
+		name := parseBSONName(fieldType.Tag.Get("bson"), fieldType.Name)
+		value, present := doc[name]
+
+		switch {
+		case doesModelIncludeAnyModelFlags(fieldType.Tag, string(ModelTagVersion)):
+			inc[name] = int64(1)
+		case doesModelIncludeAnyModelFlags(fieldType.Tag, string(ModelTagTimestampCreatedAt)):
+			if !m.options.Timestamps {
+				if present {
+					setOnInsert[name] = value
+				}
+				continue
+			}
+			if present && timestampFieldHasValue(value) {
+				setOnInsert[name] = value
+			} else {
+				setOnInsert[name] = time.Now()
+			}
+		case doesModelIncludeAnyModelFlags(
+			fieldType.Tag,
+			string(ModelTagPrimary),
+			string(ModelTagReadonly),
+		):
+			if present {
+				setOnInsert[name] = value
+			}
+		default:
+			if present {
+				set[name] = value
+			}
+		}
+	}
+
+	assign := func(operator string, values bson.M) {
+		if len(values) == 0 {
+			return
+		}
+
+		target, ok := update[operator].(bson.M)
+		if !ok || target == nil {
+			target = bson.M{}
+		}
+
+		for path, value := range values {
+			if _, isKey := filter[path]; isKey {
+				continue
+			}
+			if pathOverlapsAny(path, explicit) {
+				continue
+			}
+			target[path] = value
+		}
+
+		update[operator] = target
+	}
+
+	assign("$set", set)
+	assign("$setOnInsert", setOnInsert)
+	assign("$inc", inc)
+
+	m.applyTimestampsToUpdateDoc(&update)
+
+	return update, nil
+}
+
+// primaryValue returns the primary key held by the schema, if any.
+//
+// > NOTE: This method is internal only.
+func (m *MongORM[T]) primaryValue() (any, bool) {
+	if m == nil || m.schema == nil {
+		return nil, false
+	}
+
+	primaryFieldGoName, _, err := m.getFieldByTag(ModelTagPrimary)
+	if err != nil {
+		return nil, false
+	}
+
+	return primaryKeyValue(reflect.ValueOf(m.schema).Elem().FieldByName(primaryFieldGoName))
+}
+
+// clearPrimaryValue resets the primary key held by the schema to its zero value.
+//
+// > NOTE: This method is internal only.
+func (m *MongORM[T]) clearPrimaryValue() error {
+	primaryFieldGoName, _, err := m.getFieldByTag(ModelTagPrimary)
+	if err != nil {
+		return err
+	}
+
+	field := reflect.ValueOf(m.schema).Elem().FieldByName(primaryFieldGoName)
+	if !field.CanSet() {
+		return configErrorf("primary field must be exported and settable")
+	}
+	field.SetZero()
+
+	return nil
+}
+
+// reloadSchema reads the document matching filter and applies it to the schema.
+//
+// > NOTE: This method is internal only.
+func (m *MongORM[T]) reloadSchema(ctx context.Context, filter bson.M) error {
+	var doc T
+	if err := m.info.collection.FindOne(ctx, filter).Decode(&doc); err != nil {
+		return normalizeError(err)
+	}
+
+	return m.applySchema(ctx, &doc)
+}
@@ -3,8 +3,12 @@ package mongorm
 import (
 	"errors"
 	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
 	"strings"
 
+	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 )
 
@@ -14,6 +18,9 @@ var (
 	ErrInvalidConfig          = errors.New("mongorm: invalid configuration")
 	ErrTransactionUnsupported = errors.New("mongorm: transaction unsupported")
 	ErrOptimisticLockConflict = errors.New("mongorm: optimistic lock conflict")
+	ErrNotAttempted           = errors.New("mongorm: write not attempted")
+	ErrMigrationLocked        = errors.New("mongorm: migrations locked by another process")
+	ErrMigrationChanged       = errors.New("mongorm: applied migration changed")
 )
 
 func normalizeError(err error) error {
@@ -26,7 +33,12 @@ func normalizeError(err error) error {
 	}
 
 	if mongo.IsDuplicateKeyError(err) {
-		return errors.Join(ErrDuplicateKey, err)
+		var dup *DuplicateKeyError
+		if errors.As(err, &dup) {
+			return err
+		}
+
+		return newDuplicateKeyError(err)
 	}
 
 	if IsTransactionUnsupported(err) {
@@ -77,3 +89,269 @@ func mapUpdateOneError(err error, optimisticLockEnabled bool) error {
 
 	return normalizeError(err)
 }
+
+// DuplicateKeyError describes a write rejected by a unique index. It matches
+// ErrDuplicateKey with errors.Is and unwraps to the driver error.
+//
+// Example usage:
+//
+//	var dup *mongorm.DuplicateKeyError
+//	if errors.As(err, &dup) && slices.Contains(dup.Fields, "Email") {
+//	    // Report "email already taken"
+//	}
+type DuplicateKeyError struct {
+	// Index is the name of the unique index, such as "email_1".
+	Index string
+	// KeyPattern is the key specification of the index, such as {email: 1}.
+	KeyPattern bson.D
+	// KeyValues holds the conflicting values by BSON field name.
+	KeyValues bson.D
+	// Fields holds the Go struct field of every indexed field, in KeyPattern order.
+	// Nested fields are joined with dots; fields that do not map to the model keep
+	// their BSON name.
+	Fields []string
+	// Err is the error returned by the driver.
+	Err error
+}
+
+func (e *DuplicateKeyError) Error() string {
+	if e.Index == "" {
+		return fmt.Sprintf("%s: %v", ErrDuplicateKey, e.Err)
+	}
+
+	if len(e.KeyValues) == 0 {
+		return fmt.Sprintf("%s on index %s", ErrDuplicateKey, e.Index)
+	}
+
+	values := make([]string, len(e.KeyValues))
+	for i, value := range e.KeyValues {
+		values[i] = fmt.Sprintf("%s: %v", value.Key, value.Value)
+	}
+
+	return fmt.Sprintf("%s on index %s (%s)", ErrDuplicateKey, e.Index, strings.Join(values, ", "))
+}
+
+// Is reports whether target is ErrDuplicateKey.
+func (e *DuplicateKeyError) Is(target error) bool {
+	return target == ErrDuplicateKey
+}
+
+func (e *DuplicateKeyError) Unwrap() error {
+	return e.Err
+}
+
+// duplicateKeyIndexPattern extracts the index name from a duplicate key error message.
+var duplicateKeyIndexPattern = regexp.MustCompile(`index: (\S+)`)
+
+// newDuplicateKeyError builds a DuplicateKeyError from a driver duplicate key error.
+// The index name comes from the error message; the key pattern and values come from the
+// keyPattern and keyValue fields the server reports with the error.
+//
+// > NOTE: This function is internal only.
+func newDuplicateKeyError(err error) *DuplicateKeyError {
+	dup := &DuplicateKeyError{Err: err}
+
+	var raw bson.Raw
+	var message string
+
+	var writeException mongo.WriteException
+	var bulkException mongo.BulkWriteException
+	var writeError mongo.WriteError
+	var commandError mongo.CommandError
+	switch {
+	case errors.As(err, &writeException):
+		for _, writeErr := range writeException.WriteErrors {
+			if mongo.IsDuplicateKeyError(writeErr) {
+				raw, message = writeErr.Raw, writeErr.Message
+				break
+			}
+		}
+	case errors.As(err, &bulkException):
+		for _, writeErr := range bulkException.WriteErrors {
+			if mongo.IsDuplicateKeyError(writeErr.WriteError) {
+				raw, message = writeErr.Raw, writeErr.Message
+				break
+			}
+		}
+	case errors.As(err, &writeError):
+		raw, message = writeError.Raw, writeError.Message
+	case errors.As(err, &commandError):
+		raw, message = commandError.Raw, commandError.Message
+	}
+
+	if match := duplicateKeyIndexPattern.FindStringSubmatch(message); match != nil {
+		dup.Index = match[1]
+	}
+
+	if pattern, ok := raw.Lookup("keyPattern").DocumentOK(); ok {
+		_ = bson.Unmarshal(pattern, &dup.KeyPattern)
+	}
+	if values, ok := raw.Lookup("keyValue").DocumentOK(); ok {
+		_ = bson.Unmarshal(values, &dup.KeyValues)
+	}
+
+	return dup
+}
+
+// resolveDuplicateKeyFields fills the Fields of a DuplicateKeyError in err from the struct
+// type t.
+//
+// > NOTE: This function is internal only.
+func resolveDuplicateKeyFields(err error, t reflect.Type) {
+	var dup *DuplicateKeyError
+	if !errors.As(err, &dup) || dup.Fields != nil {
+		return
+	}
+
+	keys := dup.KeyPattern
+	if len(keys) == 0 {
+		keys = dup.KeyValues
+	}
+
+	fields := make([]string, len(keys))
+	for i, key := range keys {
+		fields[i] = structFieldPath(t, key.Key)
+	}
+
+	dup.Fields = fields
+}
+
+// structFieldPath maps a dotted BSON path to the matching dotted Go struct field path of
+// t. Segments that do not resolve keep the remaining BSON path.
+//
+// > NOTE: This function is internal only.
+func structFieldPath(t reflect.Type, path string) string {
+	segments := strings.Split(path, ".")
+	names := make([]string, 0, len(segments))
+
+	for i, segment := range segments {
+		t = dereferenceType(t)
+		for t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+			t = dereferenceType(t.Elem())
+		}
+
+		if t.Kind() != reflect.Struct {
+			return strings.Join(append(names, segments[i:]...), ".")
+		}
+
+		index := slices.IndexFunc(reflect.VisibleFields(t), func(f reflect.StructField) bool {
+			if f.Anonymous || !f.IsExported() {
+				return false
+			}
+
+			tag := f.Tag.Get("bson")
+			if parseBSONName(tag, "") == "" {
+				return strings.ToLower(f.Name) == segment
+			}
+
+			return parseBSONName(tag, "") == segment
+		})
+		if index < 0 {
+			return strings.Join(append(names, segments[i:]...), ".")
+		}
+
+		field := reflect.VisibleFields(t)[index]
+		names = append(names, field.Name)
+		t = field.Type
+	}
+
+	return strings.Join(names, ".")
+}
+
+// WriteErrors reports the failed models of a bulk or multi-document write. Each error
+// matches the sentinel errors with errors.Is, so a duplicate key anywhere in the batch
+// matches ErrDuplicateKey, and unwraps to the driver error.
+//
+// Example usage:
+//
+//	var writeErrs *mongorm.WriteErrors
+//	if errors.As(err, &writeErrs) {
+//	    for _, i := range writeErrs.Indexes() {
+//	        // writeErrs.Errors[i] is the error of models[i]
+//	    }
+//	}
+type WriteErrors struct {
+	// Errors maps the position of each failed model in the slice passed to BulkWrite,
+	// which is the order the models were added to a BulkWriteBuilder, to its error.
+	// Multi-document updates and deletes report their single statement at position 0.
+	Errors map[int]error
+	// Err is the error returned by the driver.
+	Err error
+}
+
+// Indexes returns the positions of the failed models in ascending order.
+func (e *WriteErrors) Indexes() []int {
+	indexes := make([]int, 0, len(e.Errors))
+	for i := range e.Errors {
+		indexes = append(indexes, i)
+	}
+	slices.Sort(indexes)
+
+	return indexes
+}
+
+func (e *WriteErrors) Error() string {
+	failed := make([]string, 0, len(e.Errors))
+	for _, i := range e.Indexes() {
+		failed = append(failed, fmt.Sprintf("model %d: %v", i, e.Errors[i]))
+	}
+
+	return fmt.Sprintf("mongorm: %d write errors: %s", len(e.Errors), strings.Join(failed, "; "))
+}
+
+func (e *WriteErrors) Unwrap() []error {
+	errs := make([]error, 0, len(e.Errors)+1)
+	for _, i := range e.Indexes() {
+		errs = append(errs, e.Errors[i])
+	}
+
+	return append(errs, e.Err)
+}
+
+// normalizeWriteError normalizes the error of a single-document write and maps duplicate
+// key fields back to the model.
+//
+// > NOTE: This method is internal only.
+func (m *MongORM[T]) normalizeWriteError(err error) error {
+	return m.withDuplicateKeyFields(normalizeError(err))
+}
+
+// withDuplicateKeyFields maps the fields of a DuplicateKeyError in err back to the model
+// and returns err.
+//
+// > NOTE: This method is internal only.
+func (m *MongORM[T]) withDuplicateKeyFields(err error) error {
+	resolveDuplicateKeyFields(err, reflect.TypeFor[T]())
+
+	return err
+}
+
+// normalizeWriteErrors normalizes the error of a bulk or multi-document write. Driver
+// errors carrying write errors become a WriteErrors keyed by model position.
+//
+// > NOTE: This method is internal only.
+func (m *MongORM[T]) normalizeWriteErrors(err error) error {
+	var writeErrs []mongo.WriteError
+
+	var writeException mongo.WriteException
+	var bulkException mongo.BulkWriteException
+	switch {
+	case errors.As(err, &writeException):
+		writeErrs = writeException.WriteErrors
+	case errors.As(err, &bulkException):
+		for _, writeErr := range bulkException.WriteErrors {
+			writeErrs = append(writeErrs, writeErr.WriteError)
+		}
+	}
+
+	if len(writeErrs) == 0 {
+		return m.normalizeWriteError(err)
+	}
+
+	result := &WriteErrors{Errors: make(map[int]error, len(writeErrs)), Err: err}
+	for _, writeErr := range writeErrs {
+		result.Errors[writeErr.Index] = m.normalizeWriteError(writeErr)
+	}
+
+	return result
+}
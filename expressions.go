@@ -0,0 +1,172 @@
+package mongorm
+
+import (
+	"strings"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// And combines query expressions with MongoDB's $and operator. Nil or empty expressions
+// are skipped, nested And() groups are flattened, and a single remaining expression is
+// returned as-is. The result is a plain bson.M, so it can be passed to Where(), nested in
+// other combinators, or used in a $match stage.
+//
+// Example usage:
+//
+//	orm.Where(mongorm.And(
+//	    ToDoFields.Done.Eq(false),
+//	    mongorm.Or(ToDoFields.Count.Gte(10), ToDoFields.Text.Reg("^urgent")),
+//	))
+func And(exprs ...bson.M) bson.M {
+	return combineExpressions("$and", true, exprs)
+}
+
+// Or combines query expressions with MongoDB's $or operator. Nil or empty expressions
+// are skipped, nested Or() groups are flattened, and a single remaining expression is
+// returned as-is.
+//
+// Example usage:
+//
+//	orm.Where(mongorm.Or(
+//	    ToDoFields.Text.Eq("A"),
+//	    mongorm.And(ToDoFields.Text.Eq("B"), ToDoFields.Done.Eq(true)),
+//	))
+func Or(exprs ...bson.M) bson.M {
+	return combineExpressions("$or", true, exprs)
+}
+
+// Nor combines query expressions with MongoDB's $nor operator, matching documents for
+// which none of the expressions hold. Nil or empty expressions are skipped.
+//
+// Example usage:
+//
+//	orm.Where(mongorm.Nor(ToDoFields.Done.Eq(true), ToDoFields.Count.Gt(100)))
+func Nor(exprs ...bson.M) bson.M {
+	return combineExpressions("$nor", false, exprs)
+}
+
+// Not negates a query expression. A single-field operator expression such as
+// {"count": {"$gt": 5}} becomes {"count": {"$not": {"$gt": 5}}}; every other expression
+// is wrapped in {"$nor": [expr]}. Like MongoDB's $not, the negation also matches documents
+// where the field is missing. Not(Not(expr)) unwraps back to expr for single-field
+// operator expressions.
+//
+// Example usage:
+//
+//	orm.Where(mongorm.Not(ToDoFields.Text.Reg("^draft")))
+func Not(expr bson.M) bson.M {
+	if len(expr) == 0 {
+		return bson.M{}
+	}
+
+	if len(expr) == 1 {
+		for key, value := range expr {
+			if strings.HasPrefix(key, "$") {
+				break
+			}
+
+			operators, ok := operatorDocument(value)
+			if !ok {
+				break
+			}
+
+			if inner, negated := operators["$not"]; negated && len(operators) == 1 {
+				return bson.M{key: inner}
+			}
+
+			return bson.M{key: bson.M{"$not": operators}}
+		}
+	}
+
+	return bson.M{"$nor": bson.A{expr}}
+}
+
+// combineExpressions builds a logical operator document from the non-empty expressions.
+// When flatten is true, children that are themselves a single-key document with the same
+// operator are merged into the parent clause list.
+//
+// > NOTE: This function is internal only.
+func combineExpressions(operator string, flatten bool, exprs []bson.M) bson.M {
+	clauses := bson.A{}
+
+	for _, expr := range exprs {
+		if len(expr) == 0 {
+			continue
+		}
+
+		if flatten && len(expr) == 1 {
+			if nested, ok := expr[operator]; ok {
+				clauses = append(clauses, expressionClauses(nested)...)
+				continue
+			}
+		}
+
+		clauses = append(clauses, expr)
+	}
+
+	if len(clauses) == 0 {
+		return bson.M{}
+	}
+
+	if flatten && len(clauses) == 1 {
+		if single, ok := clauses[0].(bson.M); ok {
+			return single
+		}
+	}
+
+	return bson.M{operator: clauses}
+}
+
+// expressionClauses normalizes the value of a logical operator ($and, $or, $nor) into
+// a bson.A so it can be extended with further clauses.
+//
+// > NOTE: This function is internal only.
+func expressionClauses(value any) bson.A {
+	switch typed := value.(type) {
+	case bson.A:
+		return append(bson.A{}, typed...)
+	case []any:
+		return append(bson.A{}, typed...)
+	case []bson.M:
+		clauses := make(bson.A, 0, len(typed))
+		for _, clause := range typed {
+			clauses = append(clauses, clause)
+		}
+		return clauses
+	case bson.M:
+		return bson.A{typed}
+	case nil:
+		return bson.A{}
+	default:
+		return bson.A{typed}
+	}
+}
+
+// operatorDocument reports whether value is a query operator document, i.e. a map whose
+// keys all start with "$" (for example {"$gt": 5, "$lt": 10}).
+//
+// > NOTE: This function is internal only.
+func operatorDocument(value any) (bson.M, bool) {
+	var doc bson.M
+
+	switch typed := value.(type) {
+	case bson.M:
+		doc = typed
+	case map[string]any:
+		doc = bson.M(typed)
+	default:
+		return nil, false
+	}
+
+	if len(doc) == 0 {
+		return nil, false
+	}
+
+	for key := range doc {
+		if !strings.HasPrefix(key, "$") {
+			return nil, false
+		}
+	}
+
+	return doc, true
+}
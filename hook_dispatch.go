@@ -0,0 +1,502 @@
+package mongorm
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// modelHook, queryHook, updateHook, updateResultHook and deleteResultHook are the hooks
+// of a schema as called by the operations: the context-aware variant, the plain one, and
+// then the hooks and plugins registered with RegisterHook and RegisterPlugin.
+//
+// > NOTE: These types are internal only.
+type (
+	modelHook[T any]        func(context.Context, *MongORM[T]) error
+	queryHook[T any]        func(context.Context, *MongORM[T], *bson.M) error
+	updateHook[T any]       func(context.Context, *MongORM[T], *bson.M, *bson.M) error
+	updateResultHook[T any] func(context.Context, *MongORM[T], *bson.M, *bson.M, *mongo.UpdateResult) error
+	deleteResultHook[T any] func(context.Context, *MongORM[T], *bson.M, *mongo.DeleteResult) error
+)
+
+// then returns a hook running h and then next. Either may be nil.
+//
+// > NOTE: This method is internal only.
+func (h modelHook[T]) then(next modelHook[T]) modelHook[T] {
+	if h == nil {
+		return next
+	}
+	if next == nil {
+		return h
+	}
+
+	return func(ctx context.Context, m *MongORM[T]) error {
+		if err := h(ctx, m); err != nil {
+			return err
+		}
+		return next(ctx, m)
+	}
+}
+
+// then returns a hook running h and then next. Either may be nil.
+//
+// > NOTE: This method is internal only.
+func (h queryHook[T]) then(next queryHook[T]) queryHook[T] {
+	if h == nil {
+		return next
+	}
+	if next == nil {
+		return h
+	}
+
+	return func(ctx context.Context, m *MongORM[T], query *bson.M) error {
+		if err := h(ctx, m, query); err != nil {
+			return err
+		}
+		return next(ctx, m, query)
+	}
+}
+
+// then returns a hook running h and then next. Either may be nil.
+//
+// > NOTE: This method is internal only.
+func (h updateHook[T]) then(next updateHook[T]) updateHook[T] {
+	if h == nil {
+		return next
+	}
+	if next == nil {
+		return h
+	}
+
+	return func(ctx context.Context, m *MongORM[T], query *bson.M, update *bson.M) error {
+		if err := h(ctx, m, query, update); err != nil {
+			return err
+		}
+		return next(ctx, m, query, update)
+	}
+}
+
+// then returns a hook running h and then next. Either may be nil.
+//
+// > NOTE: This method is internal only.
+func (h updateResultHook[T]) then(next updateResultHook[T]) updateResultHook[T] {
+	if h == nil {
+		return next
+	}
+	if next == nil {
+		return h
+	}
+
+	return func(ctx context.Context, m *MongORM[T], query *bson.M, update *bson.M, result *mongo.UpdateResult) error {
+		if err := h(ctx, m, query, update, result); err != nil {
+			return err
+		}
+		return next(ctx, m, query, update, result)
+	}
+}
+
+// then returns a hook running h and then next. Either may be nil.
+//
+// > NOTE: This method is internal only.
+func (h deleteResultHook[T]) then(next deleteResultHook[T]) deleteResultHook[T] {
+	if h == nil {
+		return next
+	}
+	if next == nil {
+		return h
+	}
+
+	return func(ctx context.Context, m *MongORM[T], query *bson.M, result *mongo.DeleteResult) error {
+		if err := h(ctx, m, query, result); err != nil {
+			return err
+		}
+		return next(ctx, m, query, result)
+	}
+}
+
+// beforeFindHook returns the BeforeFind hooks of the schema, or false when it has none.
+//
+// > NOTE: This method is internal only.
+func (m *MongORM[T]) beforeFindHook() (queryHook[T], bool) {
+	var hook queryHook[T]
+	schema := any(m.schema)
+	if h, ok := schema.(BeforeFindWithContextHook[T]); ok {
+		hook = h.BeforeFindWithContext
+	}
+	if h, ok := schema.(BeforeFindHook[T]); ok {
+		hook = hook.then(func(_ context.Context, m *MongORM[T], query *bson.M) error { return h.BeforeFind(m, query) })
+	}
+	hook = hook.then(m.registeredQueryHook(HookBeforeFind))
+
+	return hook, hook != nil
+}
+
+// afterFindHook returns the AfterFind hooks of the schema, or false when it has none.
+//
+// > NOTE: This method is internal only.
+func (m *MongORM[T]) afterFindHook() (modelHook[T], bool) {
+	hook, _ := afterFindHookOf[T](m.schema)
+	hook = hook.then(m.registeredModelHook(HookAfterFind))
+
+	return hook, hook != nil
+}
+
+// afterFindHookOf returns the AfterFind hooks target implements for models of T, or
+// false when it has none.
+//
+// > NOTE: This function is internal only.
+func afterFindHookOf[T any](target any) (modelHook[T], bool) {
+	var hook modelHook[T]
+	if h, ok := target.(AfterFindWithContextHook[T]); ok {
+		hook = h.AfterFindWithContext
+	}
+	if h, ok := target.(AfterFindHook[T]); ok {
+		hook = hook.then(func(_ context.Context, m *MongORM[T]) error { return h.AfterFind(m) })
+	}
+
+	return hook, hook != nil
+}
+
+// beforeSaveHook returns the BeforeSave hooks of the schema, or false when it has none.
+//
+// > NOTE: This method is internal only.
+func (m *MongORM[T]) beforeSaveHook() (queryHook[T], bool) {
+	var hook queryHook[T]
+	schema := any(m.schema)
+	if h, ok := schema.(BeforeSaveWithContextHook[T]); ok {
+		hook = h.BeforeSaveWithContext
+	}
+	if h, ok := schema.(BeforeSaveHook[T]); ok {
+		hook = hook.then(func(_ context.Context, m *MongORM[T], filter *bson.M) error { return h.BeforeSave(m, filter) })
+	}
+	hook = hook.then(m.registeredQueryHook(HookBeforeSave))
+
+	return hook, hook != nil
+}
+
+// afterSaveHook returns the AfterSave hooks of the schema, or false when it has none.
+//
+// > NOTE: This method is internal only.
+func (m *MongORM[T]) afterSaveHook() (modelHook[T], bool) {
+	var hook modelHook[T]
+	schema := any(m.schema)
+	if h, ok := schema.(AfterSaveWithContextHook[T]); ok {
+		hook = h.AfterSaveWithContext
+	}
+	if h, ok := schema.(AfterSaveHook[T]); ok {
+		hook = hook.then(func(_ context.Context, m *MongORM[T]) error { return h.AfterSave(m) })
+	}
+	hook = hook.then(m.registeredModelHook(HookAfterSave))
+
+	return hook, hook != nil
+}
+
+// beforeCreateHook returns the BeforeCreate hooks of the schema, or false when it has
+// none.
+//
+// > NOTE: This method is internal only.
+func (m *MongORM[T]) beforeCreateHook() (modelHook[T], bool) {
+	var hook modelHook[T]
+	schema := any(m.schema)
+	if h, ok := schema.(BeforeCreateWithContextHook[T]); ok {
+		hook = h.BeforeCreateWithContext
+	}
+	if h, ok := schema.(BeforeCreateHook[T]); ok {
+		hook = hook.then(func(_ context.Context, m *MongORM[T]) error { return h.BeforeCreate(m) })
+	}
+	hook = hook.then(m.registeredModelHook(HookBeforeCreate))
+
+	return hook, hook != nil
+}
+
+// afterCreateHook returns the AfterCreate hooks of the schema, or false when it has none.
+//
+// > NOTE: This method is internal only.
+func (m *MongORM[T]) afterCreateHook() (modelHook[T], bool) {
+	var hook modelHook[T]
+	schema := any(m.schema)
+	if h, ok := schema.(AfterCreateWithContextHook[T]); ok {
+		hook = h.AfterCreateWithContext
+	}
+	if h, ok := schema.(AfterCreateHook[T]); ok {
+		hook = hook.then(func(_ context.Context, m *MongORM[T]) error { return h.AfterCreate(m) })
+	}
+	hook = hook.then(m.registeredModelHook(HookAfterCreate))
+
+	return hook, hook != nil
+}
+
+// beforeUpdateHook returns the BeforeUpdate hooks of the schema, or false when it has
+// none.
+//
+// > NOTE: This method is internal only.
+func (m *MongORM[T]) beforeUpdateHook() (updateHook[T], bool) {
+	var hook updateHook[T]
+	schema := any(m.schema)
+	if h, ok := schema.(BeforeUpdateWithContextHook[T]); ok {
+		hook = h.BeforeUpdateWithContext
+	}
+	if h, ok := schema.(BeforeUpdateHook[T]); ok {
+		hook = hook.then(func(_ context.Context, m *MongORM[T], query *bson.M, update *bson.M) error {
+			return h.BeforeUpdate(m, query, update)
+		})
+	}
+	hook = hook.then(m.registeredUpdateHook(HookBeforeUpdate))
+
+	return hook, hook != nil
+}
+
+// afterUpdateHook returns the AfterUpdate hooks of the schema, or false when it has none.
+//
+// > NOTE: This method is internal only.
+func (m *MongORM[T]) afterUpdateHook() (modelHook[T], bool) {
+	var hook modelHook[T]
+	schema := any(m.schema)
+	if h, ok := schema.(AfterUpdateWithContextHook[T]); ok {
+		hook = h.AfterUpdateWithContext
+	}
+	if h, ok := schema.(AfterUpdateHook[T]); ok {
+		hook = hook.then(func(_ context.Context, m *MongORM[T]) error { return h.AfterUpdate(m) })
+	}
+	hook = hook.then(m.registeredModelHook(HookAfterUpdate))
+
+	return hook, hook != nil
+}
+
+// beforeDeleteHook returns the BeforeDelete hooks of the schema, or false when it has
+// none.
+//
+// > NOTE: This method is internal only.
+func (m *MongORM[T]) beforeDeleteHook() (queryHook[T], bool) {
+	var hook queryHook[T]
+	schema := any(m.schema)
+	if h, ok := schema.(BeforeDeleteWithContextHook[T]); ok {
+		hook = h.BeforeDeleteWithContext
+	}
+	if h, ok := schema.(BeforeDeleteHook[T]); ok {
+		hook = hook.then(func(_ context.Context, m *MongORM[T], query *bson.M) error { return h.BeforeDelete(m, query) })
+	}
+	hook = hook.then(m.registeredQueryHook(HookBeforeDelete))
+
+	return hook, hook != nil
+}
+
+// afterDeleteHook returns the AfterDelete hooks of the schema, or false when it has none.
+//
+// > NOTE: This method is internal only.
+func (m *MongORM[T]) afterDeleteHook() (modelHook[T], bool) {
+	var hook modelHook[T]
+	schema := any(m.schema)
+	if h, ok := schema.(AfterDeleteWithContextHook[T]); ok {
+		hook = h.AfterDeleteWithContext
+	}
+	if h, ok := schema.(AfterDeleteHook[T]); ok {
+		hook = hook.then(func(_ context.Context, m *MongORM[T]) error { return h.AfterDelete(m) })
+	}
+	hook = hook.then(m.registeredModelHook(HookAfterDelete))
+
+	return hook, hook != nil
+}
+
+// beforeUpdateManyHook returns the BeforeUpdateMany hooks of the schema, or false when it
+// has none.
+//
+// > NOTE: This method is internal only.
+func (m *MongORM[T]) beforeUpdateManyHook() (updateHook[T], bool) {
+	var hook updateHook[T]
+	schema := any(m.schema)
+	if h, ok := schema.(BeforeUpdateManyWithContextHook[T]); ok {
+		hook = h.BeforeUpdateManyWithContext
+	}
+	if h, ok := schema.(BeforeUpdateManyHook[T]); ok {
+		hook = hook.then(func(_ context.Context, m *MongORM[T], query *bson.M, update *bson.M) error {
+			return h.BeforeUpdateMany(m, query, update)
+		})
+	}
+	hook = hook.then(m.registeredUpdateHook(HookBeforeUpdateMany))
+
+	return hook, hook != nil
+}
+
+// afterUpdateManyHook returns the AfterUpdateMany hooks of the schema, or false when it
+// has none.
+//
+// > NOTE: This method is internal only.
+func (m *MongORM[T]) afterUpdateManyHook() (updateResultHook[T], bool) {
+	var hook updateResultHook[T]
+	schema := any(m.schema)
+	if h, ok := schema.(AfterUpdateManyWithContextHook[T]); ok {
+		hook = h.AfterUpdateManyWithContext
+	}
+	if h, ok := schema.(AfterUpdateManyHook[T]); ok {
+		hook = hook.then(func(_ context.Context, m *MongORM[T], query *bson.M, update *bson.M, result *mongo.UpdateResult) error {
+			return h.AfterUpdateMany(m, query, update, result)
+		})
+	}
+	hook = hook.then(m.registeredUpdateResultHook(HookAfterUpdateMany))
+
+	return hook, hook != nil
+}
+
+// beforeDeleteManyHook returns the BeforeDeleteMany hooks of the schema, or false when it
+// has none.
+//
+// > NOTE: This method is internal only.
+func (m *MongORM[T]) beforeDeleteManyHook() (queryHook[T], bool) {
+	var hook queryHook[T]
+	schema := any(m.schema)
+	if h, ok := schema.(BeforeDeleteManyWithContextHook[T]); ok {
+		hook = h.BeforeDeleteManyWithContext
+	}
+	if h, ok := schema.(BeforeDeleteManyHook[T]); ok {
+		hook = hook.then(func(_ context.Context, m *MongORM[T], query *bson.M) error { return h.BeforeDeleteMany(m, query) })
+	}
+	hook = hook.then(m.registeredQueryHook(HookBeforeDeleteMany))
+
+	return hook, hook != nil
+}
+
+// afterDeleteManyHook returns the AfterDeleteMany hooks of the schema, or false when it
+// has none.
+//
+// > NOTE: This method is internal only.
+func (m *MongORM[T]) afterDeleteManyHook() (deleteResultHook[T], bool) {
+	var hook deleteResultHook[T]
+	schema := any(m.schema)
+	if h, ok := schema.(AfterDeleteManyWithContextHook[T]); ok {
+		hook = h.AfterDeleteManyWithContext
+	}
+	if h, ok := schema.(AfterDeleteManyHook[T]); ok {
+		hook = hook.then(func(_ context.Context, m *MongORM[T], query *bson.M, result *mongo.DeleteResult) error {
+			return h.AfterDeleteMany(m, query, result)
+		})
+	}
+	hook = hook.then(m.registeredDeleteResultHook(HookAfterDeleteMany))
+
+	return hook, hook != nil
+}
+
+// beforeFinalizeHook returns the BeforeFinalize hooks of the schema, or false when it has
+// none.
+//
+// > NOTE: This method is internal only.
+func (m *MongORM[T]) beforeFinalizeHook() (modelHook[T], bool) {
+	hook, _ := beforeFinalizeHookOf[T](m.schema)
+	hook = hook.then(m.registeredModelHook(HookBeforeFinalize))
+
+	return hook, hook != nil
+}
+
+// beforeFinalizeHookOf returns the BeforeFinalize hooks target implements for models of T, or
+// false when it has none.
+//
+// > NOTE: This function is internal only.
+func beforeFinalizeHookOf[T any](target any) (modelHook[T], bool) {
+	var hook modelHook[T]
+	if h, ok := target.(BeforeFinalizeWithContextHook[T]); ok {
+		hook = h.BeforeFinalizeWithContext
+	}
+	if h, ok := target.(BeforeFinalizeHook[T]); ok {
+		hook = hook.then(func(_ context.Context, m *MongORM[T]) error { return h.BeforeFinalize(m) })
+	}
+
+	return hook, hook != nil
+}
+
+// afterFinalizeHook returns the AfterFinalize hooks of the schema, or false when it has
+// none.
+//
+// > NOTE: This method is internal only.
+func (m *MongORM[T]) afterFinalizeHook() (modelHook[T], bool) {
+	hook, _ := afterFinalizeHookOf[T](m.schema)
+	hook = hook.then(m.registeredModelHook(HookAfterFinalize))
+
+	return hook, hook != nil
+}
+
+// afterFinalizeHookOf returns the AfterFinalize hooks target implements for models of T, or
+// false when it has none.
+//
+// > NOTE: This function is internal only.
+func afterFinalizeHookOf[T any](target any) (modelHook[T], bool) {
+	var hook modelHook[T]
+	if h, ok := target.(AfterFinalizeWithContextHook[T]); ok {
+		hook = h.AfterFinalizeWithContext
+	}
+	if h, ok := target.(AfterFinalizeHook[T]); ok {
+		hook = hook.then(func(_ context.Context, m *MongORM[T]) error { return h.AfterFinalize(m) })
+	}
+
+	return hook, hook != nil
+}
+
+// registeredModelHook, registeredQueryHook, registeredUpdateHook,
+// registeredUpdateResultHook and registeredDeleteResultHook adapt the hooks registered
+// for event with RegisterHook and RegisterPlugin to the operations. They return nil when
+// there are none.
+//
+// > NOTE: These methods are internal only.
+func (m *MongORM[T]) registeredModelHook(event HookEvent) modelHook[T] {
+	// Keep the document the hook was resolved for: AfterDelete runs after the reset.
+	return registeredDocumentHook[T](event, m.schema)
+}
+
+// registeredDocumentHook adapts the hooks registered for event to pass document as
+// HookArgs.Document. It returns nil when there are none.
+//
+// > NOTE: This function is internal only.
+func registeredDocumentHook[T any](event HookEvent, document any) modelHook[T] {
+	hook := registeredHook[T](event)
+	if hook == nil {
+		return nil
+	}
+
+	return func(ctx context.Context, m *MongORM[T]) error {
+		return hook(ctx, m, &HookArgs{Document: document})
+	}
+}
+
+func (m *MongORM[T]) registeredQueryHook(event HookEvent) queryHook[T] {
+	hook := registeredHook[T](event)
+	if hook == nil {
+		return nil
+	}
+
+	return func(ctx context.Context, m *MongORM[T], query *bson.M) error {
+		return hook(ctx, m, &HookArgs{Filter: query})
+	}
+}
+
+func (m *MongORM[T]) registeredUpdateHook(event HookEvent) updateHook[T] {
+	hook := registeredHook[T](event)
+	if hook == nil {
+		return nil
+	}
+
+	return func(ctx context.Context, m *MongORM[T], query *bson.M, update *bson.M) error {
+		return hook(ctx, m, &HookArgs{Filter: query, Update: update})
+	}
+}
+
+func (m *MongORM[T]) registeredUpdateResultHook(event HookEvent) updateResultHook[T] {
+	hook := registeredHook[T](event)
+	if hook == nil {
+		return nil
+	}
+
+	return func(ctx context.Context, m *MongORM[T], query *bson.M, update *bson.M, result *mongo.UpdateResult) error {
+		return hook(ctx, m, &HookArgs{Filter: query, Update: update, UpdateResult: result})
+	}
+}
+
+func (m *MongORM[T]) registeredDeleteResultHook(event HookEvent) deleteResultHook[T] {
+	hook := registeredHook[T](event)
+	if hook == nil {
+		return nil
+	}
+
+	return func(ctx context.Context, m *MongORM[T], query *bson.M, result *mongo.DeleteResult) error {
+		return hook(ctx, m, &HookArgs{Filter: query, DeleteResult: result})
+	}
+}
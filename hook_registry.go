@@ -0,0 +1,198 @@
+package mongorm
+
+import (
+	"context"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// HookEvent names a lifecycle event hooks can be registered for. The values match the
+// names of the hook methods.
+type HookEvent string
+
+// Hook events.
+const (
+	HookBeforeFind       HookEvent = "BeforeFind"
+	HookAfterFind        HookEvent = "AfterFind"
+	HookBeforeSave       HookEvent = "BeforeSave"
+	HookAfterSave        HookEvent = "AfterSave"
+	HookBeforeCreate     HookEvent = "BeforeCreate"
+	HookAfterCreate      HookEvent = "AfterCreate"
+	HookBeforeUpdate     HookEvent = "BeforeUpdate"
+	HookAfterUpdate      HookEvent = "AfterUpdate"
+	HookBeforeDelete     HookEvent = "BeforeDelete"
+	HookAfterDelete      HookEvent = "AfterDelete"
+	HookBeforeUpdateMany HookEvent = "BeforeUpdateMany"
+	HookAfterUpdateMany  HookEvent = "AfterUpdateMany"
+	HookBeforeDeleteMany HookEvent = "BeforeDeleteMany"
+	HookAfterDeleteMany  HookEvent = "AfterDeleteMany"
+	HookBeforeFinalize   HookEvent = "BeforeFinalize"
+	HookAfterFinalize    HookEvent = "AfterFinalize"
+)
+
+// HookArgs carries the arguments of an event to registered hooks and plugins. Fields that
+// the event does not have are nil. Changes made through Filter and Update are sent, like
+// changes made by the matching hook methods.
+type HookArgs struct {
+	// Event is the event being run.
+	Event HookEvent
+	// Collection is the name of the model's collection.
+	Collection string
+	// Document is the schema of the model, a *T. For the load events of FindAllAs and
+	// AggregateAs it is the decoded result, a *R.
+	Document any
+	// Filter is the filter of the Find, Save, Update, Delete and Many events. It is nil
+	// for BeforeSave of an insert.
+	Filter *bson.M
+	// Update is the update document of BeforeUpdate and the UpdateMany events.
+	Update *bson.M
+	// UpdateResult is the result of AfterUpdateMany.
+	UpdateResult *mongo.UpdateResult
+	// DeleteResult is the result of AfterDeleteMany.
+	DeleteResult *mongo.DeleteResult
+}
+
+// HookFunc is a hook registered with RegisterHook.
+type HookFunc[T any] func(ctx context.Context, m *MongORM[T], args *HookArgs) error
+
+// Plugin adds behavior to every model, such as audit stamping, tenant filtering or field
+// encryption. Register it with RegisterPlugin. Hook is called for every event of every
+// model; it can inspect args.Document with reflection and change args.Filter and
+// args.Update.
+//
+// Example usage:
+//
+//	type tenantPlugin struct{}
+//
+//	func (tenantPlugin) Name() string { return "tenant" }
+//
+//	func (tenantPlugin) Hook(ctx context.Context, event mongorm.HookEvent, args *mongorm.HookArgs) error {
+//	    if event == mongorm.HookBeforeFind && args.Filter != nil {
+//	        (*args.Filter)["tenant"] = tenantFrom(ctx)
+//	    }
+//	    return nil
+//	}
+type Plugin interface {
+	// Name identifies the plugin. Registering a plugin with the same name replaces it.
+	Name() string
+	// Hook runs the plugin for event. Returning an error aborts the operation, like a
+	// hook method.
+	Hook(ctx context.Context, event HookEvent, args *HookArgs) error
+}
+
+// hookKey identifies the hooks registered for a model type and event.
+//
+// > NOTE: This type is internal only.
+type hookKey struct {
+	model reflect.Type
+	event HookEvent
+}
+
+var (
+	hooksMu sync.RWMutex
+	hooks   = map[hookKey][]any{}
+	plugins []Plugin
+)
+
+// RegisterHook registers hook to run on event for every model of type T, after the hook
+// methods of the model. Hooks registered for the same type and event run in registration
+// order.
+//
+// Example usage:
+//
+//	func init() {
+//	    mongorm.RegisterHook(mongorm.HookBeforeSave, func(ctx context.Context, m *mongorm.MongORM[ToDo], args *mongorm.HookArgs) error {
+//	        m.Document().UpdatedBy = mongorm.String(actorFrom(ctx))
+//	        return nil
+//	    })
+//	}
+func RegisterHook[T any](event HookEvent, hook HookFunc[T]) {
+	if event == "" || hook == nil {
+		return
+	}
+
+	hooksMu.Lock()
+	defer hooksMu.Unlock()
+
+	key := hookKey{model: reflect.TypeFor[T](), event: event}
+	hooks[key] = append(hooks[key], hook)
+}
+
+// RegisterPlugin registers plugin for every model. Plugins run after the hook methods of
+// the model and the hooks registered with RegisterHook, in registration order.
+// Registering a name that already exists replaces the previous plugin in place.
+func RegisterPlugin(plugin Plugin) {
+	if plugin == nil || strings.TrimSpace(plugin.Name()) == "" {
+		return
+	}
+
+	hooksMu.Lock()
+	defer hooksMu.Unlock()
+
+	for i, registered := range plugins {
+		if registered.Name() == plugin.Name() {
+			plugins[i] = plugin
+			return
+		}
+	}
+
+	plugins = append(plugins, plugin)
+}
+
+// UnregisterPlugin removes the plugin registered under name, if any.
+func UnregisterPlugin(name string) {
+	hooksMu.Lock()
+	defer hooksMu.Unlock()
+
+	for i, registered := range plugins {
+		if registered.Name() == name {
+			plugins = append(plugins[:i:i], plugins[i+1:]...)
+			return
+		}
+	}
+}
+
+// registeredHook returns a hook running the functions registered for T and event and then
+// the plugins, or nil when there are none. The returned hook fills the Event, Collection
+// and Document of args.
+//
+// > NOTE: This function is internal only.
+func registeredHook[T any](event HookEvent) HookFunc[T] {
+	hooksMu.RLock()
+	registered := hooks[hookKey{model: reflect.TypeFor[T](), event: event}]
+	active := slices.Clone(plugins)
+	hooksMu.RUnlock()
+
+	if len(registered) == 0 && len(active) == 0 {
+		return nil
+	}
+
+	return func(ctx context.Context, m *MongORM[T], args *HookArgs) error {
+		args.Event = event
+		if args.Document == nil {
+			args.Document = m.schema
+		}
+		if m.info != nil && m.info.collection != nil {
+			args.Collection = m.info.collection.Name()
+		}
+
+		for _, hook := range registered {
+			if err := hook.(HookFunc[T])(ctx, m, args); err != nil {
+				return err
+			}
+		}
+
+		for _, plugin := range active {
+			if err := plugin.Hook(ctx, event, args); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
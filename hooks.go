@@ -1,6 +1,11 @@
 package mongorm
 
-import "go.mongodb.org/mongo-driver/v2/bson"
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
 
 // BeforeFindHook is called before executing a find operation.
 // It allows you to modify the query or perform any necessary setup.
@@ -19,6 +24,23 @@ type BeforeFindHook[T any] interface {
 	BeforeFind(*MongORM[T], *bson.M) error
 }
 
+// BeforeFindWithContextHook is the context-aware variant of BeforeFindHook. Every hook
+// has such a variant, named after the hook with a WithContext suffix. It receives the
+// context of the operation, so it can read request-scoped values such as the actor,
+// tenant or trace ID, honor cancellation, and run further queries in the caller's
+// transaction session. When a model implements both variants, the context-aware one runs
+// first.
+//
+// Example usage:
+//
+//	func (u *ToDo) BeforeFindWithContext(ctx context.Context, m *MongORM[ToDo], query *bson.M) error {
+//	    (*query)["tenant"] = tenantFrom(ctx)
+//	    return nil
+//	}
+type BeforeFindWithContextHook[T any] interface {
+	BeforeFindWithContext(context.Context, *MongORM[T], *bson.M) error
+}
+
 // AfterFindHook is called after executing a find operation.
 // It allows you to perform any necessary cleanup or post-processing.
 //
@@ -36,6 +58,18 @@ type AfterFindHook[T any] interface {
 	AfterFind(*MongORM[T]) error
 }
 
+// AfterFindWithContextHook is the context-aware variant of AfterFindHook.
+//
+// Example usage:
+//
+//	func (u *ToDo) AfterFindWithContext(ctx context.Context, m *MongORM[ToDo]) error {
+//	    audit.Read(ctx, u.ID)
+//	    return nil
+//	}
+type AfterFindWithContextHook[T any] interface {
+	AfterFindWithContext(context.Context, *MongORM[T]) error
+}
+
 // BeforeSaveHook is called before executing a save operation.
 // It allows you to modify the document or perform any necessary setup.
 //
@@ -53,6 +87,18 @@ type BeforeSaveHook[T any] interface {
 	BeforeSave(*MongORM[T], *bson.M) error
 }
 
+// BeforeSaveWithContextHook is the context-aware variant of BeforeSaveHook.
+//
+// Example usage:
+//
+//	func (u *ToDo) BeforeSaveWithContext(ctx context.Context, m *MongORM[ToDo], filter *bson.M) error {
+//	    u.UpdatedBy = mongorm.String(actorFrom(ctx))
+//	    return nil
+//	}
+type BeforeSaveWithContextHook[T any] interface {
+	BeforeSaveWithContext(context.Context, *MongORM[T], *bson.M) error
+}
+
 // AfterSaveHook is called after executing a save operation.
 // It allows you to perform any necessary cleanup or post-processing.
 //
@@ -70,6 +116,18 @@ type AfterSaveHook[T any] interface {
 	AfterSave(*MongORM[T]) error
 }
 
+// AfterSaveWithContextHook is the context-aware variant of AfterSaveHook. Writes made with
+// ctx join the transaction of the save, if any.
+//
+// Example usage:
+//
+//	func (u *ToDo) AfterSaveWithContext(ctx context.Context, m *MongORM[ToDo]) error {
+//	    return mongorm.New(&AuditLog{Action: mongorm.String("save")}).Save(ctx)
+//	}
+type AfterSaveWithContextHook[T any] interface {
+	AfterSaveWithContext(context.Context, *MongORM[T]) error
+}
+
 // BeforeCreateHook is called before executing a create operation.
 // It allows you to modify the document or perform any necessary setup.
 //
@@ -87,6 +145,18 @@ type BeforeCreateHook[T any] interface {
 	BeforeCreate(*MongORM[T]) error
 }
 
+// BeforeCreateWithContextHook is the context-aware variant of BeforeCreateHook.
+//
+// Example usage:
+//
+//	func (u *ToDo) BeforeCreateWithContext(ctx context.Context, m *MongORM[ToDo]) error {
+//	    u.CreatedBy = mongorm.String(actorFrom(ctx))
+//	    return nil
+//	}
+type BeforeCreateWithContextHook[T any] interface {
+	BeforeCreateWithContext(context.Context, *MongORM[T]) error
+}
+
 // AfterCreateHook is called after executing a create operation.
 // It allows you to perform any necessary cleanup or post-processing.
 //
@@ -104,6 +174,17 @@ type AfterCreateHook[T any] interface {
 	AfterCreate(*MongORM[T]) error
 }
 
+// AfterCreateWithContextHook is the context-aware variant of AfterCreateHook.
+//
+// Example usage:
+//
+//	func (u *ToDo) AfterCreateWithContext(ctx context.Context, m *MongORM[ToDo]) error {
+//	    return events.Publish(ctx, "todo.created", u.ID)
+//	}
+type AfterCreateWithContextHook[T any] interface {
+	AfterCreateWithContext(context.Context, *MongORM[T]) error
+}
+
 // BeforeUpdateHook is called before executing an update operation.
 // It allows you to modify the query, update document, or perform any necessary setup.
 //
@@ -121,6 +202,20 @@ type BeforeUpdateHook[T any] interface {
 	BeforeUpdate(*MongORM[T], *bson.M, *bson.M) error
 }
 
+// BeforeUpdateWithContextHook is the context-aware variant of BeforeUpdateHook.
+//
+// Example usage:
+//
+//	func (u *ToDo) BeforeUpdateWithContext(ctx context.Context, m *MongORM[ToDo], query *bson.M, update *bson.M) error {
+//	    if err := ctx.Err(); err != nil {
+//	        return err
+//	    }
+//	    return nil
+//	}
+type BeforeUpdateWithContextHook[T any] interface {
+	BeforeUpdateWithContext(context.Context, *MongORM[T], *bson.M, *bson.M) error
+}
+
 // AfterUpdateHook is called after executing an update operation.
 // It allows you to perform any necessary cleanup or post-processing.
 //
@@ -138,6 +233,17 @@ type AfterUpdateHook[T any] interface {
 	AfterUpdate(*MongORM[T]) error
 }
 
+// AfterUpdateWithContextHook is the context-aware variant of AfterUpdateHook.
+//
+// Example usage:
+//
+//	func (u *ToDo) AfterUpdateWithContext(ctx context.Context, m *MongORM[ToDo]) error {
+//	    return events.Publish(ctx, "todo.updated", u.ID)
+//	}
+type AfterUpdateWithContextHook[T any] interface {
+	AfterUpdateWithContext(context.Context, *MongORM[T]) error
+}
+
 // BeforeDeleteHook is called before executing a delete operation.
 // It allows you to modify the query or perform any necessary setup.
 //
@@ -155,6 +261,20 @@ type BeforeDeleteHook[T any] interface {
 	BeforeDelete(*MongORM[T], *bson.M) error
 }
 
+// BeforeDeleteWithContextHook is the context-aware variant of BeforeDeleteHook.
+//
+// Example usage:
+//
+//	func (u *ToDo) BeforeDeleteWithContext(ctx context.Context, m *MongORM[ToDo], query *bson.M) error {
+//	    if !canDelete(ctx) {
+//	        return ErrForbidden
+//	    }
+//	    return nil
+//	}
+type BeforeDeleteWithContextHook[T any] interface {
+	BeforeDeleteWithContext(context.Context, *MongORM[T], *bson.M) error
+}
+
 // AfterDeleteHook is called after executing a delete operation.
 // It allows you to perform any necessary cleanup or post-processing.
 //
@@ -172,6 +292,121 @@ type AfterDeleteHook[T any] interface {
 	AfterDelete(*MongORM[T]) error
 }
 
+// AfterDeleteWithContextHook is the context-aware variant of AfterDeleteHook.
+//
+// Example usage:
+//
+//	func (u *ToDo) AfterDeleteWithContext(ctx context.Context, m *MongORM[ToDo]) error {
+//	    return events.Publish(ctx, "todo.deleted", u.ID)
+//	}
+type AfterDeleteWithContextHook[T any] interface {
+	AfterDeleteWithContext(context.Context, *MongORM[T]) error
+}
+
+// BeforeUpdateManyHook is called before SaveMulti updates every document matching the
+// filter, and for every UpdateOne and UpdateMany model of BulkWrite. It may modify the filter and the update document; primary, readonly and
+// created_at fields are still rejected afterwards, and updated_at is set.
+//
+// Example usage:
+//
+//	func (u *ToDo) BeforeUpdateMany(m *MongORM[ToDo], filter *bson.M, update *bson.M) error {
+//	    (*filter)["archived"] = bson.M{"$ne": true}
+//	    return nil
+//	}
+type BeforeUpdateManyHook[T any] interface {
+	BeforeUpdateMany(*MongORM[T], *bson.M, *bson.M) error
+}
+
+// BeforeUpdateManyWithContextHook is the context-aware variant of BeforeUpdateManyHook.
+//
+// Example usage:
+//
+//	func (u *ToDo) BeforeUpdateManyWithContext(ctx context.Context, m *MongORM[ToDo], filter *bson.M, update *bson.M) error {
+//	    (*filter)["tenant"] = tenantFrom(ctx)
+//	    return nil
+//	}
+type BeforeUpdateManyWithContextHook[T any] interface {
+	BeforeUpdateManyWithContext(context.Context, *MongORM[T], *bson.M, *bson.M) error
+}
+
+// AfterUpdateManyHook is called after SaveMulti with the filter and update document that
+// were sent and the result of the update. After BulkWrite it is called for every update
+// model, with the totals of the whole request as the result.
+//
+// Example usage:
+//
+//	func (u *ToDo) AfterUpdateMany(m *MongORM[ToDo], filter *bson.M, update *bson.M, result *mongo.UpdateResult) error {
+//	    log.Printf("updated %d todos", result.ModifiedCount)
+//	    return nil
+//	}
+type AfterUpdateManyHook[T any] interface {
+	AfterUpdateMany(*MongORM[T], *bson.M, *bson.M, *mongo.UpdateResult) error
+}
+
+// AfterUpdateManyWithContextHook is the context-aware variant of AfterUpdateManyHook.
+//
+// Example usage:
+//
+//	func (u *ToDo) AfterUpdateManyWithContext(ctx context.Context, m *MongORM[ToDo], filter *bson.M, update *bson.M, result *mongo.UpdateResult) error {
+//	    return events.Publish(ctx, "todo.updated_many", result.ModifiedCount)
+//	}
+type AfterUpdateManyWithContextHook[T any] interface {
+	AfterUpdateManyWithContext(context.Context, *MongORM[T], *bson.M, *bson.M, *mongo.UpdateResult) error
+}
+
+// BeforeDeleteManyHook is called before DeleteMulti removes (or soft-deletes) every
+// document matching the filter, and for every DeleteOne and DeleteMany model of
+// BulkWrite. It may modify the filter.
+//
+// Example usage:
+//
+//	func (u *ToDo) BeforeDeleteMany(m *MongORM[ToDo], filter *bson.M) error {
+//	    if len(*filter) == 0 {
+//	        return errors.New("refusing to delete every todo")
+//	    }
+//	    return nil
+//	}
+type BeforeDeleteManyHook[T any] interface {
+	BeforeDeleteMany(*MongORM[T], *bson.M) error
+}
+
+// BeforeDeleteManyWithContextHook is the context-aware variant of BeforeDeleteManyHook.
+//
+// Example usage:
+//
+//	func (u *ToDo) BeforeDeleteManyWithContext(ctx context.Context, m *MongORM[ToDo], filter *bson.M) error {
+//	    (*filter)["tenant"] = tenantFrom(ctx)
+//	    return nil
+//	}
+type BeforeDeleteManyWithContextHook[T any] interface {
+	BeforeDeleteManyWithContext(context.Context, *MongORM[T], *bson.M) error
+}
+
+// AfterDeleteManyHook is called after DeleteMulti with the filter that was sent and the
+// result of the delete. After BulkWrite it is called for every delete model, with the
+// totals of the whole request as the result.
+//
+// Example usage:
+//
+//	func (u *ToDo) AfterDeleteMany(m *MongORM[ToDo], filter *bson.M, result *mongo.DeleteResult) error {
+//	    log.Printf("deleted %d todos", result.DeletedCount)
+//	    return nil
+//	}
+type AfterDeleteManyHook[T any] interface {
+	AfterDeleteMany(*MongORM[T], *bson.M, *mongo.DeleteResult) error
+}
+
+// AfterDeleteManyWithContextHook is the context-aware variant of AfterDeleteManyHook.
+//
+// Example usage:
+//
+//	func (u *ToDo) AfterDeleteManyWithContext(ctx context.Context, m *MongORM[ToDo], filter *bson.M, result *mongo.DeleteResult) error {
+//	    return events.Publish(ctx, "todo.deleted_many", result.DeletedCount)
+//	}
+type AfterDeleteManyWithContextHook[T any] interface {
+	AfterDeleteManyWithContext(context.Context, *MongORM[T], *bson.M, *mongo.DeleteResult) error
+}
+
 // BeforeFinalizeHook is called before finalizing the MongORM instance.
 // It allows you to perform any necessary setup or modifications before the instance is finalized.
 //
@@ -189,6 +424,17 @@ type BeforeFinalizeHook[T any] interface {
 	BeforeFinalize(*MongORM[T]) error
 }
 
+// BeforeFinalizeWithContextHook is the context-aware variant of BeforeFinalizeHook.
+//
+// Example usage:
+//
+//	func (u *ToDo) BeforeFinalizeWithContext(ctx context.Context, m *MongORM[ToDo]) error {
+//	    return ctx.Err()
+//	}
+type BeforeFinalizeWithContextHook[T any] interface {
+	BeforeFinalizeWithContext(context.Context, *MongORM[T]) error
+}
+
 // AfterFinalizeHook is called after finalizing the MongORM instance.
 // It allows you to perform any necessary cleanup or post-processing after the instance is finalized.
 //
@@ -205,3 +451,15 @@ type BeforeFinalizeHook[T any] interface {
 type AfterFinalizeHook[T any] interface {
 	AfterFinalize(*MongORM[T]) error
 }
+
+// AfterFinalizeWithContextHook is the context-aware variant of AfterFinalizeHook.
+//
+// Example usage:
+//
+//	func (u *ToDo) AfterFinalizeWithContext(ctx context.Context, m *MongORM[ToDo]) error {
+//	    u.Editable = canEdit(ctx, u)
+//	    return nil
+//	}
+type AfterFinalizeWithContextHook[T any] interface {
+	AfterFinalizeWithContext(context.Context, *MongORM[T]) error
+}
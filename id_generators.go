@@ -0,0 +1,425 @@
+package mongorm
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// IDGenerator produces identifiers for the primary field of a document. When configured,
+// MongORM calls it right before an insert if the primary field is still empty, so the ID
+// is known to the application (and to BeforeCreate hooks) before the document is written.
+//
+// The generated value is assigned to the primary field directly when the types match.
+// String primary fields receive the textual form of the ID (hex for ObjectIDs, canonical
+// form for UUIDs and ULIDs), and custom byte-array types receive a converted value.
+//
+// Example usage:
+//
+//	type ToDo struct {
+//	  ID *string `bson:"_id,omitempty" mongorm:"primary,id:uuidv7"`
+//	}
+//	// OR
+//	orm := mongorm.FromOptions(&ToDo{}, &mongorm.MongORMOptions{
+//	    IDGenerator: mongorm.ULIDGenerator(),
+//	})
+type IDGenerator interface {
+	NewID() (any, error)
+}
+
+// IDGeneratorFunc adapts a plain function to the IDGenerator interface.
+type IDGeneratorFunc func() (any, error)
+
+// NewID calls f().
+func (f IDGeneratorFunc) NewID() (any, error) {
+	return f()
+}
+
+// Built-in generator names that can be used with the `id:<name>` field tag.
+const (
+	IDGeneratorObjectID = "objectid"
+	IDGeneratorUUIDv4   = "uuidv4"
+	IDGeneratorUUIDv7   = "uuidv7"
+	IDGeneratorULID     = "ulid"
+)
+
+var (
+	idGeneratorsMu sync.RWMutex
+	idGenerators   = map[string]IDGenerator{
+		IDGeneratorObjectID: ObjectIDGenerator(),
+		IDGeneratorUUIDv4:   UUIDv4Generator(),
+		IDGeneratorUUIDv7:   UUIDv7Generator(),
+		IDGeneratorULID:     ULIDGenerator(),
+	}
+)
+
+// RegisterIDGenerator makes a custom generator available to the `id:<name>` field tag.
+// Registering a name that already exists replaces the previous generator.
+//
+// Example usage:
+//
+//	mongorm.RegisterIDGenerator("snowflake", mongorm.IDGeneratorFunc(func() (any, error) {
+//	    return node.Generate().Int64(), nil
+//	}))
+func RegisterIDGenerator(name string, generator IDGenerator) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" || generator == nil {
+		return
+	}
+
+	idGeneratorsMu.Lock()
+	defer idGeneratorsMu.Unlock()
+
+	idGenerators[name] = generator
+}
+
+func lookupIDGenerator(name string) (IDGenerator, bool) {
+	idGeneratorsMu.RLock()
+	defer idGeneratorsMu.RUnlock()
+
+	generator, ok := idGenerators[strings.ToLower(strings.TrimSpace(name))]
+	return generator, ok
+}
+
+// ObjectIDGenerator returns a generator producing new bson.ObjectID values.
+func ObjectIDGenerator() IDGenerator {
+	return IDGeneratorFunc(func() (any, error) {
+		return bson.NewObjectID(), nil
+	})
+}
+
+// UUIDv4Generator returns a generator producing random (version 4) UUIDs.
+func UUIDv4Generator() IDGenerator {
+	return IDGeneratorFunc(func() (any, error) {
+		return NewUUIDv4()
+	})
+}
+
+// UUIDv7Generator returns a generator producing time-ordered (version 7) UUIDs.
+func UUIDv7Generator() IDGenerator {
+	return IDGeneratorFunc(func() (any, error) {
+		return NewUUIDv7()
+	})
+}
+
+// ULIDGenerator returns a generator producing lexicographically sortable ULIDs.
+func ULIDGenerator() IDGenerator {
+	return IDGeneratorFunc(func() (any, error) {
+		return NewULID()
+	})
+}
+
+// UUID is a 128-bit universally unique identifier as defined by RFC 9562.
+type UUID [16]byte
+
+// NewUUIDv4 returns a new random UUID.
+func NewUUIDv4() (UUID, error) {
+	var u UUID
+	if _, err := rand.Read(u[:]); err != nil {
+		return UUID{}, err
+	}
+
+	u[6] = (u[6] & 0x0f) | 0x40
+	u[8] = (u[8] & 0x3f) | 0x80
+
+	return u, nil
+}
+
+// NewUUIDv7 returns a new UUID whose first 48 bits hold the current Unix time in
+// milliseconds, so IDs generated later sort after IDs generated earlier.
+func NewUUIDv7() (UUID, error) {
+	var u UUID
+	if _, err := rand.Read(u[6:]); err != nil {
+		return UUID{}, err
+	}
+
+	putUint48(u[:6], uint64(time.Now().UnixMilli()))
+
+	u[6] = (u[6] & 0x0f) | 0x70
+	u[8] = (u[8] & 0x3f) | 0x80
+
+	return u, nil
+}
+
+// String returns the canonical textual form, e.g. "0190163d-8694-739b-aea5-966c26f8ad91".
+func (u UUID) String() string {
+	var buf [36]byte
+	hex.Encode(buf[0:8], u[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], u[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], u[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], u[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], u[10:])
+
+	return string(buf[:])
+}
+
+// MarshalBSONValue encodes the UUID as BSON binary subtype 4.
+func (u UUID) MarshalBSONValue() (byte, []byte, error) {
+	t, data, err := bson.MarshalValue(bson.Binary{Subtype: bson.TypeBinaryUUID, Data: u[:]})
+	return byte(t), data, err
+}
+
+// UnmarshalBSONValue decodes a UUID stored as BSON binary or as a canonical string.
+func (u *UUID) UnmarshalBSONValue(t byte, data []byte) error {
+	raw := bson.RawValue{Type: bson.Type(t), Value: data}
+
+	switch bson.Type(t) {
+	case bson.TypeBinary:
+		_, payload, ok := raw.BinaryOK()
+		if !ok || len(payload) != len(u) {
+			return fmt.Errorf("mongorm: invalid UUID binary value")
+		}
+		copy(u[:], payload)
+		return nil
+	case bson.TypeString:
+		parsed, err := ParseUUID(raw.StringValue())
+		if err != nil {
+			return err
+		}
+		*u = parsed
+		return nil
+	default:
+		return fmt.Errorf("mongorm: cannot decode BSON type %s into UUID", bson.Type(t))
+	}
+}
+
+// ParseUUID parses a UUID in canonical textual form.
+func ParseUUID(s string) (UUID, error) {
+	var u UUID
+	if len(s) != 36 || s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+		return UUID{}, fmt.Errorf("mongorm: invalid UUID %q", s)
+	}
+
+	compact := s[0:8] + s[9:13] + s[14:18] + s[19:23] + s[24:]
+	if _, err := hex.Decode(u[:], []byte(compact)); err != nil {
+		return UUID{}, fmt.Errorf("mongorm: invalid UUID %q", s)
+	}
+
+	return u, nil
+}
+
+// ULID is a 128-bit Universally Unique Lexicographically Sortable Identifier: a 48-bit
+// millisecond timestamp followed by 80 random bits, rendered as 26 Crockford base32 chars.
+type ULID [16]byte
+
+const crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// NewULID returns a new ULID for the current time.
+func NewULID() (ULID, error) {
+	var id ULID
+	if _, err := rand.Read(id[6:]); err != nil {
+		return ULID{}, err
+	}
+
+	putUint48(id[:6], uint64(time.Now().UnixMilli()))
+
+	return id, nil
+}
+
+// String returns the 26 character Crockford base32 form of the ULID.
+func (id ULID) String() string {
+	hi := binary.BigEndian.Uint64(id[0:8])
+	lo := binary.BigEndian.Uint64(id[8:16])
+
+	// 128 bits are encoded as 26 groups of 5 bits, with 2 leading padding bits.
+	var buf [26]byte
+	for i := 25; i >= 0; i-- {
+		buf[i] = crockfordAlphabet[lo&0x1f]
+		lo = (lo >> 5) | (hi << 59)
+		hi >>= 5
+	}
+
+	return string(buf[:])
+}
+
+// MarshalBSONValue encodes the ULID as its 26 character string form, which keeps the
+// stored values sortable by creation time.
+func (id ULID) MarshalBSONValue() (byte, []byte, error) {
+	t, data, err := bson.MarshalValue(id.String())
+	return byte(t), data, err
+}
+
+// UnmarshalBSONValue decodes a ULID stored as a BSON string.
+func (id *ULID) UnmarshalBSONValue(t byte, data []byte) error {
+	raw := bson.RawValue{Type: bson.Type(t), Value: data}
+
+	value, ok := raw.StringValueOK()
+	if !ok {
+		return fmt.Errorf("mongorm: cannot decode BSON type %s into ULID", bson.Type(t))
+	}
+
+	parsed, err := ParseULID(value)
+	if err != nil {
+		return err
+	}
+
+	*id = parsed
+	return nil
+}
+
+// ParseULID parses a ULID from its 26 character Crockford base32 form. Parsing is
+// case-insensitive.
+func ParseULID(s string) (ULID, error) {
+	if len(s) != 26 {
+		return ULID{}, fmt.Errorf("mongorm: invalid ULID %q", s)
+	}
+
+	var hi, lo uint64
+	for i := 0; i < len(s); i++ {
+		index := strings.IndexByte(crockfordAlphabet, upperASCII(s[i]))
+		if index < 0 || (i == 0 && index > 7) {
+			return ULID{}, fmt.Errorf("mongorm: invalid ULID %q", s)
+		}
+
+		hi = (hi << 5) | (lo >> 59)
+		lo = (lo << 5) | uint64(index)
+	}
+
+	var id ULID
+	binary.BigEndian.PutUint64(id[0:8], hi)
+	binary.BigEndian.PutUint64(id[8:16], lo)
+
+	return id, nil
+}
+
+func upperASCII(c byte) byte {
+	if c >= 'a' && c <= 'z' {
+		return c - ('a' - 'A')
+	}
+
+	return c
+}
+
+func putUint48(dst []byte, v uint64) {
+	dst[0] = byte(v >> 40)
+	dst[1] = byte(v >> 32)
+	dst[2] = byte(v >> 24)
+	dst[3] = byte(v >> 16)
+	dst[4] = byte(v >> 8)
+	dst[5] = byte(v)
+}
+
+// idGeneratorForSchema resolves the generator used for the primary field. A generator set
+// on MongORMOptions takes precedence over the `id:<name>` tag on the primary field.
+//
+// > NOTE: This method is internal only.
+func (m *MongORM[T]) idGeneratorForSchema() (IDGenerator, error) {
+	if m.options != nil && m.options.IDGenerator != nil {
+		return m.options.IDGenerator, nil
+	}
+
+	primaryGoName, _, err := m.getFieldByTag(ModelTagPrimary)
+	if err != nil {
+		return nil, err
+	}
+
+	field, ok := reflect.TypeFor[T]().FieldByName(primaryGoName)
+	if !ok {
+		return nil, nil
+	}
+
+	name, ok := getModelTagValue(field.Tag, ModelTagIDGenerator)
+	if !ok {
+		return nil, nil
+	}
+
+	generator, ok := lookupIDGenerator(name)
+	if !ok {
+		return nil, configErrorf("unknown id generator %q on field %s", name, primaryGoName)
+	}
+
+	return generator, nil
+}
+
+// generatePrimaryID fills the primary field using the configured IDGenerator when the
+// field is still empty. Documents that already carry an ID are left untouched.
+//
+// > NOTE: This method is internal only.
+func (m *MongORM[T]) generatePrimaryID() error {
+	if m == nil || m.schema == nil {
+		return nil
+	}
+
+	generator, err := m.idGeneratorForSchema()
+	if err != nil || generator == nil {
+		return err
+	}
+
+	primaryGoName, _, err := m.getFieldByTag(ModelTagPrimary)
+	if err != nil {
+		return err
+	}
+
+	field := reflect.ValueOf(m.schema).Elem().FieldByName(primaryGoName)
+	if _, hasID := primaryKeyValue(field); hasID {
+		return nil
+	}
+
+	id, err := generator.NewID()
+	if err != nil {
+		return err
+	}
+
+	return assignGeneratedID(field, id)
+}
+
+// assignGeneratedID stores a generated ID into the primary field, allocating pointers and
+// converting the value to the field type when needed.
+//
+// > NOTE: This function is internal only.
+func assignGeneratedID(field reflect.Value, id any) error {
+	if !field.CanSet() {
+		return configErrorf("primary field must be exported and settable")
+	}
+
+	target := field
+	if target.Kind() == reflect.Pointer {
+		target = reflect.New(field.Type().Elem()).Elem()
+	}
+
+	value := reflect.ValueOf(id)
+	if !value.IsValid() {
+		return configErrorf("id generator returned a nil value")
+	}
+
+	switch {
+	case value.Type().AssignableTo(target.Type()):
+		target.Set(value)
+	case target.Kind() == reflect.String:
+		target.SetString(generatedIDString(id))
+	case value.Type().ConvertibleTo(target.Type()):
+		target.Set(value.Convert(target.Type()))
+	default:
+		return configErrorf("id generator returned %T which cannot be assigned to %s", id, field.Type())
+	}
+
+	if field.Kind() == reflect.Pointer {
+		field.Set(target.Addr())
+	}
+
+	return nil
+}
+
+func generatedIDString(id any) string {
+	switch typed := id.(type) {
+	case bson.ObjectID:
+		return typed.Hex()
+	case string:
+		return typed
+	case fmt.Stringer:
+		return typed.String()
+	default:
+		return fmt.Sprint(id)
+	}
+}
@@ -0,0 +1,217 @@
+package mongorm
+
+import (
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// IndexBuilder builds a typed MongoDB index model from schema fields.
+//
+// Example usage:
+//
+//	model := mongorm.NewIndexBuilder().
+//	    Asc(ToDoFields.Text).
+//	    Desc(ToDoFields.Count).
+//	    Partial(ToDoFields.Done.Eq(false), ToDoFields.Count.Gt(0)).
+//	    Name("open_todos").
+//	    Model()
+//
+//	_, err := orm.EnsureIndex(ctx, model)
+type IndexBuilder struct {
+	keys               bson.D
+	options            *options.IndexOptionsBuilder
+	partial            []bson.M
+	weights            bson.D
+	wildcardProjection bson.D
+}
+
+// NewIndexBuilder creates a new index model builder.
+func NewIndexBuilder() *IndexBuilder {
+	return &IndexBuilder{
+		keys:    bson.D{},
+		options: options.Index(),
+	}
+}
+
+// Keys appends index keys built with Asc, Desc, Text or the other key helpers.
+func (b *IndexBuilder) Keys(keys ...bson.E) *IndexBuilder {
+	b.keys = append(b.keys, keys...)
+
+	return b
+}
+
+// Asc appends an ascending key for the field.
+func (b *IndexBuilder) Asc(field Field) *IndexBuilder {
+	return b.Keys(Asc(field))
+}
+
+// Desc appends a descending key for the field.
+func (b *IndexBuilder) Desc(field Field) *IndexBuilder {
+	return b.Keys(Desc(field))
+}
+
+// Text appends a text key for the field.
+func (b *IndexBuilder) Text(field Field) *IndexBuilder {
+	return b.Keys(Text(field))
+}
+
+// Geo2DSphere appends a 2dsphere key for the field.
+func (b *IndexBuilder) Geo2DSphere(field Field) *IndexBuilder {
+	return b.Keys(Geo2DSphere(field))
+}
+
+// Hashed appends a hashed key for the field.
+func (b *IndexBuilder) Hashed(field Field) *IndexBuilder {
+	return b.Keys(bson.E{Key: field.BSONName(), Value: "hashed"})
+}
+
+// Wildcard appends a wildcard key covering the field and everything below it, e.g.
+// {"attributes.$**": 1}. Pass nil to cover the whole document ({"$**": 1}) and narrow it
+// with WildcardInclude or WildcardExclude.
+func (b *IndexBuilder) Wildcard(field Field) *IndexBuilder {
+	if field == nil {
+		return b.Keys(bson.E{Key: "$**", Value: 1})
+	}
+
+	return b.Keys(bson.E{Key: field.BSONName() + ".$**", Value: 1})
+}
+
+// WildcardInclude limits a whole-document wildcard index to the fields.
+func (b *IndexBuilder) WildcardInclude(fields ...Field) *IndexBuilder {
+	for _, field := range fields {
+		b.wildcardProjection = append(b.wildcardProjection, bson.E{Key: field.BSONName(), Value: 1})
+	}
+
+	return b
+}
+
+// WildcardExclude removes the fields from a whole-document wildcard index.
+func (b *IndexBuilder) WildcardExclude(fields ...Field) *IndexBuilder {
+	for _, field := range fields {
+		b.wildcardProjection = append(b.wildcardProjection, bson.E{Key: field.BSONName(), Value: 0})
+	}
+
+	return b
+}
+
+// Name sets the index name. Without it the server generates one from the keys.
+func (b *IndexBuilder) Name(name string) *IndexBuilder {
+	b.options.SetName(name)
+
+	return b
+}
+
+// Unique makes the index reject duplicate keys.
+func (b *IndexBuilder) Unique() *IndexBuilder {
+	b.options.SetUnique(true)
+
+	return b
+}
+
+// Sparse skips documents that do not contain the indexed fields.
+func (b *IndexBuilder) Sparse() *IndexBuilder {
+	b.options.SetSparse(true)
+
+	return b
+}
+
+// ExpireAfterSeconds makes the index a TTL index that removes documents the given number
+// of seconds after the time stored in the indexed field.
+func (b *IndexBuilder) ExpireAfterSeconds(seconds int32) *IndexBuilder {
+	b.options.SetExpireAfterSeconds(seconds)
+
+	return b
+}
+
+// Partial restricts the index to documents matching all of the filters, built with the
+// primitive field operators, e.g. Partial(ToDoFields.Done.Eq(false)). Filters on
+// different fields are merged into one document; repeated fields are combined with
+// $and.
+func (b *IndexBuilder) Partial(filters ...bson.M) *IndexBuilder {
+	b.partial = append(b.partial, filters...)
+
+	return b
+}
+
+// Collation sets the collation used by the index for string comparisons.
+func (b *IndexBuilder) Collation(collation *options.Collation) *IndexBuilder {
+	b.options.SetCollation(collation)
+
+	return b
+}
+
+// Hidden hides the index from the query planner while it keeps being maintained.
+func (b *IndexBuilder) Hidden() *IndexBuilder {
+	b.options.SetHidden(true)
+
+	return b
+}
+
+// Weight sets the weight of a text field. Fields without a weight default to 1.
+func (b *IndexBuilder) Weight(field Field, weight int32) *IndexBuilder {
+	b.weights = append(b.weights, bson.E{Key: field.BSONName(), Value: weight})
+
+	return b
+}
+
+// DefaultLanguage sets the language of a text index, e.g. "english" or "none".
+func (b *IndexBuilder) DefaultLanguage(language string) *IndexBuilder {
+	b.options.SetDefaultLanguage(language)
+
+	return b
+}
+
+// LanguageOverride sets the field that holds the per-document language of a text index.
+func (b *IndexBuilder) LanguageOverride(field Field) *IndexBuilder {
+	b.options.SetLanguageOverride(field.BSONName())
+
+	return b
+}
+
+// Model returns the built index model.
+func (b *IndexBuilder) Model() mongo.IndexModel {
+	opts := options.Index()
+	opts.Opts = append(opts.Opts, b.options.Opts...)
+
+	if len(b.weights) > 0 {
+		opts.SetWeights(b.weights)
+	}
+	if len(b.wildcardProjection) > 0 {
+		opts.SetWildcardProjection(b.wildcardProjection)
+	}
+	if filter := b.partialFilter(); filter != nil {
+		opts.SetPartialFilterExpression(filter)
+	}
+
+	return mongo.IndexModel{
+		Keys:    append(bson.D{}, b.keys...),
+		Options: opts,
+	}
+}
+
+// partialFilter merges the partial filters into one document, falling back to $and when
+// two filters constrain the same field.
+//
+// > NOTE: This method is internal only.
+func (b *IndexBuilder) partialFilter() bson.M {
+	if len(b.partial) == 0 {
+		return nil
+	}
+
+	merged := bson.M{}
+	for _, filter := range b.partial {
+		for key, value := range filter {
+			if _, exists := merged[key]; exists {
+				and := bson.A{}
+				for _, f := range b.partial {
+					and = append(and, f)
+				}
+				return bson.M{"$and": and}
+			}
+			merged[key] = value
+		}
+	}
+
+	return merged
+}
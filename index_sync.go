@@ -0,0 +1,601 @@
+package mongorm
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// IndexSyncOptions controls how SyncIndexes treats indexes on the collection that do not
+// match the model. The zero value only creates missing indexes and reports the rest.
+type IndexSyncOptions struct {
+	// DropStale drops indexes that the model does not declare. The _id index is never
+	// dropped.
+	DropStale bool
+	// RebuildDrifted drops and recreates indexes whose keys, name or options no longer
+	// match the model.
+	RebuildDrifted bool
+}
+
+// IndexSyncResult reports what SyncIndexes found on the collection and what it changed.
+type IndexSyncResult struct {
+	// Created lists the names of the indexes that were created.
+	Created []string
+	// Dropped lists the names of the indexes that were dropped.
+	Dropped []string
+	// Stale lists the indexes on the collection that the model does not declare.
+	Stale []string
+	// Drifted lists the declared indexes that collide with a different existing index.
+	Drifted []IndexDrift
+}
+
+// IndexDrift describes a desired index that matches an existing index by name or keys
+// but differs from it.
+type IndexDrift struct {
+	// Name is the name of the desired index.
+	Name string
+	// Existing is the name of the index on the collection.
+	Existing string
+	// Differences lists what differs: "name", "keys" or an index option such as
+	// "unique" or "expireAfterSeconds".
+	Differences []string
+	// Model is the desired index model.
+	Model mongo.IndexModel
+}
+
+// IndexPlan is the difference between a set of desired indexes and the indexes on the
+// collection, as computed by PlanIndexes.
+type IndexPlan struct {
+	// Create lists the desired indexes that do not exist on the collection.
+	Create []mongo.IndexModel
+	// Drop lists the names of the indexes on the collection that are not desired. The
+	// _id index is never listed.
+	Drop []string
+	// Conflicts lists the desired indexes that collide with a different existing index:
+	// same keys with different options or name, or same name with different keys.
+	Conflicts []IndexDrift
+}
+
+// Empty reports whether the collection already matches the desired indexes.
+func (p *IndexPlan) Empty() bool {
+	return len(p.Create) == 0 && len(p.Drop) == 0 && len(p.Conflicts) == 0
+}
+
+// PlanIndexes compares the desired index models with the indexes on the collection and
+// returns the changes needed to match them, without applying anything. Desired indexes
+// are matched to existing ones by name first and then by keys.
+//
+// Example usage:
+//
+//	plan, err := orm.PlanIndexes(ctx, []mongo.IndexModel{
+//	    mongorm.UniqueIndexModelFromKeys(mongorm.Asc(ToDoFields.Text)),
+//	})
+//	if err != nil {
+//	    return err
+//	}
+//	for _, conflict := range plan.Conflicts {
+//	    log.Printf("index %s conflicts with %s: %v", conflict.Name, conflict.Existing, conflict.Differences)
+//	}
+func (m *MongORM[T]) PlanIndexes(ctx context.Context, desired []mongo.IndexModel) (*IndexPlan, error) {
+	if err := m.ensureReady(); err != nil {
+		return nil, err
+	}
+
+	existing, err := m.listIndexDescriptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &IndexPlan{}
+	matched := map[string]bool{}
+
+	for _, model := range desired {
+		description, err := describeIndexModel(model)
+		if err != nil {
+			return nil, err
+		}
+
+		current, ok := findIndexDescription(existing, description)
+		if !ok {
+			plan.Create = append(plan.Create, model)
+			continue
+		}
+		matched[current.name] = true
+
+		differences := description.differences(current)
+		if len(differences) == 0 {
+			continue
+		}
+
+		plan.Conflicts = append(plan.Conflicts, IndexDrift{
+			Name:        description.name,
+			Existing:    current.name,
+			Differences: differences,
+			Model:       model,
+		})
+	}
+
+	for _, current := range existing {
+		if current.name == "_id_" || matched[current.name] {
+			continue
+		}
+
+		plan.Drop = append(plan.Drop, current.name)
+	}
+
+	return plan, nil
+}
+
+// SyncIndexes brings the indexes of the collection in line with the index tags of the
+// model. Missing indexes are created; stale and drifted indexes are reported and only
+// dropped or rebuilt when the matching IndexSyncOptions flag is set. Use PlanIndexes with
+// IndexModels to review the changes without applying them.
+//
+// Example usage:
+//
+//	result, err := mongorm.New(&Task{}).SyncIndexes(ctx)
+//	if err != nil {
+//	    return err
+//	}
+//	for _, drift := range result.Drifted {
+//	    log.Printf("index %s differs: %v", drift.Name, drift.Differences)
+//	}
+func (m *MongORM[T]) SyncIndexes(ctx context.Context, opts ...IndexSyncOptions) (*IndexSyncResult, error) {
+	syncOpts := IndexSyncOptions{}
+	if len(opts) > 0 {
+		syncOpts = opts[0]
+	}
+
+	models, err := m.IndexModels()
+	if err != nil {
+		return nil, err
+	}
+
+	plan, err := m.PlanIndexes(ctx, models)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &IndexSyncResult{Stale: plan.Drop, Drifted: plan.Conflicts}
+	create := plan.Create
+	drop := []string{}
+
+	if syncOpts.RebuildDrifted {
+		for _, drift := range plan.Conflicts {
+			// Several desired indexes can drift against the same existing one.
+			if !slices.Contains(drop, drift.Existing) {
+				drop = append(drop, drift.Existing)
+			}
+			create = append(create, drift.Model)
+		}
+	}
+	if syncOpts.DropStale {
+		drop = append(drop, plan.Drop...)
+	}
+
+	indexes := m.info.collection.Indexes()
+	for _, name := range drop {
+		if err := indexes.DropOne(ctx, name); err != nil {
+			return result, err
+		}
+		result.Dropped = append(result.Dropped, name)
+	}
+
+	if len(create) > 0 {
+		names, err := indexes.CreateMany(ctx, create)
+		if err != nil {
+			return result, err
+		}
+		result.Created = names
+	}
+
+	return result, nil
+}
+
+// indexDescription is the comparable form of an index definition. Text keys are
+// collapsed into the _fts/_ftsx form the server reports, and options only hold values
+// that differ from the server defaults.
+//
+// > NOTE: This type is internal only.
+type indexDescription struct {
+	name    string
+	keys    bson.D
+	options bson.D
+}
+
+// indexOptionNames lists the index options compared between a declared and an existing
+// index, in the order differences are reported.
+var indexOptionNames = []string{
+	"unique",
+	"sparse",
+	"expireAfterSeconds",
+	"partialFilterExpression",
+	"collation",
+	"hidden",
+	"weights",
+	"default_language",
+	"language_override",
+	"wildcardProjection",
+}
+
+// listIndexDescriptions lists the indexes on the collection.
+//
+// > NOTE: This method is internal only.
+func (m *MongORM[T]) listIndexDescriptions(ctx context.Context) ([]indexDescription, error) {
+	cursor, err := m.info.collection.Indexes().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var specs []bson.D
+	if err := cursor.All(ctx, &specs); err != nil {
+		return nil, err
+	}
+
+	descriptions := make([]indexDescription, len(specs))
+	for i, spec := range specs {
+		descriptions[i] = describeIndexSpec(spec)
+	}
+
+	return descriptions, nil
+}
+
+// findIndexDescription returns the existing index with the name of desired or, failing
+// that, with the same keys.
+//
+// > NOTE: This function is internal only.
+func findIndexDescription(existing []indexDescription, desired indexDescription) (indexDescription, bool) {
+	for _, current := range existing {
+		if current.name == desired.name {
+			return current, true
+		}
+	}
+
+	for _, current := range existing {
+		if indexValuesEqual(current.keys, desired.keys) {
+			return current, true
+		}
+	}
+
+	return indexDescription{}, false
+}
+
+// describeIndexModel builds the description of a declared index model.
+//
+// > NOTE: This function is internal only.
+func describeIndexModel(model mongo.IndexModel) (indexDescription, error) {
+	keysValue, err := normalizeIndexValue(model.Keys)
+	if err != nil {
+		return indexDescription{}, err
+	}
+	keys, ok := keysValue.(bson.D)
+	if !ok || len(keys) == 0 {
+		return indexDescription{}, configErrorf("index keys must be a non-empty document")
+	}
+
+	args := &options.IndexOptions{}
+	if model.Options != nil {
+		for _, setter := range model.Options.List() {
+			if err := setter(args); err != nil {
+				return indexDescription{}, err
+			}
+		}
+	}
+
+	desc := indexDescription{name: defaultIndexName(keys), keys: collapseTextKeys(keys)}
+	if args.Name != nil {
+		desc.name = *args.Name
+	}
+
+	set := func(name string, value any) error {
+		normalized, err := normalizeIndexValue(value)
+		if err != nil {
+			return err
+		}
+		desc.options = append(desc.options, bson.E{Key: name, Value: normalized})
+		return nil
+	}
+
+	if args.Unique != nil && *args.Unique {
+		desc.options = append(desc.options, bson.E{Key: "unique", Value: true})
+	}
+	if args.Sparse != nil && *args.Sparse {
+		desc.options = append(desc.options, bson.E{Key: "sparse", Value: true})
+	}
+	if args.ExpireAfterSeconds != nil {
+		desc.options = append(desc.options, bson.E{Key: "expireAfterSeconds", Value: *args.ExpireAfterSeconds})
+	}
+	if args.PartialFilterExpression != nil {
+		if err := set("partialFilterExpression", args.PartialFilterExpression); err != nil {
+			return indexDescription{}, err
+		}
+	}
+	if args.Collation != nil {
+		desc.options = append(desc.options, bson.E{Key: "collation", Value: collationDocument(args.Collation)})
+	}
+	if args.Hidden != nil && *args.Hidden {
+		desc.options = append(desc.options, bson.E{Key: "hidden", Value: true})
+	}
+	if args.WildcardProjection != nil {
+		if err := set("wildcardProjection", args.WildcardProjection); err != nil {
+			return indexDescription{}, err
+		}
+	}
+
+	if slices.ContainsFunc(keys, isTextIndexKey) {
+		weights := bson.D{}
+		for _, key := range keys {
+			if isTextIndexKey(key) {
+				weights = append(weights, bson.E{Key: key.Key, Value: int32(1)})
+			}
+		}
+		if args.Weights != nil {
+			custom, err := normalizeIndexValue(args.Weights)
+			if err != nil {
+				return indexDescription{}, err
+			}
+			customWeights, ok := custom.(bson.D)
+			if !ok {
+				return indexDescription{}, configErrorf("text index weights must be a document")
+			}
+			for _, weight := range customWeights {
+				weights = setIndexOption(weights, weight.Key, weight.Value)
+			}
+		}
+		desc.options = append(desc.options, bson.E{Key: "weights", Value: sortedIndexDocument(weights)})
+
+		language, override := "english", "language"
+		if args.DefaultLanguage != nil {
+			language = *args.DefaultLanguage
+		}
+		if args.LanguageOverride != nil {
+			override = *args.LanguageOverride
+		}
+		desc.options = append(desc.options,
+			bson.E{Key: "default_language", Value: language},
+			bson.E{Key: "language_override", Value: override},
+		)
+	}
+
+	return desc, nil
+}
+
+// describeIndexSpec builds the description of an index reported by the server.
+//
+// > NOTE: This function is internal only.
+func describeIndexSpec(spec bson.D) indexDescription {
+	desc := indexDescription{}
+
+	for _, e := range spec {
+		switch e.Key {
+		case "name":
+			desc.name, _ = e.Value.(string)
+		case "key":
+			desc.keys, _ = e.Value.(bson.D)
+		case "unique", "sparse", "hidden":
+			if enabled, _ := e.Value.(bool); enabled {
+				desc.options = append(desc.options, bson.E{Key: e.Key, Value: true})
+			}
+		case "weights":
+			if weights, ok := e.Value.(bson.D); ok {
+				desc.options = append(desc.options, bson.E{Key: e.Key, Value: sortedIndexDocument(weights)})
+			}
+		default:
+			if slices.Contains(indexOptionNames, e.Key) {
+				desc.options = append(desc.options, e)
+			}
+		}
+	}
+
+	return desc
+}
+
+// differences lists what differs between the declared index d and the existing index
+// current. A declared collation only compares the fields it sets, because the server
+// reports every collation field.
+//
+// > NOTE: This method is internal only.
+func (d indexDescription) differences(current indexDescription) []string {
+	differences := []string{}
+
+	if d.name != current.name {
+		differences = append(differences, "name")
+	}
+	if !indexValuesEqual(d.keys, current.keys) {
+		differences = append(differences, "keys")
+	}
+
+	for _, name := range indexOptionNames {
+		desired, hasDesired := lookupIndexOption(d.options, name)
+		existing, hasExisting := lookupIndexOption(current.options, name)
+
+		switch {
+		case hasDesired != hasExisting:
+			differences = append(differences, name)
+		case !hasDesired:
+		case name == "collation":
+			desiredCollation, _ := desired.(bson.D)
+			existingCollation, _ := existing.(bson.D)
+			for _, field := range desiredCollation {
+				value, ok := lookupIndexOption(existingCollation, field.Key)
+				if !ok || !indexValuesEqual(field.Value, value) {
+					differences = append(differences, name)
+					break
+				}
+			}
+		case !indexValuesEqual(desired, existing):
+			differences = append(differences, name)
+		}
+	}
+
+	return differences
+}
+
+// defaultIndexName returns the name the server generates for keys, such as
+// "user_id_1_status_-1".
+//
+// > NOTE: This function is internal only.
+func defaultIndexName(keys bson.D) string {
+	parts := make([]string, 0, len(keys)*2)
+	for _, key := range keys {
+		parts = append(parts, key.Key, fmt.Sprint(key.Value))
+	}
+
+	return strings.Join(parts, "_")
+}
+
+func isTextIndexKey(key bson.E) bool {
+	return key.Value == "text"
+}
+
+// collapseTextKeys replaces the text keys of an index with the _fts/_ftsx pair the
+// server stores in their place.
+//
+// > NOTE: This function is internal only.
+func collapseTextKeys(keys bson.D) bson.D {
+	collapsed := bson.D{}
+	inserted := false
+
+	for _, key := range keys {
+		if !isTextIndexKey(key) {
+			collapsed = append(collapsed, key)
+			continue
+		}
+		if !inserted {
+			collapsed = append(collapsed, bson.E{Key: "_fts", Value: "text"}, bson.E{Key: "_ftsx", Value: int32(1)})
+			inserted = true
+		}
+	}
+
+	return collapsed
+}
+
+// collationDocument converts a collation to the document form the server reports,
+// keeping only the fields that are set.
+//
+// > NOTE: This function is internal only.
+func collationDocument(c *options.Collation) bson.D {
+	doc := bson.D{}
+	add := func(name string, value any, set bool) {
+		if set {
+			doc = append(doc, bson.E{Key: name, Value: value})
+		}
+	}
+
+	add("locale", c.Locale, c.Locale != "")
+	add("caseLevel", c.CaseLevel, c.CaseLevel)
+	add("caseFirst", c.CaseFirst, c.CaseFirst != "")
+	add("strength", int32(c.Strength), c.Strength != 0)
+	add("numericOrdering", c.NumericOrdering, c.NumericOrdering)
+	add("alternate", c.Alternate, c.Alternate != "")
+	add("maxVariable", c.MaxVariable, c.MaxVariable != "")
+	add("normalization", c.Normalization, c.Normalization)
+	add("backwards", c.Backwards, c.Backwards)
+
+	return doc
+}
+
+// normalizeIndexValue round-trips value through BSON so that declared values compare
+// with the values the server reports.
+//
+// > NOTE: This function is internal only.
+func normalizeIndexValue(value any) (any, error) {
+	raw, err := bson.Marshal(bson.D{{Key: "v", Value: value}})
+	if err != nil {
+		return nil, err
+	}
+
+	var doc bson.D
+	if err := bson.Unmarshal(raw, &doc); err != nil {
+		return nil, err
+	}
+
+	return doc[0].Value, nil
+}
+
+// indexValuesEqual compares two normalized BSON values, treating numbers of different
+// types as equal when their values are.
+//
+// > NOTE: This function is internal only.
+func indexValuesEqual(a, b any) bool {
+	if x, ok := indexNumber(a); ok {
+		y, ok := indexNumber(b)
+		return ok && x == y
+	}
+
+	switch x := a.(type) {
+	case bson.D:
+		y, ok := b.(bson.D)
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for i := range x {
+			if x[i].Key != y[i].Key || !indexValuesEqual(x[i].Value, y[i].Value) {
+				return false
+			}
+		}
+		return true
+	case bson.A:
+		y, ok := b.(bson.A)
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for i := range x {
+			if !indexValuesEqual(x[i], y[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return reflect.DeepEqual(a, b)
+	}
+}
+
+func indexNumber(value any) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	default:
+		return 0, false
+	}
+}
+
+func lookupIndexOption(doc bson.D, name string) (any, bool) {
+	for _, e := range doc {
+		if e.Key == name {
+			return e.Value, true
+		}
+	}
+
+	return nil, false
+}
+
+func setIndexOption(doc bson.D, name string, value any) bson.D {
+	for i := range doc {
+		if doc[i].Key == name {
+			doc[i].Value = value
+			return doc
+		}
+	}
+
+	return append(doc, bson.E{Key: name, Value: value})
+}
+
+func sortedIndexDocument(doc bson.D) bson.D {
+	sorted := slices.Clone(doc)
+	slices.SortFunc(sorted, func(a, b bson.E) int { return strings.Compare(a.Key, b.Key) })
+
+	return sorted
+}
@@ -0,0 +1,189 @@
+package mongorm
+
+import (
+	"cmp"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// taggedIndex is an index assembled from the index tags of a model.
+//
+// > NOTE: This type is internal only.
+type taggedIndex struct {
+	name   string
+	keys   []taggedIndexKey
+	unique bool
+	ttl    *int32
+}
+
+// taggedIndexKey is one key of a tagged index. Keys are ordered by order and then by the
+// position of the field in the struct.
+//
+// > NOTE: This type is internal only.
+type taggedIndexKey struct {
+	field    string
+	value    any
+	order    int
+	position int
+}
+
+// IndexModels returns the index models declared by the index tags of the model, in the
+// order the indexes first appear in the struct. Every model carries an explicit name so
+// SyncIndexes can match it against the indexes on the collection.
+//
+// Example usage:
+//
+//	type Task struct {
+//	    Email  *string `bson:"email" mongorm:"unique"`
+//	    UserID *string `bson:"user_id" mongorm:"index:user_status,order:1"`
+//	    Status *string `bson:"status" mongorm:"index:user_status,order:2"`
+//	}
+//
+//	models, err := mongorm.New(&Task{}).IndexModels()
+func (m *MongORM[T]) IndexModels() ([]mongo.IndexModel, error) {
+	indexes, err := taggedIndexesOf(reflect.TypeFor[T]())
+	if err != nil {
+		return nil, err
+	}
+
+	models := make([]mongo.IndexModel, 0, len(indexes))
+	for _, idx := range indexes {
+		slices.SortStableFunc(idx.keys, func(a, b taggedIndexKey) int {
+			return cmp.Or(cmp.Compare(a.order, b.order), cmp.Compare(a.position, b.position))
+		})
+
+		keys := make(bson.D, len(idx.keys))
+		for i, key := range idx.keys {
+			keys[i] = bson.E{Key: key.field, Value: key.value}
+		}
+
+		name := idx.name
+		if name == "" {
+			name = defaultIndexName(keys)
+		}
+
+		opts := options.Index().SetName(name)
+		if idx.unique {
+			opts.SetUnique(true)
+		}
+		if idx.ttl != nil {
+			opts.SetExpireAfterSeconds(*idx.ttl)
+		}
+
+		models = append(models, mongo.IndexModel{Keys: keys, Options: opts})
+	}
+
+	return models, nil
+}
+
+// taggedIndexesOf collects the indexes declared by the index tags of the struct type t.
+//
+// > NOTE: This function is internal only.
+func taggedIndexesOf(t reflect.Type) ([]*taggedIndex, error) {
+	indexes := []*taggedIndex{}
+	groups := map[string]*taggedIndex{}
+	var textIndex *taggedIndex
+
+	for i := 0; i < t.NumField(); i++ {
+		fieldType := t.Field(i)
+
+		// Skip unexported
+		if fieldType.PkgPath != "" {
+			continue
+		}
+
+		flags := getModelTags(fieldType.Tag)
+		has := func(tag ModelTags) bool { return slices.Contains(flags, string(tag)) }
+		group, grouped := getModelTagValue(fieldType.Tag, ModelTagIndex)
+		ttlValue, hasTTL := getModelTagValue(fieldType.Tag, ModelTagTTL)
+		orderValue, hasOrder := getModelTagValue(fieldType.Tag, ModelTagIndexOrder)
+
+		if !grouped && !hasTTL && !has(ModelTagIndex) && !has(ModelTagUnique) && !has(ModelTagText) {
+			if hasOrder || has(ModelTagIndexDesc) {
+				return nil, configErrorf("field %s sets an index order or direction without an index tag", fieldType.Name)
+			}
+			continue
+		}
+
+		field := strings.Split(fieldType.Tag.Get("bson"), ",")[0]
+		if field == "-" {
+			return nil, configErrorf("field %s declares an index but is not stored", fieldType.Name)
+		}
+		if field == "" {
+			field = strings.ToLower(fieldType.Name)
+		}
+
+		var direction any = 1
+		if has(ModelTagIndexDesc) {
+			direction = -1
+		}
+
+		order := 0
+		if hasOrder {
+			parsed, err := strconv.Atoi(orderValue)
+			if err != nil {
+				return nil, configErrorf("field %s has an invalid index order %q", fieldType.Name, orderValue)
+			}
+			order = parsed
+		}
+
+		var ttl *int32
+		if hasTTL {
+			seconds, err := strconv.ParseInt(ttlValue, 10, 32)
+			if err != nil || seconds < 0 {
+				return nil, configErrorf("field %s has an invalid ttl %q", fieldType.Name, ttlValue)
+			}
+			expireAfter := int32(seconds)
+			ttl = &expireAfter
+		}
+
+		if grouped {
+			if hasTTL {
+				return nil, configErrorf("field %s cannot combine ttl with the compound index %s", fieldType.Name, group)
+			}
+
+			idx, ok := groups[group]
+			if !ok {
+				idx = &taggedIndex{name: group}
+				groups[group] = idx
+				indexes = append(indexes, idx)
+			}
+
+			key := taggedIndexKey{field: field, value: direction, order: order, position: i}
+			if has(ModelTagText) {
+				key.value = "text"
+			}
+			idx.keys = append(idx.keys, key)
+			idx.unique = idx.unique || has(ModelTagUnique)
+			continue
+		}
+
+		if hasOrder {
+			return nil, configErrorf("field %s sets an index order without a compound index name", fieldType.Name)
+		}
+
+		if has(ModelTagIndex) || has(ModelTagUnique) || hasTTL {
+			indexes = append(indexes, &taggedIndex{
+				keys:   []taggedIndexKey{{field: field, value: direction, position: i}},
+				unique: has(ModelTagUnique),
+				ttl:    ttl,
+			})
+		}
+
+		if has(ModelTagText) {
+			if textIndex == nil {
+				textIndex = &taggedIndex{}
+				indexes = append(indexes, textIndex)
+			}
+			textIndex.keys = append(textIndex.keys, taggedIndexKey{field: field, value: "text", position: i})
+		}
+	}
+
+	return indexes, nil
+}
@@ -0,0 +1,479 @@
+package memdb
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// runPipeline applies the aggregation stages to docs in order.
+func runPipeline(ctx context.Context, db *Database, docs []bson.D, stages bson.A) ([]bson.D, error) {
+	for _, raw := range stages {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		stage, ok := raw.(bson.D)
+		if !ok || len(stage) != 1 {
+			return nil, fmt.Errorf("memdb: each pipeline stage must be a document with one key")
+		}
+
+		var err error
+		docs, err = runStage(ctx, db, docs, stage[0])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return docs, nil
+}
+
+func runStage(ctx context.Context, db *Database, docs []bson.D, stage bson.E) ([]bson.D, error) {
+	switch stage.Key {
+	case "$match":
+		filter, ok := stage.Value.(bson.D)
+		if !ok {
+			return nil, fmt.Errorf("memdb: $match requires a document")
+		}
+
+		out := []bson.D{}
+		for _, doc := range docs {
+			matched, err := matchDocument(doc, filter)
+			if err != nil {
+				return nil, err
+			}
+			if matched {
+				out = append(out, doc)
+			}
+		}
+		return out, nil
+	case "$sort":
+		spec, ok := stage.Value.(bson.D)
+		if !ok {
+			return nil, fmt.Errorf("memdb: $sort requires a document")
+		}
+		return docs, sortDocuments(docs, spec)
+	case "$skip", "$limit":
+		n, ok := toInt64(stage.Value)
+		if !ok {
+			if f, isFloat := stage.Value.(float64); isFloat {
+				n, ok = int64(f), true
+			}
+		}
+		if !ok || n < 0 {
+			return nil, fmt.Errorf("memdb: %s requires a non-negative integer", stage.Key)
+		}
+		if stage.Key == "$skip" {
+			return docs[min(int(n), len(docs)):], nil
+		}
+		return docs[:min(int(n), len(docs))], nil
+	case "$project":
+		spec, ok := stage.Value.(bson.D)
+		if !ok {
+			return nil, fmt.Errorf("memdb: $project requires a document")
+		}
+		return projectStage(docs, spec)
+	case "$addFields", "$set":
+		spec, ok := stage.Value.(bson.D)
+		if !ok {
+			return nil, fmt.Errorf("memdb: %s requires a document", stage.Key)
+		}
+
+		out := make([]bson.D, len(docs))
+		for i, doc := range docs {
+			updated := cloneDocument(doc)
+			for _, field := range spec {
+				value, err := evalExpression(doc, field.Value)
+				if err != nil {
+					return nil, err
+				}
+				if updated, err = setPath(updated, splitPath(field.Key), value); err != nil {
+					return nil, err
+				}
+			}
+			out[i] = updated
+		}
+		return out, nil
+	case "$unset":
+		fields := bson.A{}
+		switch v := stage.Value.(type) {
+		case string:
+			fields = bson.A{v}
+		case bson.A:
+			fields = v
+		default:
+			return nil, fmt.Errorf("memdb: $unset requires a field name or an array of field names")
+		}
+
+		spec := bson.D{}
+		for _, field := range fields {
+			spec = append(spec, bson.E{Key: stringOf(field), Value: int32(0)})
+		}
+		return projectStage(docs, spec)
+	case "$unwind":
+		return unwindStage(docs, stage.Value)
+	case "$group":
+		spec, ok := stage.Value.(bson.D)
+		if !ok {
+			return nil, fmt.Errorf("memdb: $group requires a document")
+		}
+		return groupStage(docs, spec)
+	case "$count":
+		name, ok := stage.Value.(string)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("memdb: $count requires a field name")
+		}
+		if len(docs) == 0 {
+			return []bson.D{}, nil
+		}
+		return []bson.D{{{Key: name, Value: int32(len(docs))}}}, nil
+	case "$lookup":
+		spec, ok := stage.Value.(bson.D)
+		if !ok {
+			return nil, fmt.Errorf("memdb: $lookup requires a document")
+		}
+		return lookupStage(ctx, db, docs, spec)
+	case "$facet":
+		spec, ok := stage.Value.(bson.D)
+		if !ok {
+			return nil, fmt.Errorf("memdb: $facet requires a document")
+		}
+
+		result := bson.D{}
+		for _, facet := range spec {
+			stages, ok := facet.Value.(bson.A)
+			if !ok {
+				return nil, fmt.Errorf("memdb: $facet %s requires a pipeline", facet.Key)
+			}
+
+			input := make([]bson.D, len(docs))
+			for i, doc := range docs {
+				input[i] = cloneDocument(doc)
+			}
+
+			output, err := runPipeline(ctx, db, input, stages)
+			if err != nil {
+				return nil, err
+			}
+
+			values := make(bson.A, len(output))
+			for i, doc := range output {
+				values[i] = doc
+			}
+			result = append(result, bson.E{Key: facet.Key, Value: values})
+		}
+		return []bson.D{result}, nil
+	default:
+		return nil, unsupportedf("aggregation stage %s", stage.Key)
+	}
+}
+
+// projectStage applies a $project stage. Field references and expressions compute new
+// fields; everything else follows the find projection rules.
+func projectStage(docs []bson.D, spec bson.D) ([]bson.D, error) {
+	plain := bson.D{}
+	computed := bson.D{}
+	for _, e := range spec {
+		switch e.Value.(type) {
+		case string, bson.D:
+			computed = append(computed, e)
+		default:
+			plain = append(plain, e)
+		}
+	}
+
+	if len(computed) > 0 && len(plain) == 0 {
+		plain = bson.D{{Key: "_id", Value: int32(1)}}
+	}
+
+	out := make([]bson.D, len(docs))
+	for i, doc := range docs {
+		projected, err := projectDocument(doc, plain)
+		if err != nil {
+			return nil, err
+		}
+		if len(computed) > 0 {
+			projected = cloneDocument(projected)
+		}
+
+		for _, field := range computed {
+			value, err := evalExpression(doc, field.Value)
+			if err != nil {
+				return nil, err
+			}
+			if projected, err = setPath(projected, splitPath(field.Key), value); err != nil {
+				return nil, err
+			}
+		}
+
+		out[i] = projected
+	}
+
+	return out, nil
+}
+
+func unwindStage(docs []bson.D, spec any) ([]bson.D, error) {
+	path, preserve := "", false
+	switch v := spec.(type) {
+	case string:
+		path = v
+	case bson.D:
+		value, _ := lookupKey(v, "path")
+		path = stringOf(value)
+		if keep, ok := lookupKey(v, "preserveNullAndEmptyArrays"); ok {
+			preserve = truthy(keep)
+		}
+		if _, ok := lookupKey(v, "includeArrayIndex"); ok {
+			return nil, unsupportedf("$unwind includeArrayIndex")
+		}
+	}
+
+	if !strings.HasPrefix(path, "$") {
+		return nil, fmt.Errorf("memdb: $unwind path must start with $")
+	}
+	parts := splitPath(path[1:])
+
+	out := []bson.D{}
+	for _, doc := range docs {
+		value, ok := getExact(doc, parts)
+		arr, isArray := value.(bson.A)
+
+		switch {
+		case isArray && len(arr) > 0:
+			for _, item := range arr {
+				unwound, err := setPath(cloneDocument(doc), parts, cloneValue(item))
+				if err != nil {
+					return nil, err
+				}
+				out = append(out, unwound)
+			}
+		case ok && value != nil && !isArray:
+			out = append(out, doc)
+		case preserve:
+			if isArray {
+				doc = unsetPath(cloneDocument(doc), parts)
+			}
+			out = append(out, doc)
+		}
+	}
+
+	return out, nil
+}
+
+func groupStage(docs []bson.D, spec bson.D) ([]bson.D, error) {
+	idExpr, ok := lookupKey(spec, "_id")
+	if !ok {
+		return nil, fmt.Errorf("memdb: $group requires an _id expression")
+	}
+
+	type group struct {
+		id   any
+		docs []bson.D
+	}
+	groups := []*group{}
+
+	for _, doc := range docs {
+		id, err := evalExpression(doc, idExpr)
+		if err != nil {
+			return nil, err
+		}
+
+		var target *group
+		for _, g := range groups {
+			if valuesEqual(g.id, id) {
+				target = g
+				break
+			}
+		}
+		if target == nil {
+			target = &group{id: id}
+			groups = append(groups, target)
+		}
+		target.docs = append(target.docs, doc)
+	}
+
+	out := make([]bson.D, 0, len(groups))
+	for _, g := range groups {
+		result := bson.D{{Key: "_id", Value: g.id}}
+
+		for _, field := range spec {
+			if field.Key == "_id" {
+				continue
+			}
+
+			accumulator, ok := field.Value.(bson.D)
+			if !ok || len(accumulator) != 1 {
+				return nil, fmt.Errorf("memdb: $group field %s must be an accumulator", field.Key)
+			}
+
+			value, err := accumulate(g.docs, accumulator[0])
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, bson.E{Key: field.Key, Value: value})
+		}
+
+		out = append(out, result)
+	}
+
+	return out, nil
+}
+
+func accumulate(docs []bson.D, accumulator bson.E) (any, error) {
+	values := make([]any, 0, len(docs))
+	for _, doc := range docs {
+		value, err := evalExpression(doc, accumulator.Value)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+
+	switch accumulator.Key {
+	case "$sum", "$avg":
+		var total any = int32(0)
+		count := 0
+		for _, value := range values {
+			if _, ok := toFloat(value); ok {
+				total = addNumbers(total, value)
+				count++
+			}
+		}
+		if accumulator.Key == "$sum" {
+			return total, nil
+		}
+		if count == 0 {
+			return nil, nil
+		}
+		sum, _ := toFloat(total)
+		return sum / float64(count), nil
+	case "$min", "$max":
+		var result any
+		for _, value := range values {
+			if value == nil {
+				continue
+			}
+			order := compareValues(value, result)
+			if result == nil || (accumulator.Key == "$min" && order < 0) || (accumulator.Key == "$max" && order > 0) {
+				result = value
+			}
+		}
+		return result, nil
+	case "$first", "$last":
+		if len(values) == 0 {
+			return nil, nil
+		}
+		if accumulator.Key == "$first" {
+			return values[0], nil
+		}
+		return values[len(values)-1], nil
+	case "$push":
+		return bson.A(values), nil
+	case "$addToSet":
+		set := bson.A{}
+		for _, value := range values {
+			if !containsValue(set, value) {
+				set = append(set, value)
+			}
+		}
+		return set, nil
+	case "$count":
+		return int32(len(docs)), nil
+	default:
+		return nil, unsupportedf("accumulator %s", accumulator.Key)
+	}
+}
+
+func lookupStage(ctx context.Context, db *Database, docs []bson.D, spec bson.D) ([]bson.D, error) {
+	from, _ := lookupKey(spec, "from")
+	localField, _ := lookupKey(spec, "localField")
+	foreignField, _ := lookupKey(spec, "foreignField")
+	as, _ := lookupKey(spec, "as")
+
+	if _, ok := lookupKey(spec, "pipeline"); ok {
+		return nil, unsupportedf("$lookup with a pipeline")
+	}
+	if stringOf(from) == "" || stringOf(localField) == "" || stringOf(foreignField) == "" || stringOf(as) == "" {
+		return nil, fmt.Errorf("memdb: $lookup requires from, localField, foreignField and as")
+	}
+
+	foreign, err := db.collection(stringOf(from)).query(ctx, nil, nil, nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]bson.D, len(docs))
+	for i, doc := range docs {
+		locals := expandArrays(resolvePath(doc, splitPath(stringOf(localField))))
+		if len(locals) == 0 {
+			locals = []any{nil}
+		}
+
+		matches := bson.A{}
+		for _, candidate := range foreign {
+			values := resolvePath(candidate, splitPath(stringOf(foreignField)))
+			for _, local := range locals {
+				if matched, _ := matchEquality(values, local); matched {
+					matches = append(matches, cloneDocument(candidate))
+					break
+				}
+			}
+		}
+
+		joined, err := setPath(cloneDocument(doc), splitPath(stringOf(as)), matches)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = joined
+	}
+
+	return out, nil
+}
+
+// evalExpression evaluates an aggregation expression: "$field" references, literal
+// values and documents of expressions. Expression operators are not supported.
+func evalExpression(doc bson.D, expr any) (any, error) {
+	switch v := expr.(type) {
+	case string:
+		if strings.HasPrefix(v, "$$") {
+			return nil, unsupportedf("aggregation variable %s", v)
+		}
+		if strings.HasPrefix(v, "$") {
+			value, _ := firstValue(doc, v[1:])
+			return cloneValue(value), nil
+		}
+		return v, nil
+	case bson.D:
+		if len(v) == 1 && v[0].Key == "$literal" {
+			return v[0].Value, nil
+		}
+
+		out := bson.D{}
+		for _, e := range v {
+			if strings.HasPrefix(e.Key, "$") {
+				return nil, unsupportedf("expression operator %s", e.Key)
+			}
+
+			value, err := evalExpression(doc, e.Value)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, bson.E{Key: e.Key, Value: value})
+		}
+		return out, nil
+	case bson.A:
+		out := make(bson.A, len(v))
+		for i, item := range v {
+			value, err := evalExpression(doc, item)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = value
+		}
+		return out, nil
+	default:
+		return v, nil
+	}
+}
@@ -0,0 +1,857 @@
+package memdb
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"sync"
+
+	"github.com/azayn-labs/mongorm"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// Collection is an in-memory collection. It implements mongorm.Collection and returns the
+// driver result types, so results decode exactly like results from MongoDB.
+type Collection struct {
+	db      *Database
+	name    string
+	mu      sync.Mutex
+	docs    []bson.D
+	indexes []*index
+}
+
+var _ mongorm.Collection = (*Collection)(nil)
+
+func newCollection(db *Database, name string) *Collection {
+	return &Collection{
+		db:      db,
+		name:    name,
+		indexes: []*index{idIndex()},
+	}
+}
+
+// Name returns the collection name.
+func (c *Collection) Name() string {
+	return c.name
+}
+
+// FindOne returns the first document matching filter.
+func (c *Collection) FindOne(
+	ctx context.Context,
+	filter any,
+	opts ...options.Lister[options.FindOneOptions],
+) *mongo.SingleResult {
+	args, err := resolveOptions(opts)
+	if err != nil {
+		return singleResult(nil, err)
+	}
+
+	docs, err := c.query(ctx, filter, args.Sort, args.Skip, int64Ptr(1), args.Projection)
+	if err != nil {
+		return singleResult(nil, err)
+	}
+	if len(docs) == 0 {
+		return singleResult(nil, mongo.ErrNoDocuments)
+	}
+
+	return singleResult(docs[0], nil)
+}
+
+// Find returns a cursor over the documents matching filter.
+func (c *Collection) Find(
+	ctx context.Context,
+	filter any,
+	opts ...options.Lister[options.FindOptions],
+) (*mongo.Cursor, error) {
+	args, err := resolveOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := args.Limit
+	if limit != nil && *limit < 0 {
+		limit = int64Ptr(-*limit)
+	}
+
+	docs, err := c.query(ctx, filter, args.Sort, args.Skip, limit, args.Projection)
+	if err != nil {
+		return nil, err
+	}
+
+	return cursor(docs)
+}
+
+// FindOneAndUpdate updates the first document matching filter and returns it as it was
+// before the update, or after it with options.After.
+func (c *Collection) FindOneAndUpdate(
+	ctx context.Context,
+	filter any,
+	update any,
+	opts ...options.Lister[options.FindOneAndUpdateOptions],
+) *mongo.SingleResult {
+	args, err := resolveOptions(opts)
+	if err != nil {
+		return singleResult(nil, err)
+	}
+
+	returnAfter := args.ReturnDocument != nil && *args.ReturnDocument == options.After
+	res, err := c.update(ctx, filter, update, updateArgs{
+		upsert:       boolValue(args.Upsert),
+		sort:         args.Sort,
+		arrayFilters: args.ArrayFilters,
+	})
+	if err != nil {
+		return singleResult(nil, err)
+	}
+
+	doc := res.before
+	if returnAfter {
+		doc = res.after
+	}
+	if doc == nil {
+		return singleResult(nil, mongo.ErrNoDocuments)
+	}
+
+	projection, err := toDocument(args.Projection)
+	if err != nil {
+		return singleResult(nil, err)
+	}
+
+	projected, err := projectDocument(doc, projection)
+	if err != nil {
+		return singleResult(nil, err)
+	}
+
+	return singleResult(projected, nil)
+}
+
+// InsertOne inserts document, generating an ObjectID _id when it has none.
+func (c *Collection) InsertOne(
+	ctx context.Context,
+	document any,
+	_ ...options.Lister[options.InsertOneOptions],
+) (*mongo.InsertOneResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	doc, err := toDocument(document)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	id, writeErr := c.insert(doc)
+	if writeErr != nil {
+		return nil, mongo.WriteException{WriteErrors: mongo.WriteErrors{*writeErr}}
+	}
+
+	return &mongo.InsertOneResult{InsertedID: id, Acknowledged: true}, nil
+}
+
+// InsertMany inserts documents in order. Ordered inserts stop at the first failure;
+// failures are reported as a mongo.BulkWriteException.
+func (c *Collection) InsertMany(
+	ctx context.Context,
+	documents any,
+	opts ...options.Lister[options.InsertManyOptions],
+) (*mongo.InsertManyResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	args, err := resolveOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	docs, err := toArray(documents)
+	if err != nil {
+		return nil, err
+	}
+
+	ordered := args.Ordered == nil || *args.Ordered
+	result := &mongo.InsertManyResult{Acknowledged: true}
+	failures := []mongo.BulkWriteError{}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i, item := range docs {
+		doc, ok := item.(bson.D)
+		if !ok {
+			return nil, fmt.Errorf("memdb: document at index %d is not a document", i)
+		}
+
+		id, writeErr := c.insert(doc)
+		if writeErr != nil {
+			writeErr.Index = i
+			failures = append(failures, mongo.BulkWriteError{
+				WriteError: *writeErr,
+				Request:    mongo.NewInsertOneModel().SetDocument(doc),
+			})
+			if ordered {
+				break
+			}
+			continue
+		}
+
+		result.InsertedIDs = append(result.InsertedIDs, id)
+	}
+
+	if len(failures) > 0 {
+		return result, mongo.BulkWriteException{WriteErrors: failures}
+	}
+
+	return result, nil
+}
+
+// UpdateOne updates the first document matching filter.
+func (c *Collection) UpdateOne(
+	ctx context.Context,
+	filter any,
+	update any,
+	opts ...options.Lister[options.UpdateOneOptions],
+) (*mongo.UpdateResult, error) {
+	args, err := resolveOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.update(ctx, filter, update, updateArgs{
+		upsert:       boolValue(args.Upsert),
+		sort:         args.Sort,
+		arrayFilters: args.ArrayFilters,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.updateResult(), nil
+}
+
+// UpdateMany updates every document matching filter.
+func (c *Collection) UpdateMany(
+	ctx context.Context,
+	filter any,
+	update any,
+	opts ...options.Lister[options.UpdateManyOptions],
+) (*mongo.UpdateResult, error) {
+	args, err := resolveOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.update(ctx, filter, update, updateArgs{
+		multi:        true,
+		upsert:       boolValue(args.Upsert),
+		arrayFilters: args.ArrayFilters,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.updateResult(), nil
+}
+
+// DeleteOne deletes the first document matching filter.
+func (c *Collection) DeleteOne(
+	ctx context.Context,
+	filter any,
+	_ ...options.Lister[options.DeleteOneOptions],
+) (*mongo.DeleteResult, error) {
+	deleted, err := c.delete(ctx, filter, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return &mongo.DeleteResult{DeletedCount: deleted, Acknowledged: true}, nil
+}
+
+// DeleteMany deletes every document matching filter.
+func (c *Collection) DeleteMany(
+	ctx context.Context,
+	filter any,
+	_ ...options.Lister[options.DeleteManyOptions],
+) (*mongo.DeleteResult, error) {
+	deleted, err := c.delete(ctx, filter, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return &mongo.DeleteResult{DeletedCount: deleted, Acknowledged: true}, nil
+}
+
+// BulkWrite executes the write models in order. Ordered writes stop at the first failure;
+// failures are reported as a mongo.BulkWriteException.
+func (c *Collection) BulkWrite(
+	ctx context.Context,
+	models []mongo.WriteModel,
+	opts ...options.Lister[options.BulkWriteOptions],
+) (*mongo.BulkWriteResult, error) {
+	args, err := resolveOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	ordered := args.Ordered == nil || *args.Ordered
+	result := &mongo.BulkWriteResult{UpsertedIDs: map[int64]any{}, Acknowledged: true}
+	failures := []mongo.BulkWriteError{}
+
+	for i, model := range models {
+		if err := c.writeModel(ctx, model, int64(i), result); err != nil {
+			writeErr, ok := err.(*mongo.WriteError)
+			if !ok {
+				return nil, err
+			}
+
+			writeErr.Index = i
+			failures = append(failures, mongo.BulkWriteError{WriteError: *writeErr, Request: model})
+			if ordered {
+				break
+			}
+		}
+	}
+
+	if len(failures) > 0 {
+		return result, mongo.BulkWriteException{WriteErrors: failures}
+	}
+
+	return result, nil
+}
+
+// Aggregate runs pipeline over the collection. Supported stages are $match, $sort, $skip,
+// $limit, $project, $addFields/$set, $unset, $unwind, $group, $count, $lookup and $facet.
+func (c *Collection) Aggregate(
+	ctx context.Context,
+	pipeline any,
+	_ ...options.Lister[options.AggregateOptions],
+) (*mongo.Cursor, error) {
+	stages, err := toArray(pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	docs, err := c.query(ctx, nil, nil, nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	docs, err = runPipeline(ctx, c.db, docs, stages)
+	if err != nil {
+		return nil, err
+	}
+
+	return cursor(docs)
+}
+
+// Watch is not supported: the in-memory backend has no oplog to stream changes from.
+func (c *Collection) Watch(
+	_ context.Context,
+	_ any,
+	_ ...options.Lister[options.ChangeStreamOptions],
+) (*mongo.ChangeStream, error) {
+	return nil, unsupportedf("change streams")
+}
+
+// Distinct returns the distinct values of fieldName among the documents matching
+// filter. Array values contribute each of their elements.
+func (c *Collection) Distinct(
+	ctx context.Context,
+	fieldName string,
+	filter any,
+	_ ...options.Lister[options.DistinctOptions],
+) mongorm.DistinctResult {
+	docs, err := c.query(ctx, filter, nil, nil, nil, nil)
+	if err != nil {
+		return &distinctResult{err: err}
+	}
+
+	values := bson.A{}
+	for _, doc := range docs {
+		for _, value := range expandDistinct(resolvePath(doc, splitPath(fieldName))) {
+			if !containsValue(values, value) {
+				values = append(values, value)
+			}
+		}
+	}
+
+	return &distinctResult{values: values}
+}
+
+// CountDocuments counts the documents matching filter.
+func (c *Collection) CountDocuments(
+	ctx context.Context,
+	filter any,
+	opts ...options.Lister[options.CountOptions],
+) (int64, error) {
+	args, err := resolveOptions(opts)
+	if err != nil {
+		return 0, err
+	}
+
+	docs, err := c.query(ctx, filter, nil, args.Skip, args.Limit, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	return int64(len(docs)), nil
+}
+
+// Indexes returns the index view of the collection. Unique indexes are enforced on
+// every write.
+func (c *Collection) Indexes() mongorm.IndexView {
+	return &indexView{coll: c}
+}
+
+// query returns copies of the documents matching filter after sorting, skipping,
+// limiting and projecting them.
+func (c *Collection) query(
+	ctx context.Context,
+	filter any,
+	sort any,
+	skip *int64,
+	limit *int64,
+	projection any,
+) ([]bson.D, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	filterDoc, err := toDocument(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	sortDoc, err := toDocument(sort)
+	if err != nil {
+		return nil, err
+	}
+
+	projectionDoc, err := toDocument(projection)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	matched, err := c.matching(filterDoc)
+	docs := make([]bson.D, len(matched))
+	for i, index := range matched {
+		docs[i] = cloneDocument(c.docs[index])
+	}
+	c.mu.Unlock()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := sortDocuments(docs, sortDoc); err != nil {
+		return nil, err
+	}
+
+	if skip != nil && *skip > 0 {
+		docs = docs[min(int(*skip), len(docs)):]
+	}
+
+	if limit != nil && *limit > 0 && int(*limit) < len(docs) {
+		docs = docs[:*limit]
+	}
+
+	for i, doc := range docs {
+		if docs[i], err = projectDocument(doc, projectionDoc); err != nil {
+			return nil, err
+		}
+	}
+
+	return docs, nil
+}
+
+// matching returns the positions of the stored documents matching filter, in natural
+// order. The caller must hold c.mu.
+func (c *Collection) matching(filter bson.D) ([]int, error) {
+	positions := []int{}
+	for i, doc := range c.docs {
+		ok, err := matchDocument(doc, filter)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			positions = append(positions, i)
+		}
+	}
+
+	return positions, nil
+}
+
+// insert stores doc after assigning an _id and checking unique indexes. The caller must
+// hold c.mu.
+func (c *Collection) insert(doc bson.D) (any, *mongo.WriteError) {
+	id, ok := lookupKey(doc, "_id")
+	if !ok {
+		id = bson.NewObjectID()
+		doc = append(bson.D{{Key: "_id", Value: id}}, doc...)
+	}
+
+	if writeErr := c.checkUnique(doc, -1); writeErr != nil {
+		return nil, writeErr
+	}
+
+	c.docs = append(c.docs, cloneDocument(doc))
+
+	return id, nil
+}
+
+type updateArgs struct {
+	multi        bool
+	upsert       bool
+	sort         any
+	arrayFilters []any
+}
+
+type updateOutcome struct {
+	matched    int64
+	modified   int64
+	upsertedID any
+	before     bson.D
+	after      bson.D
+}
+
+func (o *updateOutcome) updateResult() *mongo.UpdateResult {
+	res := &mongo.UpdateResult{
+		MatchedCount:  o.matched,
+		ModifiedCount: o.modified,
+		UpsertedID:    o.upsertedID,
+		Acknowledged:  true,
+	}
+	if o.upsertedID != nil {
+		res.UpsertedCount = 1
+	}
+
+	return res
+}
+
+// update applies update to the first (or every, with multi) document matching filter,
+// inserting a new document for upserts without a match. Write errors are returned as a
+// mongo.WriteException.
+func (c *Collection) update(ctx context.Context, filter any, update any, args updateArgs) (*updateOutcome, error) {
+	res, err := c.updateDocuments(ctx, filter, update, args)
+	if writeErr, ok := err.(*mongo.WriteError); ok {
+		return nil, mongo.WriteException{WriteErrors: mongo.WriteErrors{*writeErr}}
+	}
+
+	return res, err
+}
+
+func (c *Collection) updateDocuments(
+	ctx context.Context,
+	filter any,
+	update any,
+	args updateArgs,
+) (*updateOutcome, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	filterDoc, err := toDocument(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	updateDoc, err := toUpdateDocument(update)
+	if err != nil {
+		return nil, err
+	}
+
+	sortDoc, err := toDocument(args.sort)
+	if err != nil {
+		return nil, err
+	}
+
+	arrayFilters := bson.A{}
+	if len(args.arrayFilters) > 0 {
+		if arrayFilters, err = toArray(args.arrayFilters); err != nil {
+			return nil, err
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	positions, err := c.matching(filterDoc)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sortDoc) > 0 && len(positions) > 1 {
+		order, err := sortOrder(sortDoc)
+		if err != nil {
+			return nil, err
+		}
+		slices.SortStableFunc(positions, func(a, b int) int { return order(c.docs[a], c.docs[b]) })
+	}
+
+	if !args.multi && len(positions) > 1 {
+		positions = positions[:1]
+	}
+
+	outcome := &updateOutcome{}
+	apply := &updater{filter: filterDoc, arrayFilters: arrayFilters}
+
+	for _, position := range positions {
+		before := c.docs[position]
+		after, err := apply.apply(before, updateDoc)
+		if err != nil {
+			return nil, err
+		}
+
+		beforeID, _ := lookupKey(before, "_id")
+		if afterID, _ := lookupKey(after, "_id"); !valuesEqual(beforeID, afterID) {
+			return nil, &mongo.WriteError{Code: 66, Message: "Performing an update on the path '_id' would modify the immutable field '_id'"}
+		}
+
+		if writeErr := c.checkUnique(after, position); writeErr != nil {
+			return nil, writeErr
+		}
+
+		outcome.matched++
+		if compareValues(before, after) != 0 {
+			outcome.modified++
+			c.docs[position] = after
+		}
+
+		outcome.before, outcome.after = cloneDocument(before), cloneDocument(after)
+	}
+
+	if len(positions) > 0 || !args.upsert {
+		return outcome, nil
+	}
+
+	seed, err := upsertSeed(filterDoc)
+	if err != nil {
+		return nil, err
+	}
+
+	inserting := &updater{filter: filterDoc, arrayFilters: arrayFilters, inserting: true}
+	doc, err := inserting.apply(seed, updateDoc)
+	if err != nil {
+		return nil, err
+	}
+
+	id, writeErr := c.insert(doc)
+	if writeErr != nil {
+		return nil, writeErr
+	}
+
+	stored, _ := c.find(id)
+	outcome.upsertedID = id
+	outcome.after = cloneDocument(stored)
+
+	return outcome, nil
+}
+
+// find returns the stored document with the given _id. The caller must hold c.mu.
+func (c *Collection) find(id any) (bson.D, bool) {
+	for _, doc := range c.docs {
+		if docID, _ := lookupKey(doc, "_id"); valuesEqual(docID, id) {
+			return doc, true
+		}
+	}
+
+	return nil, false
+}
+
+func (c *Collection) delete(ctx context.Context, filter any, multi bool) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	filterDoc, err := toDocument(filter)
+	if err != nil {
+		return 0, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	positions, err := c.matching(filterDoc)
+	if err != nil {
+		return 0, err
+	}
+
+	if !multi && len(positions) > 1 {
+		positions = positions[:1]
+	}
+
+	for i := len(positions) - 1; i >= 0; i-- {
+		c.docs = slices.Delete(c.docs, positions[i], positions[i]+1)
+	}
+
+	return int64(len(positions)), nil
+}
+
+// writeModel executes one bulk write model and adds its outcome to result. Write errors
+// are returned as *mongo.WriteError.
+func (c *Collection) writeModel(ctx context.Context, model mongo.WriteModel, index int64, result *mongo.BulkWriteResult) error {
+	switch m := model.(type) {
+	case *mongo.InsertOneModel:
+		doc, err := toDocument(m.Document)
+		if err != nil {
+			return err
+		}
+
+		c.mu.Lock()
+		_, writeErr := c.insert(doc)
+		c.mu.Unlock()
+		if writeErr != nil {
+			return writeErr
+		}
+
+		result.InsertedCount++
+		return nil
+	case *mongo.UpdateOneModel, *mongo.UpdateManyModel, *mongo.ReplaceOneModel:
+		var filter, update any
+		args := updateArgs{}
+		switch u := m.(type) {
+		case *mongo.UpdateOneModel:
+			filter, update = u.Filter, u.Update
+			args = updateArgs{upsert: boolValue(u.Upsert), sort: u.Sort, arrayFilters: u.ArrayFilters}
+		case *mongo.UpdateManyModel:
+			filter, update = u.Filter, u.Update
+			args = updateArgs{multi: true, upsert: boolValue(u.Upsert), arrayFilters: u.ArrayFilters}
+		case *mongo.ReplaceOneModel:
+			replacement, err := toDocument(u.Replacement)
+			if err != nil {
+				return err
+			}
+			if isUpdateDocument(replacement) {
+				return fmt.Errorf("memdb: replacement document cannot contain update operators")
+			}
+			filter, update = u.Filter, replacement
+			args = updateArgs{upsert: boolValue(u.Upsert), sort: u.Sort}
+		}
+
+		res, err := c.updateDocuments(ctx, filter, update, args)
+		if err != nil {
+			return err
+		}
+
+		result.MatchedCount += res.matched
+		result.ModifiedCount += res.modified
+		if res.upsertedID != nil {
+			result.UpsertedCount++
+			result.UpsertedIDs[index] = res.upsertedID
+		}
+		return nil
+	case *mongo.DeleteOneModel:
+		deleted, err := c.delete(ctx, m.Filter, false)
+		result.DeletedCount += deleted
+		return err
+	case *mongo.DeleteManyModel:
+		deleted, err := c.delete(ctx, m.Filter, true)
+		result.DeletedCount += deleted
+		return err
+	default:
+		return unsupportedf("write model %T", model)
+	}
+}
+
+// toUpdateDocument normalizes an update. Update pipelines are not supported.
+func toUpdateDocument(update any) (bson.D, error) {
+	switch update.(type) {
+	case bson.A, []bson.D, []bson.M, mongo.Pipeline:
+		return nil, unsupportedf("update pipelines")
+	}
+
+	doc, err := toDocument(update)
+	if err != nil {
+		return nil, err
+	}
+	if len(doc) == 0 {
+		return nil, fmt.Errorf("memdb: update document must not be empty")
+	}
+
+	return doc, nil
+}
+
+// expandDistinct flattens array values one level, as the distinct command does.
+func expandDistinct(values []any) []any {
+	out := []any{}
+	for _, value := range values {
+		if arr, ok := value.(bson.A); ok {
+			out = append(out, arr...)
+			continue
+		}
+		out = append(out, value)
+	}
+
+	return out
+}
+
+// distinctResult implements mongorm.DistinctResult.
+type distinctResult struct {
+	values bson.A
+	err    error
+}
+
+func (r *distinctResult) Decode(v any) error {
+	if r.err != nil {
+		return r.err
+	}
+
+	return decodeInto(r.values, v)
+}
+
+func (r *distinctResult) Err() error {
+	return r.err
+}
+
+// resolveOptions merges driver option builders into their options struct.
+func resolveOptions[T any](opts []options.Lister[T]) (*T, error) {
+	args := new(T)
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		if value := reflect.ValueOf(opt); value.Kind() == reflect.Pointer && value.IsNil() {
+			continue
+		}
+
+		for _, setter := range opt.List() {
+			if err := setter(args); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return args, nil
+}
+
+func cursor(docs []bson.D) (*mongo.Cursor, error) {
+	values := make([]any, len(docs))
+	for i, doc := range docs {
+		values[i] = doc
+	}
+
+	return mongo.NewCursorFromDocuments(values, nil, nil)
+}
+
+func singleResult(doc bson.D, err error) *mongo.SingleResult {
+	if doc == nil {
+		doc = bson.D{}
+	}
+
+	return mongo.NewSingleResultFromDocument(doc, err, nil)
+}
+
+func boolValue(value *bool) bool {
+	return value != nil && *value
+}
+
+func int64Ptr(value int64) *int64 {
+	return &value
+}
@@ -0,0 +1,297 @@
+package memdb
+
+import (
+	"strings"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// matchDocument reports whether doc satisfies the normalized query filter.
+func matchDocument(doc bson.D, filter bson.D) (bool, error) {
+	for _, e := range filter {
+		ok, err := matchFilterElement(doc, e)
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+func matchFilterElement(doc bson.D, e bson.E) (bool, error) {
+	switch e.Key {
+	case "$and", "$or", "$nor":
+		clauses, ok := e.Value.(bson.A)
+		if !ok || len(clauses) == 0 {
+			return false, unsupportedf("%s requires a non-empty array", e.Key)
+		}
+
+		for _, clause := range clauses {
+			sub, ok := clause.(bson.D)
+			if !ok {
+				return false, unsupportedf("%s entries must be documents", e.Key)
+			}
+
+			matched, err := matchDocument(doc, sub)
+			if err != nil {
+				return false, err
+			}
+
+			switch {
+			case e.Key == "$and" && !matched:
+				return false, nil
+			case e.Key == "$or" && matched:
+				return true, nil
+			case e.Key == "$nor" && matched:
+				return false, nil
+			}
+		}
+
+		return e.Key != "$or", nil
+	case "$comment":
+		return true, nil
+	}
+
+	if strings.HasPrefix(e.Key, "$") {
+		return false, unsupportedf("query operator %s", e.Key)
+	}
+
+	return matchCondition(resolvePath(doc, splitPath(e.Key)), e.Value)
+}
+
+// matchCondition evaluates a field condition against the values found at the field path.
+// The condition is either an operator document ({"$gt": 1}) or a value to compare for
+// equality.
+func matchCondition(values []any, condition any) (bool, error) {
+	ops, ok := isOperatorDocument(condition)
+	if !ok {
+		return matchEquality(values, condition)
+	}
+
+	for _, op := range ops {
+		if op.Key == "$options" {
+			continue
+		}
+
+		matched, err := matchOperator(values, op, ops)
+		if err != nil || !matched {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+func matchEquality(values []any, operand any) (bool, error) {
+	if regex, ok := operand.(bson.Regex); ok {
+		return matchRegex(values, regex.Pattern, regex.Options)
+	}
+
+	if operand == nil && len(values) == 0 {
+		return true, nil
+	}
+
+	for _, candidate := range expandArrays(values) {
+		if valuesEqual(candidate, operand) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func matchOperator(values []any, op bson.E, siblings bson.D) (bool, error) {
+	switch op.Key {
+	case "$eq":
+		return matchEquality(values, op.Value)
+	case "$ne":
+		matched, err := matchEquality(values, op.Value)
+		return !matched, err
+	case "$gt", "$gte", "$lt", "$lte":
+		return matchRange(values, op.Key, op.Value), nil
+	case "$in", "$nin":
+		operands, ok := op.Value.(bson.A)
+		if !ok {
+			return false, unsupportedf("%s requires an array", op.Key)
+		}
+
+		matched := false
+		for _, operand := range operands {
+			ok, err := matchEquality(values, operand)
+			if err != nil {
+				return false, err
+			}
+			if ok {
+				matched = true
+				break
+			}
+		}
+
+		return matched == (op.Key == "$in"), nil
+	case "$exists":
+		return truthy(op.Value) == (len(values) > 0), nil
+	case "$regex":
+		pattern, options := "", ""
+		switch v := op.Value.(type) {
+		case string:
+			pattern = v
+		case bson.Regex:
+			pattern, options = v.Pattern, v.Options
+		default:
+			return false, unsupportedf("$regex requires a string or regular expression")
+		}
+
+		if extra, ok := lookupKey(siblings, "$options"); ok {
+			options += stringOf(extra)
+		}
+
+		return matchRegex(values, pattern, options)
+	case "$size":
+		size, ok := toInt64(op.Value)
+		if !ok {
+			if f, isFloat := op.Value.(float64); isFloat && f == float64(int64(f)) {
+				size, ok = int64(f), true
+			}
+		}
+		if !ok {
+			return false, unsupportedf("$size requires an integer")
+		}
+
+		for _, value := range values {
+			if arr, ok := value.(bson.A); ok && int64(len(arr)) == size {
+				return true, nil
+			}
+		}
+
+		return false, nil
+	case "$all":
+		operands, ok := op.Value.(bson.A)
+		if !ok {
+			return false, unsupportedf("$all requires an array")
+		}
+		if len(operands) == 0 {
+			return false, nil
+		}
+
+		for _, operand := range operands {
+			if sub, ok := isOperatorDocument(operand); ok && len(sub) == 1 && sub[0].Key == "$elemMatch" {
+				matched, err := matchOperator(values, sub[0], sub)
+				if err != nil || !matched {
+					return false, err
+				}
+				continue
+			}
+
+			matched, err := matchEquality(values, operand)
+			if err != nil || !matched {
+				return false, err
+			}
+		}
+
+		return true, nil
+	case "$elemMatch":
+		condition, ok := op.Value.(bson.D)
+		if !ok {
+			return false, unsupportedf("$elemMatch requires a document")
+		}
+
+		for _, value := range values {
+			arr, ok := value.(bson.A)
+			if !ok {
+				continue
+			}
+
+			for _, item := range arr {
+				matched, err := matchElement(item, condition)
+				if err != nil {
+					return false, err
+				}
+				if matched {
+					return true, nil
+				}
+			}
+		}
+
+		return false, nil
+	case "$not":
+		var matched bool
+		var err error
+		if regex, ok := op.Value.(bson.Regex); ok {
+			matched, err = matchRegex(values, regex.Pattern, regex.Options)
+		} else if _, ok := isOperatorDocument(op.Value); ok {
+			matched, err = matchCondition(values, op.Value)
+		} else {
+			return false, unsupportedf("$not requires an operator document or regular expression")
+		}
+
+		return !matched, err
+	default:
+		return false, unsupportedf("query operator %s", op.Key)
+	}
+}
+
+// matchElement evaluates an $elemMatch condition against one array element. Conditions
+// made of operators apply to the element itself; other conditions are filters on the
+// element document.
+func matchElement(item any, condition bson.D) (bool, error) {
+	if _, ok := isOperatorDocument(condition); ok {
+		return matchCondition([]any{item}, condition)
+	}
+
+	doc, ok := item.(bson.D)
+	if !ok {
+		return false, nil
+	}
+
+	return matchDocument(doc, condition)
+}
+
+func matchRange(values []any, op string, operand any) bool {
+	for _, candidate := range expandArrays(values) {
+		if typeOrder(candidate) != typeOrder(operand) {
+			continue
+		}
+
+		order := compareValues(candidate, operand)
+		switch {
+		case op == "$gt" && order > 0,
+			op == "$gte" && order >= 0,
+			op == "$lt" && order < 0,
+			op == "$lte" && order <= 0:
+			return true
+		}
+	}
+
+	return false
+}
+
+func matchRegex(values []any, pattern, options string) (bool, error) {
+	re, err := compileRegex(pattern, options)
+	if err != nil {
+		return false, err
+	}
+
+	for _, candidate := range expandArrays(values) {
+		if s, ok := candidate.(string); ok && re.MatchString(s) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// truthy follows the MongoDB rules for boolean operands such as $exists: false, null,
+// and zero numbers are false, everything else is true.
+func truthy(value any) bool {
+	switch v := value.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	default:
+		if f, ok := toFloat(value); ok {
+			return f != 0
+		}
+		return true
+	}
+}
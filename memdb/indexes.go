@@ -0,0 +1,436 @@
+package memdb
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const idIndexName = "_id_"
+
+// index is an index definition. Only unique indexes affect writes.
+type index struct {
+	name    string
+	keys    bson.D
+	unique  bool
+	sparse  bool
+	partial bson.D
+	spec    bson.D
+}
+
+func idIndex() *index {
+	keys := bson.D{{Key: "_id", Value: int32(1)}}
+
+	return &index{
+		name:   idIndexName,
+		keys:   keys,
+		unique: true,
+		spec:   bson.D{{Key: "v", Value: int32(2)}, {Key: "key", Value: keys}, {Key: "name", Value: idIndexName}},
+	}
+}
+
+// newIndex builds an index from a driver index model, generating the name from the keys
+// like the server does when none is given.
+func newIndex(model mongo.IndexModel) (*index, error) {
+	keys, err := toDocument(model.Keys)
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("memdb: index keys must not be empty")
+	}
+
+	args := &options.IndexOptions{}
+	if model.Options != nil {
+		for _, setter := range model.Options.List() {
+			if err := setter(args); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	idx := &index{keys: keys}
+
+	if args.Name != nil {
+		idx.name = *args.Name
+	} else {
+		parts := make([]string, 0, len(keys)*2)
+		for _, key := range keys {
+			parts = append(parts, key.Key, fmt.Sprint(key.Value))
+		}
+		idx.name = strings.Join(parts, "_")
+	}
+
+	idx.spec = bson.D{{Key: "v", Value: int32(2)}, {Key: "key", Value: specKeys(keys)}, {Key: "name", Value: idx.name}}
+
+	if args.Unique != nil && *args.Unique {
+		idx.unique = true
+		idx.spec = append(idx.spec, bson.E{Key: "unique", Value: true})
+	}
+	if args.Sparse != nil && *args.Sparse {
+		idx.sparse = true
+		idx.spec = append(idx.spec, bson.E{Key: "sparse", Value: true})
+	}
+	if args.ExpireAfterSeconds != nil {
+		idx.spec = append(idx.spec, bson.E{Key: "expireAfterSeconds", Value: *args.ExpireAfterSeconds})
+	}
+	if args.PartialFilterExpression != nil {
+		filter, err := toDocument(args.PartialFilterExpression)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := matchDocument(bson.D{}, filter); err != nil {
+			return nil, err
+		}
+		idx.partial = filter
+		idx.spec = append(idx.spec, bson.E{Key: "partialFilterExpression", Value: filter})
+	}
+	if args.Collation != nil {
+		idx.spec = append(idx.spec, bson.E{Key: "collation", Value: collationDocument(args.Collation)})
+	}
+	if args.Hidden != nil && *args.Hidden {
+		idx.spec = append(idx.spec, bson.E{Key: "hidden", Value: true})
+	}
+	if args.WildcardProjection != nil {
+		projection, err := toDocument(args.WildcardProjection)
+		if err != nil {
+			return nil, err
+		}
+		idx.spec = append(idx.spec, bson.E{Key: "wildcardProjection", Value: projection})
+	}
+
+	if weights := textWeights(keys, args); len(weights) > 0 {
+		language, override := "english", "language"
+		if args.DefaultLanguage != nil {
+			language = *args.DefaultLanguage
+		}
+		if args.LanguageOverride != nil {
+			override = *args.LanguageOverride
+		}
+		idx.spec = append(idx.spec,
+			bson.E{Key: "weights", Value: weights},
+			bson.E{Key: "default_language", Value: language},
+			bson.E{Key: "language_override", Value: override},
+			bson.E{Key: "textIndexVersion", Value: int32(3)},
+		)
+	}
+
+	return idx, nil
+}
+
+// collationDocument converts a collation to the document form the server reports,
+// keeping only the fields that are set.
+func collationDocument(c *options.Collation) bson.D {
+	doc := bson.D{}
+	add := func(name string, value any, set bool) {
+		if set {
+			doc = append(doc, bson.E{Key: name, Value: value})
+		}
+	}
+
+	add("locale", c.Locale, c.Locale != "")
+	add("caseLevel", c.CaseLevel, c.CaseLevel)
+	add("caseFirst", c.CaseFirst, c.CaseFirst != "")
+	add("strength", int32(c.Strength), c.Strength != 0)
+	add("numericOrdering", c.NumericOrdering, c.NumericOrdering)
+	add("alternate", c.Alternate, c.Alternate != "")
+	add("maxVariable", c.MaxVariable, c.MaxVariable != "")
+	add("normalization", c.Normalization, c.Normalization)
+	add("backwards", c.Backwards, c.Backwards)
+
+	return doc
+}
+
+// specKeys returns keys as the server reports them, with the text keys replaced by the
+// _fts/_ftsx pair.
+func specKeys(keys bson.D) bson.D {
+	out := bson.D{}
+	inserted := false
+
+	for _, key := range keys {
+		if key.Value != "text" {
+			out = append(out, key)
+			continue
+		}
+		if !inserted {
+			out = append(out, bson.E{Key: "_fts", Value: "text"}, bson.E{Key: "_ftsx", Value: int32(1)})
+			inserted = true
+		}
+	}
+
+	return out
+}
+
+// textWeights returns the weights of the text keys, sorted by field name, or nil when
+// the index has no text keys.
+func textWeights(keys bson.D, args *options.IndexOptions) bson.D {
+	weights := bson.D{}
+	for _, key := range keys {
+		if key.Value == "text" {
+			weights = append(weights, bson.E{Key: key.Key, Value: int32(1)})
+		}
+	}
+	if len(weights) == 0 {
+		return nil
+	}
+
+	if custom, err := toDocument(args.Weights); err == nil {
+		for _, weight := range custom {
+			position := slices.IndexFunc(weights, func(e bson.E) bool { return e.Key == weight.Key })
+			if position < 0 {
+				weights = append(weights, weight)
+			} else {
+				weights[position].Value = weight.Value
+			}
+		}
+	}
+
+	slices.SortFunc(weights, func(a, b bson.E) int { return strings.Compare(a.Key, b.Key) })
+
+	return weights
+}
+
+// key returns the index key of doc and whether the document is indexed at all.
+// Documents outside the partial filter expression are not indexed.
+func (idx *index) key(doc bson.D) (bson.A, bool) {
+	if idx.partial != nil {
+		if matched, err := matchDocument(doc, idx.partial); err != nil || !matched {
+			return nil, false
+		}
+	}
+
+	key := make(bson.A, len(idx.keys))
+	present := false
+
+	for i, field := range idx.keys {
+		value, ok := firstValue(doc, field.Key)
+		key[i] = value
+		present = present || ok
+	}
+
+	return key, present || !idx.sparse
+}
+
+// checkUnique reports a duplicate key write error when doc collides with a stored
+// document other than the one at position skip. The caller must hold c.mu.
+func (c *Collection) checkUnique(doc bson.D, skip int) *mongo.WriteError {
+	for _, idx := range c.indexes {
+		if !idx.unique {
+			continue
+		}
+
+		if writeErr := c.checkIndex(idx, doc, skip); writeErr != nil {
+			return writeErr
+		}
+	}
+
+	return nil
+}
+
+func (c *Collection) checkIndex(idx *index, doc bson.D, skip int) *mongo.WriteError {
+	key, indexed := idx.key(doc)
+	if !indexed {
+		return nil
+	}
+
+	for i, stored := range c.docs {
+		if i == skip {
+			continue
+		}
+
+		other, indexed := idx.key(stored)
+		if indexed && compareValues(key, other) == 0 {
+			return duplicateKeyError(c, idx, key)
+		}
+	}
+
+	return nil
+}
+
+// duplicateKeyError builds the write error the server reports when key collides on idx,
+// including the keyPattern and keyValue fields.
+func duplicateKeyError(c *Collection, idx *index, key bson.A) *mongo.WriteError {
+	message := fmt.Sprintf(
+		"E11000 duplicate key error collection: %s.%s index: %s dup key: %v",
+		c.db.name,
+		c.name,
+		idx.name,
+		key,
+	)
+
+	keyValue := make(bson.D, len(idx.keys))
+	for i, field := range idx.keys {
+		keyValue[i] = bson.E{Key: field.Key, Value: key[i]}
+	}
+
+	raw, _ := bson.Marshal(bson.D{
+		{Key: "code", Value: int32(11000)},
+		{Key: "errmsg", Value: message},
+		{Key: "keyPattern", Value: idx.keys},
+		{Key: "keyValue", Value: keyValue},
+	})
+
+	return &mongo.WriteError{Code: 11000, Message: message, Raw: raw}
+}
+
+// indexView implements mongorm.IndexView for a Collection.
+type indexView struct {
+	coll *Collection
+}
+
+func (v *indexView) List(ctx context.Context, _ ...options.Lister[options.ListIndexesOptions]) (*mongo.Cursor, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	v.coll.mu.Lock()
+	specs := make([]bson.D, len(v.coll.indexes))
+	for i, idx := range v.coll.indexes {
+		specs[i] = cloneDocument(idx.spec)
+	}
+	v.coll.mu.Unlock()
+
+	return cursor(specs)
+}
+
+func (v *indexView) ListSpecifications(
+	ctx context.Context,
+	opts ...options.Lister[options.ListIndexesOptions],
+) ([]mongo.IndexSpecification, error) {
+	cur, err := v.List(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp []struct {
+		Name               string   `bson:"name"`
+		KeysDocument       bson.Raw `bson:"key"`
+		Version            int32    `bson:"v"`
+		ExpireAfterSeconds *int32   `bson:"expireAfterSeconds"`
+		Sparse             *bool    `bson:"sparse"`
+		Unique             *bool    `bson:"unique"`
+	}
+	if err := cur.All(ctx, &resp); err != nil {
+		return nil, err
+	}
+
+	specs := make([]mongo.IndexSpecification, len(resp))
+	for i, spec := range resp {
+		specs[i] = mongo.IndexSpecification{
+			Name:               spec.Name,
+			Namespace:          v.coll.db.name + "." + v.coll.name,
+			KeysDocument:       spec.KeysDocument,
+			Version:            spec.Version,
+			ExpireAfterSeconds: spec.ExpireAfterSeconds,
+			Sparse:             spec.Sparse,
+			Unique:             spec.Unique,
+		}
+	}
+
+	return specs, nil
+}
+
+func (v *indexView) CreateOne(
+	ctx context.Context,
+	model mongo.IndexModel,
+	opts ...options.Lister[options.CreateIndexesOptions],
+) (string, error) {
+	names, err := v.CreateMany(ctx, []mongo.IndexModel{model}, opts...)
+	if err != nil {
+		return "", err
+	}
+
+	return names[0], nil
+}
+
+// CreateMany creates the indexes. Creating an index that already exists with the same
+// keys and options is a no-op; a unique index fails when stored documents already
+// collide.
+func (v *indexView) CreateMany(
+	ctx context.Context,
+	models []mongo.IndexModel,
+	_ ...options.Lister[options.CreateIndexesOptions],
+) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	created := make([]*index, len(models))
+	for i, model := range models {
+		idx, err := newIndex(model)
+		if err != nil {
+			return nil, err
+		}
+		created[i] = idx
+	}
+
+	v.coll.mu.Lock()
+	defer v.coll.mu.Unlock()
+
+	names := make([]string, len(created))
+	for i, idx := range created {
+		names[i] = idx.name
+
+		position := slices.IndexFunc(v.coll.indexes, func(existing *index) bool { return existing.name == idx.name })
+		if position >= 0 {
+			if compareValues(v.coll.indexes[position].spec, idx.spec) != 0 {
+				return nil, fmt.Errorf("memdb: an index named %q already exists with different options", idx.name)
+			}
+			continue
+		}
+
+		if idx.unique {
+			for j, doc := range v.coll.docs {
+				if writeErr := v.coll.checkIndex(idx, doc, j); writeErr != nil {
+					return nil, mongo.CommandError{Code: 11000, Message: writeErr.Message, Name: "DuplicateKey", Raw: writeErr.Raw}
+				}
+			}
+		}
+
+		v.coll.indexes = append(v.coll.indexes, idx)
+	}
+
+	return names, nil
+}
+
+func (v *indexView) DropOne(ctx context.Context, name string, _ ...options.Lister[options.DropIndexesOptions]) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if name == "*" {
+		return mongo.ErrMultipleIndexDrop
+	}
+	if name == idIndexName {
+		return fmt.Errorf("memdb: cannot drop the _id index")
+	}
+
+	v.coll.mu.Lock()
+	defer v.coll.mu.Unlock()
+
+	position := slices.IndexFunc(v.coll.indexes, func(idx *index) bool { return idx.name == name })
+	if position < 0 {
+		return mongo.CommandError{Code: 27, Message: "index not found with name [" + name + "]", Name: "IndexNotFound"}
+	}
+
+	v.coll.indexes = slices.Delete(v.coll.indexes, position, position+1)
+
+	return nil
+}
+
+func (v *indexView) DropAll(ctx context.Context, _ ...options.Lister[options.DropIndexesOptions]) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	v.coll.mu.Lock()
+	defer v.coll.mu.Unlock()
+
+	v.coll.indexes = []*index{idIndex()}
+
+	return nil
+}
@@ -0,0 +1,82 @@
+// Package memdb provides an in-memory backend for MongORM. It stores documents in process
+// memory and evaluates filters, updates, sorting, projection and the common aggregation
+// stages itself, so code built on MongORM can be tested without a MongoDB server.
+//
+// Example usage:
+//
+//	db := memdb.New("test")
+//	orm := mongorm.FromOptions(&ToDo{}, &mongorm.MongORMOptions{
+//	    Backend:        db,
+//	    CollectionName: mongorm.String("todos"),
+//	})
+//
+// The backend covers the operators MongORM generates. Operators it does not implement,
+// such as geospatial queries or $where, fail with ErrUnsupported instead of being
+// silently ignored. Transactions and change streams are not available.
+package memdb
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/azayn-labs/mongorm"
+)
+
+// ErrUnsupported is returned when a filter, update, projection or pipeline uses a feature
+// the in-memory backend does not implement.
+var ErrUnsupported = errors.New("memdb: unsupported operation")
+
+// unsupportedf returns an error wrapping ErrUnsupported.
+func unsupportedf(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", ErrUnsupported, fmt.Sprintf(format, args...))
+}
+
+// Database is an in-memory database. It implements mongorm.Backend and is safe for
+// concurrent use.
+type Database struct {
+	name        string
+	mu          sync.Mutex
+	collections map[string]*Collection
+}
+
+var _ mongorm.Backend = (*Database)(nil)
+
+// New creates an empty in-memory database with the given name.
+func New(name string) *Database {
+	return &Database{
+		name:        name,
+		collections: map[string]*Collection{},
+	}
+}
+
+// Name returns the database name.
+func (d *Database) Name() string {
+	return d.name
+}
+
+// Collection returns the collection with the given name, creating it on first use.
+func (d *Database) Collection(name string) mongorm.Collection {
+	return d.collection(name)
+}
+
+// Drop removes every collection and document from the database.
+func (d *Database) Drop() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.collections = map[string]*Collection{}
+}
+
+func (d *Database) collection(name string) *Collection {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	coll, ok := d.collections[name]
+	if !ok {
+		coll = newCollection(d, name)
+		d.collections[name] = coll
+	}
+
+	return coll
+}
@@ -0,0 +1,196 @@
+package memdb
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// sortDocuments sorts docs in place by a normalized sort specification.
+func sortDocuments(docs []bson.D, spec bson.D) error {
+	order, err := sortOrder(spec)
+	if err != nil {
+		return err
+	}
+
+	slices.SortStableFunc(docs, order)
+
+	return nil
+}
+
+// sortOrder returns the comparison function for a normalized sort specification. Missing
+// fields sort as null; callers sort stably so ties keep their insertion order.
+func sortOrder(spec bson.D) (func(a, b bson.D) int, error) {
+	for _, key := range spec {
+		if _, ok := toFloat(key.Value); !ok {
+			return nil, unsupportedf("sort on %s with %v", key.Key, key.Value)
+		}
+	}
+
+	return func(a, b bson.D) int {
+		for _, key := range spec {
+			direction, _ := toFloat(key.Value)
+			av, _ := firstValue(a, key.Key)
+			bv, _ := firstValue(b, key.Key)
+
+			order := compareValues(av, bv)
+			if direction < 0 {
+				order = -order
+			}
+			if order != 0 {
+				return order
+			}
+		}
+
+		return 0
+	}, nil
+}
+
+// projectDocument applies a normalized projection. Inclusion projections keep the listed
+// paths, exclusion projections drop them; _id is kept unless excluded explicitly.
+func projectDocument(doc bson.D, spec bson.D) (bson.D, error) {
+	if len(spec) == 0 {
+		return doc, nil
+	}
+
+	includeID := true
+	inclusion := false
+	paths := [][]string{}
+
+	for _, e := range spec {
+		if _, ok := e.Value.(bson.D); ok {
+			return nil, unsupportedf("projection operator on %s", e.Key)
+		}
+		if _, ok := e.Value.(string); ok {
+			return nil, unsupportedf("computed projection on %s", e.Key)
+		}
+
+		include := truthy(e.Value)
+		if e.Key == "_id" {
+			includeID = include
+			continue
+		}
+
+		if strings.HasSuffix(e.Key, ".$") {
+			return nil, unsupportedf("positional projection on %s", e.Key)
+		}
+
+		if len(paths) > 0 && include != inclusion {
+			return nil, fmt.Errorf("memdb: cannot mix inclusion and exclusion in a projection")
+		}
+
+		inclusion = include
+		paths = append(paths, splitPath(e.Key))
+	}
+
+	var out bson.D
+	if inclusion {
+		out = bson.D{}
+		for _, e := range doc {
+			if e.Key == "_id" {
+				if includeID {
+					out = append(out, e)
+				}
+				continue
+			}
+
+			if value, ok := includePaths(e.Value, childPaths(paths, e.Key)); ok {
+				out = append(out, bson.E{Key: e.Key, Value: value})
+			}
+		}
+	} else {
+		out = excludePaths(doc, paths)
+		if !includeID {
+			out = excludePaths(out, [][]string{{"_id"}})
+		}
+	}
+
+	return out, nil
+}
+
+// childPaths returns the remainder of the paths starting with key. A path that ends at
+// key is returned as an empty path, meaning the whole value is kept.
+func childPaths(paths [][]string, key string) [][]string {
+	out := [][]string{}
+	for _, path := range paths {
+		if path[0] == key {
+			out = append(out, path[1:])
+		}
+	}
+
+	return out
+}
+
+func includePaths(value any, paths [][]string) (any, bool) {
+	if len(paths) == 0 {
+		return nil, false
+	}
+
+	for _, path := range paths {
+		if len(path) == 0 {
+			return value, true
+		}
+	}
+
+	switch v := value.(type) {
+	case bson.D:
+		out := bson.D{}
+		for _, e := range v {
+			if sub, ok := includePaths(e.Value, childPaths(paths, e.Key)); ok {
+				out = append(out, bson.E{Key: e.Key, Value: sub})
+			}
+		}
+		return out, true
+	case bson.A:
+		out := bson.A{}
+		for _, item := range v {
+			if _, ok := item.(bson.D); !ok {
+				continue
+			}
+			if sub, ok := includePaths(item, paths); ok {
+				out = append(out, sub)
+			}
+		}
+		return out, true
+	default:
+		return nil, false
+	}
+}
+
+func excludePaths(value any, paths [][]string) bson.D {
+	doc := value.(bson.D)
+	out := bson.D{}
+
+	for _, e := range doc {
+		children := childPaths(paths, e.Key)
+		if len(children) == 0 {
+			out = append(out, e)
+			continue
+		}
+
+		if slices.ContainsFunc(children, func(path []string) bool { return len(path) == 0 }) {
+			continue
+		}
+
+		switch v := e.Value.(type) {
+		case bson.D:
+			out = append(out, bson.E{Key: e.Key, Value: excludePaths(v, children)})
+		case bson.A:
+			arr := bson.A{}
+			for _, item := range v {
+				if sub, ok := item.(bson.D); ok {
+					arr = append(arr, excludePaths(sub, children))
+				} else {
+					arr = append(arr, item)
+				}
+			}
+			out = append(out, bson.E{Key: e.Key, Value: arr})
+		default:
+			out = append(out, e)
+		}
+	}
+
+	return out
+}
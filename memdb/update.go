@@ -0,0 +1,615 @@
+package memdb
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// errPositionalNoMatch mirrors the server error for a `$` path without a matching array
+// element in the query.
+var errPositionalNoMatch = errors.New("memdb: the positional operator did not find the match needed from the query")
+
+// updater applies one normalized update document to one document.
+type updater struct {
+	filter       bson.D
+	arrayFilters bson.A
+	inserting    bool
+}
+
+// apply returns a copy of doc with the update applied. An update without operators
+// replaces the document while keeping its _id.
+func (u *updater) apply(doc bson.D, update bson.D) (bson.D, error) {
+	out := cloneDocument(doc)
+
+	if !isUpdateDocument(update) {
+		replacement := cloneDocument(update)
+		if id, ok := lookupKey(doc, "_id"); ok {
+			replacement = setKey(replacement, "_id", id)
+			replacement = moveKeyFirst(replacement, "_id")
+		}
+		return replacement, nil
+	}
+
+	for _, op := range update {
+		fields, ok := op.Value.(bson.D)
+		if !ok {
+			return nil, fmt.Errorf("memdb: %s requires a document", op.Key)
+		}
+
+		if op.Key == "$setOnInsert" && !u.inserting {
+			continue
+		}
+
+		for _, field := range fields {
+			paths, err := u.expandPath(out, splitPath(field.Key))
+			if err != nil {
+				return nil, err
+			}
+
+			for _, path := range paths {
+				out, err = u.applyOperator(out, op.Key, path, field.Value)
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	return out, nil
+}
+
+func (u *updater) applyOperator(doc bson.D, op string, path []string, operand any) (bson.D, error) {
+	current, exists := getExact(doc, path)
+
+	switch op {
+	case "$set", "$setOnInsert":
+		return setPath(doc, path, cloneValue(operand))
+	case "$unset":
+		return unsetPath(doc, path), nil
+	case "$inc", "$mul":
+		if !exists || current == nil {
+			if op == "$mul" {
+				operand = multiplyNumbers(int32(0), operand)
+			}
+			if _, ok := toFloat(operand); !ok {
+				return nil, fmt.Errorf("memdb: cannot apply %s with a non-numeric operand", op)
+			}
+			return setPath(doc, path, operand)
+		}
+
+		if _, ok := toFloat(current); !ok {
+			return nil, fmt.Errorf("memdb: cannot apply %s to non-numeric field %s", op, strings.Join(path, "."))
+		}
+		if _, ok := toFloat(operand); !ok {
+			return nil, fmt.Errorf("memdb: cannot apply %s with a non-numeric operand", op)
+		}
+
+		if op == "$inc" {
+			return setPath(doc, path, addNumbers(current, operand))
+		}
+		return setPath(doc, path, multiplyNumbers(current, operand))
+	case "$min", "$max":
+		order := compareValues(operand, current)
+		if !exists || (op == "$min" && order < 0) || (op == "$max" && order > 0) {
+			return setPath(doc, path, cloneValue(operand))
+		}
+		return doc, nil
+	case "$currentDate":
+		now := time.Now()
+		var value any = bson.NewDateTimeFromTime(now)
+		if spec, ok := operand.(bson.D); ok {
+			if kind, _ := lookupKey(spec, "$type"); kind == "timestamp" {
+				value = bson.Timestamp{T: uint32(now.Unix())}
+			}
+		}
+		return setPath(doc, path, value)
+	case "$rename":
+		target, ok := operand.(string)
+		if !ok {
+			return nil, fmt.Errorf("memdb: $rename target must be a string")
+		}
+		if !exists {
+			return doc, nil
+		}
+		doc = unsetPath(doc, path)
+		return setPath(doc, splitPath(target), current)
+	case "$push", "$addToSet", "$pull", "$pullAll", "$pop":
+		arr := bson.A{}
+		if exists && current != nil {
+			var ok bool
+			arr, ok = current.(bson.A)
+			if !ok {
+				return nil, fmt.Errorf("memdb: cannot apply %s to non-array field %s", op, strings.Join(path, "."))
+			}
+		} else if op != "$push" && op != "$addToSet" {
+			return doc, nil
+		}
+
+		updated, err := updateArray(arr, op, operand)
+		if err != nil {
+			return nil, err
+		}
+
+		return setPath(doc, path, updated)
+	default:
+		return nil, unsupportedf("update operator %s", op)
+	}
+}
+
+// updateArray applies an array update operator and returns the new array.
+func updateArray(arr bson.A, op string, operand any) (bson.A, error) {
+	out := append(bson.A{}, arr...)
+
+	switch op {
+	case "$push":
+		items, modifiers := bson.A{operand}, bson.D{}
+		if spec, ok := operand.(bson.D); ok {
+			if each, ok := lookupKey(spec, "$each"); ok {
+				list, ok := each.(bson.A)
+				if !ok {
+					return nil, fmt.Errorf("memdb: $each requires an array")
+				}
+				items, modifiers = list, spec
+			}
+		}
+
+		position := len(out)
+		if value, ok := lookupKey(modifiers, "$position"); ok {
+			p, _ := toInt64(value)
+			position = clampIndex(int(p), len(out))
+		}
+		for _, item := range items {
+			out = append(out[:position], append(bson.A{cloneValue(item)}, out[position:]...)...)
+			position++
+		}
+
+		if _, ok := lookupKey(modifiers, "$sort"); ok {
+			return nil, unsupportedf("$push modifier $sort")
+		}
+		if value, ok := lookupKey(modifiers, "$slice"); ok {
+			n, _ := toInt64(value)
+			switch {
+			case n >= 0 && int(n) < len(out):
+				out = out[:n]
+			case n < 0 && int(-n) < len(out):
+				out = out[len(out)+int(n):]
+			}
+		}
+
+		return out, nil
+	case "$addToSet":
+		items := bson.A{operand}
+		if spec, ok := operand.(bson.D); ok {
+			if each, ok := lookupKey(spec, "$each"); ok {
+				list, ok := each.(bson.A)
+				if !ok {
+					return nil, fmt.Errorf("memdb: $each requires an array")
+				}
+				items = list
+			}
+		}
+
+		for _, item := range items {
+			if !containsValue(out, item) {
+				out = append(out, cloneValue(item))
+			}
+		}
+
+		return out, nil
+	case "$pull":
+		kept := bson.A{}
+		for _, item := range out {
+			matched, err := matchPullCondition(item, operand)
+			if err != nil {
+				return nil, err
+			}
+			if !matched {
+				kept = append(kept, item)
+			}
+		}
+
+		return kept, nil
+	case "$pullAll":
+		values, ok := operand.(bson.A)
+		if !ok {
+			return nil, fmt.Errorf("memdb: $pullAll requires an array")
+		}
+
+		kept := bson.A{}
+		for _, item := range out {
+			if !containsValue(values, item) {
+				kept = append(kept, item)
+			}
+		}
+
+		return kept, nil
+	default: // $pop
+		if len(out) == 0 {
+			return out, nil
+		}
+
+		if direction, _ := toFloat(operand); direction < 0 {
+			return out[1:], nil
+		}
+
+		return out[:len(out)-1], nil
+	}
+}
+
+// matchPullCondition reports whether a $pull condition removes item. Operator
+// documents apply to the element, plain documents are filters on element documents, and
+// other values are compared for equality.
+func matchPullCondition(item any, condition any) (bool, error) {
+	if _, ok := isOperatorDocument(condition); ok {
+		return matchCondition([]any{item}, condition)
+	}
+
+	if filter, ok := condition.(bson.D); ok {
+		if doc, ok := item.(bson.D); ok {
+			return matchDocument(doc, filter)
+		}
+		return false, nil
+	}
+
+	return matchEquality([]any{item}, condition)
+}
+
+// expandPath resolves positional operators in an update path into concrete paths:
+// `$` selects the first element matched by the query, `$[]` every element and
+// `$[identifier]` the elements matched by the array filters.
+func (u *updater) expandPath(doc bson.D, parts []string) ([][]string, error) {
+	for i, part := range parts {
+		if !strings.HasPrefix(part, "$") {
+			continue
+		}
+
+		prefix := parts[:i]
+		value, _ := getExact(doc, prefix)
+		arr, _ := value.(bson.A)
+
+		indexes, err := u.positionalIndexes(doc, prefix, part, arr)
+		if err != nil {
+			return nil, err
+		}
+
+		out := [][]string{}
+		for _, index := range indexes {
+			concrete := append(append(append([]string{}, prefix...), strconv.Itoa(index)), parts[i+1:]...)
+			expanded, err := u.expandPath(doc, concrete)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, expanded...)
+		}
+
+		return out, nil
+	}
+
+	return [][]string{parts}, nil
+}
+
+func (u *updater) positionalIndexes(doc bson.D, prefix []string, part string, arr bson.A) ([]int, error) {
+	indexes := []int{}
+
+	switch {
+	case part == "$":
+		if u.inserting {
+			return nil, errPositionalNoMatch
+		}
+
+		for i, item := range arr {
+			narrowed, err := setPath(cloneDocument(doc), prefix, bson.A{item})
+			if err != nil {
+				return nil, err
+			}
+
+			matched, err := matchDocument(narrowed, u.filter)
+			if err != nil {
+				return nil, err
+			}
+			if matched {
+				return []int{i}, nil
+			}
+		}
+
+		return nil, errPositionalNoMatch
+	case part == "$[]":
+		for i := range arr {
+			indexes = append(indexes, i)
+		}
+		return indexes, nil
+	case strings.HasPrefix(part, "$[") && strings.HasSuffix(part, "]"):
+		identifier := part[2 : len(part)-1]
+		filter, err := u.arrayFilterFor(identifier)
+		if err != nil {
+			return nil, err
+		}
+
+		for i, item := range arr {
+			matched, err := matchDocument(bson.D{{Key: identifier, Value: item}}, filter)
+			if err != nil {
+				return nil, err
+			}
+			if matched {
+				indexes = append(indexes, i)
+			}
+		}
+		return indexes, nil
+	default:
+		return nil, unsupportedf("update path part %s", part)
+	}
+}
+
+// arrayFilterFor collects the array filter conditions that refer to identifier.
+func (u *updater) arrayFilterFor(identifier string) (bson.D, error) {
+	filter := bson.D{}
+	for _, entry := range u.arrayFilters {
+		doc, ok := entry.(bson.D)
+		if !ok {
+			return nil, fmt.Errorf("memdb: array filters must be documents")
+		}
+
+		for _, e := range doc {
+			if e.Key == identifier || strings.HasPrefix(e.Key, identifier+".") {
+				filter = append(filter, e)
+			}
+		}
+	}
+
+	if len(filter) == 0 {
+		return nil, fmt.Errorf("memdb: no array filter found for identifier %q", identifier)
+	}
+
+	return filter, nil
+}
+
+// upsertSeed builds the document inserted by an upsert from the equality conditions of
+// the filter.
+func upsertSeed(filter bson.D) (bson.D, error) {
+	doc := bson.D{}
+	var err error
+
+	for _, e := range filter {
+		switch {
+		case e.Key == "$and":
+			clauses, _ := e.Value.(bson.A)
+			for _, clause := range clauses {
+				sub, ok := clause.(bson.D)
+				if !ok {
+					continue
+				}
+				seed, err := upsertSeed(sub)
+				if err != nil {
+					return nil, err
+				}
+				for _, field := range seed {
+					if doc, err = setPath(doc, []string{field.Key}, field.Value); err != nil {
+						return nil, err
+					}
+				}
+			}
+		case strings.HasPrefix(e.Key, "$"):
+			continue
+		default:
+			value := e.Value
+			if ops, ok := isOperatorDocument(value); ok {
+				eq, found := lookupKey(ops, "$eq")
+				if !found {
+					continue
+				}
+				value = eq
+			}
+
+			if doc, err = setPath(doc, splitPath(e.Key), cloneValue(value)); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return doc, nil
+}
+
+// isUpdateDocument reports whether update uses update operators rather than being a
+// replacement document.
+func isUpdateDocument(update bson.D) bool {
+	return len(update) > 0 && strings.HasPrefix(update[0].Key, "$")
+}
+
+func getExact(value any, parts []string) (any, bool) {
+	for _, part := range parts {
+		switch v := value.(type) {
+		case bson.D:
+			field, ok := lookupKey(v, part)
+			if !ok {
+				return nil, false
+			}
+			value = field
+		case bson.A:
+			index, err := strconv.Atoi(part)
+			if err != nil || index < 0 || index >= len(v) {
+				return nil, false
+			}
+			value = v[index]
+		default:
+			return nil, false
+		}
+	}
+
+	return value, true
+}
+
+// setPath sets the value at path, creating intermediate documents as needed.
+func setPath(doc bson.D, parts []string, value any) (bson.D, error) {
+	updated, err := setIn(doc, parts, value)
+	if err != nil {
+		return nil, err
+	}
+
+	return updated.(bson.D), nil
+}
+
+func setIn(container any, parts []string, value any) (any, error) {
+	if len(parts) == 0 {
+		return value, nil
+	}
+
+	switch c := container.(type) {
+	case bson.D:
+		child, _ := lookupKey(c, parts[0])
+		if len(parts) > 1 && child == nil {
+			child = bson.D{}
+		}
+
+		updated, err := setIn(child, parts[1:], value)
+		if err != nil {
+			return nil, err
+		}
+
+		return setKey(c, parts[0], updated), nil
+	case bson.A:
+		index, err := strconv.Atoi(parts[0])
+		if err != nil || index < 0 {
+			return nil, fmt.Errorf("memdb: cannot create field %q in an array", parts[0])
+		}
+
+		for len(c) <= index {
+			c = append(c, nil)
+		}
+
+		child := c[index]
+		if len(parts) > 1 && child == nil {
+			child = bson.D{}
+		}
+
+		updated, err := setIn(child, parts[1:], value)
+		if err != nil {
+			return nil, err
+		}
+
+		c[index] = updated
+		return c, nil
+	default:
+		return nil, fmt.Errorf("memdb: cannot create field %q in a value of type %T", parts[0], container)
+	}
+}
+
+// unsetPath removes the value at path. Array elements are set to null, as MongoDB does.
+func unsetPath(doc bson.D, parts []string) bson.D {
+	return unsetIn(doc, parts).(bson.D)
+}
+
+func unsetIn(container any, parts []string) any {
+	switch c := container.(type) {
+	case bson.D:
+		for i, e := range c {
+			if e.Key != parts[0] {
+				continue
+			}
+
+			if len(parts) == 1 {
+				return append(c[:i:i], c[i+1:]...)
+			}
+
+			c[i].Value = unsetIn(e.Value, parts[1:])
+			return c
+		}
+	case bson.A:
+		index, err := strconv.Atoi(parts[0])
+		if err != nil || index < 0 || index >= len(c) {
+			return c
+		}
+
+		if len(parts) == 1 {
+			c[index] = nil
+		} else {
+			c[index] = unsetIn(c[index], parts[1:])
+		}
+	}
+
+	return container
+}
+
+func setKey(doc bson.D, key string, value any) bson.D {
+	for i := range doc {
+		if doc[i].Key == key {
+			doc[i].Value = value
+			return doc
+		}
+	}
+
+	return append(doc, bson.E{Key: key, Value: value})
+}
+
+func moveKeyFirst(doc bson.D, key string) bson.D {
+	for i, e := range doc {
+		if e.Key == key {
+			out := bson.D{e}
+			out = append(out, doc[:i]...)
+			return append(out, doc[i+1:]...)
+		}
+	}
+
+	return doc
+}
+
+func containsValue(arr bson.A, value any) bool {
+	for _, item := range arr {
+		if valuesEqual(item, value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func clampIndex(index, length int) int {
+	if index < 0 {
+		index += length
+	}
+
+	return max(0, min(index, length))
+}
+
+// addNumbers adds two numeric values, widening the result like MongoDB: int32 overflows
+// into int64 and any float operand produces a float.
+func addNumbers(a, b any) any {
+	ai, aInt := toInt64(a)
+	bi, bInt := toInt64(b)
+	if aInt && bInt {
+		sum := ai + bi
+		_, a32 := a.(int32)
+		_, b32 := b.(int32)
+		if a32 && b32 && sum >= math.MinInt32 && sum <= math.MaxInt32 {
+			return int32(sum)
+		}
+		return sum
+	}
+
+	af, _ := toFloat(a)
+	bf, _ := toFloat(b)
+	return af + bf
+}
+
+func multiplyNumbers(a, b any) any {
+	ai, aInt := toInt64(a)
+	bi, bInt := toInt64(b)
+	if aInt && bInt {
+		product := ai * bi
+		_, a32 := a.(int32)
+		_, b32 := b.(int32)
+		if a32 && b32 && product >= math.MinInt32 && product <= math.MaxInt32 {
+			return int32(product)
+		}
+		return product
+	}
+
+	af, _ := toFloat(a)
+	bf, _ := toFloat(b)
+	return af * bf
+}
@@ -0,0 +1,380 @@
+package memdb
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// toDocument converts any value the driver can marshal into a bson.D whose nested
+// documents are bson.D and whose arrays are bson.A.
+func toDocument(value any) (bson.D, error) {
+	if value == nil {
+		return bson.D{}, nil
+	}
+
+	raw, err := bson.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := bson.D{}
+	if err := bson.Unmarshal(raw, &doc); err != nil {
+		return nil, err
+	}
+
+	return doc, nil
+}
+
+// toArray converts a slice value (a pipeline, a list of documents) into a bson.A with
+// normalized elements.
+func toArray(value any) (bson.A, error) {
+	doc, err := toDocument(bson.D{{Key: "v", Value: value}})
+	if err != nil {
+		return nil, err
+	}
+
+	arr, ok := doc[0].Value.(bson.A)
+	if !ok {
+		return nil, fmt.Errorf("memdb: expected an array, got %T", value)
+	}
+
+	return arr, nil
+}
+
+// toValue normalizes a single value the same way document fields are normalized.
+func toValue(value any) (any, error) {
+	doc, err := toDocument(bson.D{{Key: "v", Value: value}})
+	if err != nil {
+		return nil, err
+	}
+
+	return doc[0].Value, nil
+}
+
+// decodeInto decodes a normalized value into out, which must be a pointer.
+func decodeInto(value any, out any) error {
+	raw, err := bson.Marshal(bson.D{{Key: "v", Value: value}})
+	if err != nil {
+		return err
+	}
+
+	return bson.Raw(raw).Lookup("v").Unmarshal(out)
+}
+
+// cloneValue deep-copies documents and arrays so stored documents are never shared with
+// callers.
+func cloneValue(value any) any {
+	switch v := value.(type) {
+	case bson.D:
+		return cloneDocument(v)
+	case bson.A:
+		out := make(bson.A, len(v))
+		for i, item := range v {
+			out[i] = cloneValue(item)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+func cloneDocument(doc bson.D) bson.D {
+	out := make(bson.D, len(doc))
+	for i, e := range doc {
+		out[i] = bson.E{Key: e.Key, Value: cloneValue(e.Value)}
+	}
+
+	return out
+}
+
+func lookupKey(doc bson.D, key string) (any, bool) {
+	for _, e := range doc {
+		if e.Key == key {
+			return e.Value, true
+		}
+	}
+
+	return nil, false
+}
+
+func splitPath(path string) []string {
+	return strings.Split(path, ".")
+}
+
+// resolvePath returns every value reachable through path. Arrays along the path are
+// traversed element by element, and numeric path parts index into arrays, following the
+// MongoDB query semantics.
+func resolvePath(value any, parts []string) []any {
+	if len(parts) == 0 {
+		return []any{value}
+	}
+
+	switch v := value.(type) {
+	case bson.D:
+		field, ok := lookupKey(v, parts[0])
+		if !ok {
+			return nil
+		}
+		return resolvePath(field, parts[1:])
+	case bson.A:
+		out := []any{}
+		if index, err := strconv.Atoi(parts[0]); err == nil {
+			if index >= 0 && index < len(v) {
+				out = append(out, resolvePath(v[index], parts[1:])...)
+			}
+		}
+		for _, item := range v {
+			if doc, ok := item.(bson.D); ok {
+				out = append(out, resolvePath(doc, parts)...)
+			}
+		}
+		return out
+	default:
+		return nil
+	}
+}
+
+// firstValue returns the value at path, or nil when the path does not exist. Paths that
+// cross arrays return the array of reached values, like an aggregation field reference.
+func firstValue(doc bson.D, path string) (any, bool) {
+	var current any = doc
+	for _, part := range splitPath(path) {
+		switch v := current.(type) {
+		case bson.D:
+			field, ok := lookupKey(v, part)
+			if !ok {
+				return nil, false
+			}
+			current = field
+		case bson.A:
+			out := bson.A{}
+			for _, item := range v {
+				if sub, ok := item.(bson.D); ok {
+					if value, ok := firstValue(sub, part); ok {
+						out = append(out, value)
+					}
+				}
+			}
+			current = out
+		default:
+			return nil, false
+		}
+	}
+
+	return current, true
+}
+
+// expandArrays returns the values themselves followed by the elements of array values,
+// which is the set of candidates MongoDB compares a query operand against.
+func expandArrays(values []any) []any {
+	out := make([]any, 0, len(values))
+	out = append(out, values...)
+	for _, value := range values {
+		if arr, ok := value.(bson.A); ok {
+			out = append(out, arr...)
+		}
+	}
+
+	return out
+}
+
+// typeOrder returns the position of the value's type in the BSON comparison order.
+func typeOrder(value any) int {
+	switch value.(type) {
+	case bson.MinKey:
+		return 0
+	case nil, bson.Null, bson.Undefined:
+		return 1
+	case int32, int64, float64, bson.Decimal128:
+		return 2
+	case string, bson.Symbol:
+		return 3
+	case bson.D:
+		return 4
+	case bson.A:
+		return 5
+	case bson.Binary:
+		return 6
+	case bson.ObjectID:
+		return 7
+	case bool:
+		return 8
+	case bson.DateTime:
+		return 9
+	case bson.Timestamp:
+		return 10
+	case bson.Regex:
+		return 11
+	case bson.MaxKey:
+		return 13
+	default:
+		return 12
+	}
+}
+
+func toFloat(value any) (float64, bool) {
+	switch v := value.(type) {
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	case bson.Decimal128:
+		f, err := strconv.ParseFloat(v.String(), 64)
+		if err != nil {
+			return math.NaN(), true
+		}
+		return f, true
+	default:
+		return 0, false
+	}
+}
+
+func toInt64(value any) (int64, bool) {
+	switch v := value.(type) {
+	case int32:
+		return int64(v), true
+	case int64:
+		return v, true
+	default:
+		return 0, false
+	}
+}
+
+// compareValues orders two normalized values following the BSON comparison order.
+func compareValues(a, b any) int {
+	if order := cmp.Compare(typeOrder(a), typeOrder(b)); order != 0 {
+		return order
+	}
+
+	switch av := a.(type) {
+	case int32, int64, float64, bson.Decimal128:
+		if ai, ok := toInt64(a); ok {
+			if bi, ok := toInt64(b); ok {
+				return cmp.Compare(ai, bi)
+			}
+		}
+		af, _ := toFloat(a)
+		bf, _ := toFloat(b)
+		return cmp.Compare(af, bf)
+	case string:
+		return cmp.Compare(av, stringOf(b))
+	case bson.Symbol:
+		return cmp.Compare(string(av), stringOf(b))
+	case bson.D:
+		bv := b.(bson.D)
+		for i := 0; i < len(av) && i < len(bv); i++ {
+			if order := cmp.Compare(av[i].Key, bv[i].Key); order != 0 {
+				return order
+			}
+			if order := compareValues(av[i].Value, bv[i].Value); order != 0 {
+				return order
+			}
+		}
+		return cmp.Compare(len(av), len(bv))
+	case bson.A:
+		bv := b.(bson.A)
+		for i := 0; i < len(av) && i < len(bv); i++ {
+			if order := compareValues(av[i], bv[i]); order != 0 {
+				return order
+			}
+		}
+		return cmp.Compare(len(av), len(bv))
+	case bson.Binary:
+		bv := b.(bson.Binary)
+		if order := cmp.Compare(len(av.Data), len(bv.Data)); order != 0 {
+			return order
+		}
+		if order := cmp.Compare(av.Subtype, bv.Subtype); order != 0 {
+			return order
+		}
+		return bytes.Compare(av.Data, bv.Data)
+	case bson.ObjectID:
+		bv := b.(bson.ObjectID)
+		return bytes.Compare(av[:], bv[:])
+	case bool:
+		bv := b.(bool)
+		switch {
+		case av == bv:
+			return 0
+		case !av:
+			return -1
+		default:
+			return 1
+		}
+	case bson.DateTime:
+		return cmp.Compare(int64(av), int64(b.(bson.DateTime)))
+	case bson.Timestamp:
+		bv := b.(bson.Timestamp)
+		if order := cmp.Compare(av.T, bv.T); order != 0 {
+			return order
+		}
+		return cmp.Compare(av.I, bv.I)
+	case bson.Regex:
+		bv := b.(bson.Regex)
+		if order := cmp.Compare(av.Pattern, bv.Pattern); order != 0 {
+			return order
+		}
+		return cmp.Compare(av.Options, bv.Options)
+	default:
+		return 0
+	}
+}
+
+func stringOf(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case bson.Symbol:
+		return string(v)
+	default:
+		return ""
+	}
+}
+
+func valuesEqual(a, b any) bool {
+	return typeOrder(a) == typeOrder(b) && compareValues(a, b) == 0
+}
+
+// compileRegex builds a Go regular expression from a MongoDB pattern and options.
+func compileRegex(pattern, opts string) (*regexp.Regexp, error) {
+	flags := ""
+	for _, opt := range opts {
+		switch opt {
+		case 'i', 'm', 's':
+			flags += string(opt)
+		case 'x':
+			return nil, unsupportedf("regex option %q", opt)
+		}
+	}
+
+	if flags != "" {
+		pattern = "(?" + flags + ")" + pattern
+	}
+
+	return regexp.Compile(pattern)
+}
+
+// isOperatorDocument reports whether value is a document whose keys are all operators.
+func isOperatorDocument(value any) (bson.D, bool) {
+	doc, ok := value.(bson.D)
+	if !ok || len(doc) == 0 {
+		return nil, false
+	}
+
+	for _, e := range doc {
+		if !strings.HasPrefix(e.Key, "$") {
+			return nil, false
+		}
+	}
+
+	return doc, true
+}
@@ -0,0 +1,547 @@
+package mongorm
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// OperationKind names the collection call an Operation describes. The values match the
+// Collection method names, and the index commands for calls made through Indexes().
+type OperationKind string
+
+// Operation kinds.
+const (
+	OperationFindOne          OperationKind = "FindOne"
+	OperationFind             OperationKind = "Find"
+	OperationFindOneAndUpdate OperationKind = "FindOneAndUpdate"
+	OperationInsertOne        OperationKind = "InsertOne"
+	OperationInsertMany       OperationKind = "InsertMany"
+	OperationUpdateOne        OperationKind = "UpdateOne"
+	OperationUpdateMany       OperationKind = "UpdateMany"
+	OperationDeleteOne        OperationKind = "DeleteOne"
+	OperationDeleteMany       OperationKind = "DeleteMany"
+	OperationBulkWrite        OperationKind = "BulkWrite"
+	OperationAggregate        OperationKind = "Aggregate"
+	OperationWatch            OperationKind = "Watch"
+	OperationDistinct         OperationKind = "Distinct"
+	OperationCountDocuments   OperationKind = "CountDocuments"
+	// OperationListIndexes is IndexView.List.
+	OperationListIndexes OperationKind = "ListIndexes"
+	// OperationListIndexSpecifications is IndexView.ListSpecifications.
+	OperationListIndexSpecifications OperationKind = "ListIndexSpecifications"
+	// OperationCreateIndexes is IndexView.CreateOne and IndexView.CreateMany.
+	OperationCreateIndexes OperationKind = "CreateIndexes"
+	// OperationDropIndexes is IndexView.DropOne and IndexView.DropAll.
+	OperationDropIndexes OperationKind = "DropIndexes"
+)
+
+// Operation describes one collection call passed through the middleware chain.
+// Middleware may change its fields before calling next, for example to add a filter;
+// the call is made with the values the chain ends with.
+type Operation struct {
+	Kind       OperationKind
+	Collection string
+	// Filter is the query of find, update, delete, count and distinct calls.
+	Filter any
+	// Update is the update document of UpdateOne, UpdateMany and FindOneAndUpdate.
+	Update any
+	// Pipeline is the pipeline of Aggregate and Watch.
+	Pipeline any
+	// Documents is the document of InsertOne or the documents of InsertMany.
+	Documents any
+	// Models are the write models of BulkWrite.
+	Models []mongo.WriteModel
+	// Field is the field name of Distinct.
+	Field string
+	// IndexModels are the indexes of CreateIndexes.
+	IndexModels []mongo.IndexModel
+	// IndexName is the index of DropIndexes, or "*" to drop every index except _id.
+	IndexName string
+	// Options holds the driver options of the call as a slice of the matching lister
+	// type, for example []options.Lister[options.FindOptions] for Find.
+	Options any
+}
+
+// OperationFunc runs an operation and returns the result of the Collection method:
+// *mongo.SingleResult, *mongo.Cursor, *mongo.InsertOneResult, *mongo.InsertManyResult,
+// *mongo.UpdateResult, *mongo.DeleteResult, *mongo.BulkWriteResult, *mongo.ChangeStream,
+// DistinctResult, int64, []mongo.IndexSpecification for ListIndexSpecifications, []string
+// (the index names) for CreateIndexes or nil for DropIndexes. For FindOne,
+// FindOneAndUpdate and Distinct the error is also returned, so middleware does not have
+// to inspect the result.
+type OperationFunc func(ctx context.Context, op *Operation) (any, error)
+
+// Middleware wraps every collection call of a MongORM instance. Register it with
+// MongORMOptions.Middleware; the first middleware is the outermost.
+//
+// Example usage:
+//
+//	tenantScope := func(next mongorm.OperationFunc) mongorm.OperationFunc {
+//	    return func(ctx context.Context, op *mongorm.Operation) (any, error) {
+//	        if op.Filter != nil {
+//	            op.Filter = bson.M{"$and": bson.A{op.Filter, bson.M{"tenant": tenantFrom(ctx)}}}
+//	        }
+//	        return next(ctx, op)
+//	    }
+//	}
+//
+//	orm := mongorm.FromOptions(&ToDo{}, &mongorm.MongORMOptions{
+//	    Middleware: []mongorm.Middleware{tenantScope},
+//	})
+type Middleware func(next OperationFunc) OperationFunc
+
+// middlewareCollection decorates a Collection and runs every call through the
+// configured middleware.
+//
+// > NOTE: This type is internal only.
+type middlewareCollection struct {
+	Collection
+	run OperationFunc
+}
+
+// newMiddlewareCollection wraps coll when options configure middleware, a retry policy
+// or a logger. Retries run inside the middleware, and the logger runs innermost so that
+// it records every attempt as sent.
+//
+// > NOTE: This function is internal only.
+func newMiddlewareCollection(coll Collection, opts *MongORMOptions) Collection {
+	if opts == nil {
+		return coll
+	}
+
+	middleware := append([]Middleware{}, opts.Middleware...)
+	if retrying := newRetryMiddleware(opts.RetryPolicy); retrying != nil {
+		middleware = append(middleware, retrying)
+	}
+	if logging := newLoggingMiddleware(opts); logging != nil {
+		middleware = append(middleware, logging)
+	}
+	if len(middleware) == 0 {
+		return coll
+	}
+
+	c := &middlewareCollection{Collection: coll}
+	c.run = c.call
+	for i := len(middleware) - 1; i >= 0; i-- {
+		if middleware[i] != nil {
+			c.run = middleware[i](c.run)
+		}
+	}
+
+	return c
+}
+
+func (c *middlewareCollection) FindOne(
+	ctx context.Context,
+	filter any,
+	opts ...options.Lister[options.FindOneOptions],
+) *mongo.SingleResult {
+	return singleResultOf(c.do(ctx, &Operation{Kind: OperationFindOne, Filter: filter, Options: opts}))
+}
+
+func (c *middlewareCollection) Find(
+	ctx context.Context,
+	filter any,
+	opts ...options.Lister[options.FindOptions],
+) (*mongo.Cursor, error) {
+	res, err := c.do(ctx, &Operation{Kind: OperationFind, Filter: filter, Options: opts})
+	cursor, _ := res.(*mongo.Cursor)
+
+	return cursor, err
+}
+
+func (c *middlewareCollection) FindOneAndUpdate(
+	ctx context.Context,
+	filter any,
+	update any,
+	opts ...options.Lister[options.FindOneAndUpdateOptions],
+) *mongo.SingleResult {
+	return singleResultOf(c.do(ctx, &Operation{Kind: OperationFindOneAndUpdate, Filter: filter, Update: update, Options: opts}))
+}
+
+func (c *middlewareCollection) InsertOne(
+	ctx context.Context,
+	document any,
+	opts ...options.Lister[options.InsertOneOptions],
+) (*mongo.InsertOneResult, error) {
+	res, err := c.do(ctx, &Operation{Kind: OperationInsertOne, Documents: document, Options: opts})
+	result, _ := res.(*mongo.InsertOneResult)
+
+	return result, err
+}
+
+func (c *middlewareCollection) InsertMany(
+	ctx context.Context,
+	documents any,
+	opts ...options.Lister[options.InsertManyOptions],
+) (*mongo.InsertManyResult, error) {
+	res, err := c.do(ctx, &Operation{Kind: OperationInsertMany, Documents: documents, Options: opts})
+	result, _ := res.(*mongo.InsertManyResult)
+
+	return result, err
+}
+
+func (c *middlewareCollection) UpdateOne(
+	ctx context.Context,
+	filter any,
+	update any,
+	opts ...options.Lister[options.UpdateOneOptions],
+) (*mongo.UpdateResult, error) {
+	res, err := c.do(ctx, &Operation{Kind: OperationUpdateOne, Filter: filter, Update: update, Options: opts})
+	result, _ := res.(*mongo.UpdateResult)
+
+	return result, err
+}
+
+func (c *middlewareCollection) UpdateMany(
+	ctx context.Context,
+	filter any,
+	update any,
+	opts ...options.Lister[options.UpdateManyOptions],
+) (*mongo.UpdateResult, error) {
+	res, err := c.do(ctx, &Operation{Kind: OperationUpdateMany, Filter: filter, Update: update, Options: opts})
+	result, _ := res.(*mongo.UpdateResult)
+
+	return result, err
+}
+
+func (c *middlewareCollection) DeleteOne(
+	ctx context.Context,
+	filter any,
+	opts ...options.Lister[options.DeleteOneOptions],
+) (*mongo.DeleteResult, error) {
+	res, err := c.do(ctx, &Operation{Kind: OperationDeleteOne, Filter: filter, Options: opts})
+	result, _ := res.(*mongo.DeleteResult)
+
+	return result, err
+}
+
+func (c *middlewareCollection) DeleteMany(
+	ctx context.Context,
+	filter any,
+	opts ...options.Lister[options.DeleteManyOptions],
+) (*mongo.DeleteResult, error) {
+	res, err := c.do(ctx, &Operation{Kind: OperationDeleteMany, Filter: filter, Options: opts})
+	result, _ := res.(*mongo.DeleteResult)
+
+	return result, err
+}
+
+func (c *middlewareCollection) BulkWrite(
+	ctx context.Context,
+	models []mongo.WriteModel,
+	opts ...options.Lister[options.BulkWriteOptions],
+) (*mongo.BulkWriteResult, error) {
+	res, err := c.do(ctx, &Operation{Kind: OperationBulkWrite, Models: models, Options: opts})
+	result, _ := res.(*mongo.BulkWriteResult)
+
+	return result, err
+}
+
+func (c *middlewareCollection) Aggregate(
+	ctx context.Context,
+	pipeline any,
+	opts ...options.Lister[options.AggregateOptions],
+) (*mongo.Cursor, error) {
+	res, err := c.do(ctx, &Operation{Kind: OperationAggregate, Pipeline: pipeline, Options: opts})
+	cursor, _ := res.(*mongo.Cursor)
+
+	return cursor, err
+}
+
+func (c *middlewareCollection) Watch(
+	ctx context.Context,
+	pipeline any,
+	opts ...options.Lister[options.ChangeStreamOptions],
+) (*mongo.ChangeStream, error) {
+	res, err := c.do(ctx, &Operation{Kind: OperationWatch, Pipeline: pipeline, Options: opts})
+	stream, _ := res.(*mongo.ChangeStream)
+
+	return stream, err
+}
+
+func (c *middlewareCollection) Distinct(
+	ctx context.Context,
+	fieldName string,
+	filter any,
+	opts ...options.Lister[options.DistinctOptions],
+) DistinctResult {
+	res, err := c.do(ctx, &Operation{Kind: OperationDistinct, Field: fieldName, Filter: filter, Options: opts})
+	if result, ok := res.(DistinctResult); ok && result != nil {
+		return result
+	}
+	if err == nil {
+		err = configErrorf("middleware returned no result for %s", OperationDistinct)
+	}
+
+	return distinctError{err: err}
+}
+
+func (c *middlewareCollection) CountDocuments(
+	ctx context.Context,
+	filter any,
+	opts ...options.Lister[options.CountOptions],
+) (int64, error) {
+	res, err := c.do(ctx, &Operation{Kind: OperationCountDocuments, Filter: filter, Options: opts})
+	count, _ := res.(int64)
+
+	return count, err
+}
+
+func (c *middlewareCollection) Indexes() IndexView {
+	return &middlewareIndexView{IndexView: c.Collection.Indexes(), coll: c}
+}
+
+// do runs op through the middleware chain.
+//
+// > NOTE: This method is internal only.
+func (c *middlewareCollection) do(ctx context.Context, op *Operation) (any, error) {
+	op.Collection = c.Name()
+
+	return c.run(ctx, op)
+}
+
+// call is the end of the middleware chain: it runs op on the decorated collection.
+//
+// > NOTE: This method is internal only.
+func (c *middlewareCollection) call(ctx context.Context, op *Operation) (any, error) {
+	switch op.Kind {
+	case OperationFindOne:
+		opts, err := operationOptions[options.FindOneOptions](op)
+		if err != nil {
+			return nil, err
+		}
+		res := c.Collection.FindOne(ctx, op.Filter, opts...)
+		return res, res.Err()
+	case OperationFind:
+		opts, err := operationOptions[options.FindOptions](op)
+		if err != nil {
+			return nil, err
+		}
+		return c.Collection.Find(ctx, op.Filter, opts...)
+	case OperationFindOneAndUpdate:
+		opts, err := operationOptions[options.FindOneAndUpdateOptions](op)
+		if err != nil {
+			return nil, err
+		}
+		res := c.Collection.FindOneAndUpdate(ctx, op.Filter, op.Update, opts...)
+		return res, res.Err()
+	case OperationInsertOne:
+		opts, err := operationOptions[options.InsertOneOptions](op)
+		if err != nil {
+			return nil, err
+		}
+		return c.Collection.InsertOne(ctx, op.Documents, opts...)
+	case OperationInsertMany:
+		opts, err := operationOptions[options.InsertManyOptions](op)
+		if err != nil {
+			return nil, err
+		}
+		return c.Collection.InsertMany(ctx, op.Documents, opts...)
+	case OperationUpdateOne:
+		opts, err := operationOptions[options.UpdateOneOptions](op)
+		if err != nil {
+			return nil, err
+		}
+		return c.Collection.UpdateOne(ctx, op.Filter, op.Update, opts...)
+	case OperationUpdateMany:
+		opts, err := operationOptions[options.UpdateManyOptions](op)
+		if err != nil {
+			return nil, err
+		}
+		return c.Collection.UpdateMany(ctx, op.Filter, op.Update, opts...)
+	case OperationDeleteOne:
+		opts, err := operationOptions[options.DeleteOneOptions](op)
+		if err != nil {
+			return nil, err
+		}
+		return c.Collection.DeleteOne(ctx, op.Filter, opts...)
+	case OperationDeleteMany:
+		opts, err := operationOptions[options.DeleteManyOptions](op)
+		if err != nil {
+			return nil, err
+		}
+		return c.Collection.DeleteMany(ctx, op.Filter, opts...)
+	case OperationBulkWrite:
+		opts, err := operationOptions[options.BulkWriteOptions](op)
+		if err != nil {
+			return nil, err
+		}
+		return c.Collection.BulkWrite(ctx, op.Models, opts...)
+	case OperationAggregate:
+		opts, err := operationOptions[options.AggregateOptions](op)
+		if err != nil {
+			return nil, err
+		}
+		return c.Collection.Aggregate(ctx, op.Pipeline, opts...)
+	case OperationWatch:
+		opts, err := operationOptions[options.ChangeStreamOptions](op)
+		if err != nil {
+			return nil, err
+		}
+		return c.Collection.Watch(ctx, op.Pipeline, opts...)
+	case OperationDistinct:
+		opts, err := operationOptions[options.DistinctOptions](op)
+		if err != nil {
+			return nil, err
+		}
+		res := c.Collection.Distinct(ctx, op.Field, op.Filter, opts...)
+		return res, res.Err()
+	case OperationCountDocuments:
+		opts, err := operationOptions[options.CountOptions](op)
+		if err != nil {
+			return nil, err
+		}
+		return c.Collection.CountDocuments(ctx, op.Filter, opts...)
+	case OperationListIndexes:
+		opts, err := operationOptions[options.ListIndexesOptions](op)
+		if err != nil {
+			return nil, err
+		}
+		return c.Collection.Indexes().List(ctx, opts...)
+	case OperationListIndexSpecifications:
+		opts, err := operationOptions[options.ListIndexesOptions](op)
+		if err != nil {
+			return nil, err
+		}
+		return c.Collection.Indexes().ListSpecifications(ctx, opts...)
+	case OperationCreateIndexes:
+		opts, err := operationOptions[options.CreateIndexesOptions](op)
+		if err != nil {
+			return nil, err
+		}
+		return c.Collection.Indexes().CreateMany(ctx, op.IndexModels, opts...)
+	case OperationDropIndexes:
+		opts, err := operationOptions[options.DropIndexesOptions](op)
+		if err != nil {
+			return nil, err
+		}
+		if op.IndexName == "*" {
+			return nil, c.Collection.Indexes().DropAll(ctx, opts...)
+		}
+		return nil, c.Collection.Indexes().DropOne(ctx, op.IndexName, opts...)
+	default:
+		return nil, configErrorf("unknown operation kind %q", op.Kind)
+	}
+}
+
+// middlewareIndexView runs the calls of an IndexView through the middleware chain of its
+// collection.
+//
+// > NOTE: This type is internal only.
+type middlewareIndexView struct {
+	IndexView
+	coll *middlewareCollection
+}
+
+func (v *middlewareIndexView) List(
+	ctx context.Context,
+	opts ...options.Lister[options.ListIndexesOptions],
+) (*mongo.Cursor, error) {
+	res, err := v.coll.do(ctx, &Operation{Kind: OperationListIndexes, Options: opts})
+	cursor, _ := res.(*mongo.Cursor)
+
+	return cursor, err
+}
+
+func (v *middlewareIndexView) ListSpecifications(
+	ctx context.Context,
+	opts ...options.Lister[options.ListIndexesOptions],
+) ([]mongo.IndexSpecification, error) {
+	res, err := v.coll.do(ctx, &Operation{Kind: OperationListIndexSpecifications, Options: opts})
+	specs, _ := res.([]mongo.IndexSpecification)
+
+	return specs, err
+}
+
+func (v *middlewareIndexView) CreateOne(
+	ctx context.Context,
+	model mongo.IndexModel,
+	opts ...options.Lister[options.CreateIndexesOptions],
+) (string, error) {
+	names, err := v.CreateMany(ctx, []mongo.IndexModel{model}, opts...)
+	if err != nil {
+		return "", err
+	}
+	if len(names) == 0 {
+		return "", configErrorf("middleware returned no index name for %s", OperationCreateIndexes)
+	}
+
+	return names[0], nil
+}
+
+func (v *middlewareIndexView) CreateMany(
+	ctx context.Context,
+	models []mongo.IndexModel,
+	opts ...options.Lister[options.CreateIndexesOptions],
+) ([]string, error) {
+	res, err := v.coll.do(ctx, &Operation{Kind: OperationCreateIndexes, IndexModels: models, Options: opts})
+	names, _ := res.([]string)
+
+	return names, err
+}
+
+func (v *middlewareIndexView) DropOne(
+	ctx context.Context,
+	name string,
+	opts ...options.Lister[options.DropIndexesOptions],
+) error {
+	_, err := v.coll.do(ctx, &Operation{Kind: OperationDropIndexes, IndexName: name, Options: opts})
+
+	return err
+}
+
+func (v *middlewareIndexView) DropAll(ctx context.Context, opts ...options.Lister[options.DropIndexesOptions]) error {
+	_, err := v.coll.do(ctx, &Operation{Kind: OperationDropIndexes, IndexName: "*", Options: opts})
+
+	return err
+}
+
+// operationOptions returns the options of op as listers of O.
+//
+// > NOTE: This function is internal only.
+func operationOptions[O any](op *Operation) ([]options.Lister[O], error) {
+	if op.Options == nil {
+		return nil, nil
+	}
+
+	opts, ok := op.Options.([]options.Lister[O])
+	if !ok {
+		return nil, configErrorf("%s options must be []options.Lister[%T], got %T", op.Kind, *new(O), op.Options)
+	}
+
+	return opts, nil
+}
+
+// singleResultOf turns the outcome of a FindOne or FindOneAndUpdate chain into a
+// SingleResult, building one for errors returned without a result.
+//
+// > NOTE: This function is internal only.
+func singleResultOf(res any, err error) *mongo.SingleResult {
+	if result, ok := res.(*mongo.SingleResult); ok && result != nil {
+		return result
+	}
+	if err == nil {
+		err = mongo.ErrNoDocuments
+	}
+
+	return mongo.NewSingleResultFromDocument(bson.D{}, err, nil)
+}
+
+// distinctError is the DistinctResult of a Distinct call that failed in middleware.
+//
+// > NOTE: This type is internal only.
+type distinctError struct {
+	err error
+}
+
+func (r distinctError) Decode(any) error {
+	return r.err
+}
+
+func (r distinctError) Err() error {
+	return r.err
+}
@@ -0,0 +1,147 @@
+package mongorm
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+)
+
+const migrateCommandUsage = `Usage: mongorm-migrate [flags] <command>
+
+Commands:
+  up           apply every pending migration
+  down [n]     revert the last n applied migrations (default 1)
+  status       list registered and applied migrations
+  unlock       remove a stale migration lock
+
+Flags:
+`
+
+// RunMigrateCommand runs the mongorm-migrate command line with args (without the program
+// name), writing its output to stdout. The connection comes from the -uri and -database
+// flags, or the MONGORM_URI and MONGORM_DATABASE environment variables.
+//
+// Migrations are registered in Go, so a service builds its own migrate binary that
+// imports its migrations and calls RunMigrateCommand:
+//
+//	package main
+//
+//	import (
+//	    "context"
+//	    "fmt"
+//	    "os"
+//
+//	    "github.com/azayn-labs/mongorm"
+//	    _ "example.com/app/migrations"
+//	)
+//
+//	func main() {
+//	    if err := mongorm.RunMigrateCommand(context.Background(), os.Args[1:], os.Stdout); err != nil {
+//	        fmt.Fprintln(os.Stderr, err)
+//	        os.Exit(1)
+//	    }
+//	}
+func RunMigrateCommand(ctx context.Context, args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("mongorm-migrate", flag.ContinueOnError)
+	flags.SetOutput(stdout)
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), migrateCommandUsage)
+		flags.PrintDefaults()
+	}
+
+	uri := flags.String("uri", os.Getenv("MONGORM_URI"), "MongoDB connection string")
+	database := flags.String("database", os.Getenv("MONGORM_DATABASE"), "database name")
+	collection := flags.String("collection", DefaultMigrationsCollection, "collection recording applied migrations")
+	lockTimeout := flags.Duration("lock-timeout", DefaultMigrationLockTimeout, "how long a migration lock is held")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return configErrorf("missing migrate command")
+	}
+	if *uri == "" || *database == "" {
+		return configErrorf("-uri and -database (or MONGORM_URI and MONGORM_DATABASE) are required")
+	}
+
+	client, err := NewClient(*uri)
+	if err != nil {
+		return normalizeError(err)
+	}
+	defer func() { _ = client.Disconnect(context.WithoutCancel(ctx)) }()
+
+	migrator := NewMigrator(NewBackend(client.Database(*database)), &MigratorOptions{
+		Collection:  collection,
+		LockTimeout: *lockTimeout,
+	})
+
+	return runMigrateCommand(ctx, migrator, flags.Args(), stdout)
+}
+
+// runMigrateCommand runs one migrate command with migrator.
+//
+// > NOTE: This function is internal only.
+func runMigrateCommand(ctx context.Context, migrator *Migrator, args []string, stdout io.Writer) error {
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		for _, id := range applied {
+			fmt.Fprintf(stdout, "applied %s\n", id)
+		}
+		if err == nil && len(applied) == 0 {
+			fmt.Fprintln(stdout, "no pending migrations")
+		}
+		return err
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			parsed, err := strconv.Atoi(args[1])
+			if err != nil {
+				return configErrorf("invalid number of migrations %q", args[1])
+			}
+			steps = parsed
+		}
+
+		reverted, err := migrator.Down(ctx, steps)
+		for _, id := range reverted {
+			fmt.Fprintf(stdout, "reverted %s\n", id)
+		}
+		if err == nil && len(reverted) == 0 {
+			fmt.Fprintln(stdout, "no applied migrations")
+		}
+		return err
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, status := range statuses {
+			state, appliedAt := "pending", "-"
+			if status.Applied {
+				state, appliedAt = "applied", status.AppliedAt.UTC().Format(time.RFC3339)
+			}
+			if status.Changed {
+				state = "changed"
+			}
+			if status.Missing {
+				state = "missing"
+			}
+			fmt.Fprintf(stdout, "%-8s %-20s %s\n", state, appliedAt, status.ID)
+		}
+		return nil
+	case "unlock":
+		if err := migrator.Unlock(ctx); err != nil {
+			return err
+		}
+		fmt.Fprintln(stdout, "migration lock removed")
+		return nil
+	default:
+		return configErrorf("unknown migrate command %q", args[0])
+	}
+}
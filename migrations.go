@@ -0,0 +1,461 @@
+package mongorm
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// DefaultMigrationsCollection is the collection recording applied migrations when
+// MigratorOptions.Collection is not set.
+const DefaultMigrationsCollection = "_migrations"
+
+// DefaultMigrationLockTimeout is how long a migration lock is held before another
+// process may take it over, when MigratorOptions.LockTimeout is not set.
+const DefaultMigrationLockTimeout = 15 * time.Minute
+
+// migrationLockID is the _id of the lock document in the migrations collection.
+const migrationLockID = "_lock"
+
+// MigrationFunc applies or reverts a migration. ctx is bound to the migration
+// transaction when the server supports transactions, so MongORM operations using it take
+// part in the transaction.
+type MigrationFunc func(ctx context.Context, db Backend) error
+
+// Migration is a versioned schema or data change. Migrations run in ascending ID order,
+// so IDs usually start with a sortable version such as "20240601_add_status".
+type Migration struct {
+	ID string
+	// Checksum identifies the content of the migration, such as a hash of the script it
+	// runs or a version bumped whenever Up or Down changes. It is recorded when the
+	// migration is applied, and Migrator.Up fails with ErrMigrationChanged when an applied
+	// migration is registered with a different one. Empty checksums are not compared.
+	Checksum string
+	Up       MigrationFunc
+	Down     MigrationFunc
+}
+
+// changedFrom reports whether the migration no longer matches the checksum recorded when
+// it was applied.
+//
+// > NOTE: This method is internal only.
+func (m Migration) changedFrom(record migrationRecord) bool {
+	return m.Checksum != "" && record.Checksum != "" && m.Checksum != record.Checksum
+}
+
+var (
+	migrationsMu sync.RWMutex
+	migrations   = map[string]Migration{}
+)
+
+// RegisterMigration registers a migration for every Migrator that does not set
+// MigratorOptions.Migrations. checksum identifies the content of up and down, such as a
+// hash of the script they run or a version bumped on every edit; an applied migration
+// that is registered again with another checksum makes Migrator.Up fail with
+// ErrMigrationChanged. Edits are only detected when a checksum is supplied, so pass ""
+// only for migrations that are never changed. down may be nil for migrations that cannot
+// be reverted. Registering an ID that already exists replaces the previous migration.
+//
+// Example usage:
+//
+//	func init() {
+//	    mongorm.RegisterMigration("20240601_add_status", "v1", addStatus, removeStatus)
+//	}
+//
+//	func addStatus(ctx context.Context, db mongorm.Backend) error {
+//	    _, err := db.Collection("todos").UpdateMany(ctx,
+//	        bson.M{"status": bson.M{"$exists": false}},
+//	        bson.M{"$set": bson.M{"status": "open"}},
+//	    )
+//	    return err
+//	}
+func RegisterMigration(id, checksum string, up, down MigrationFunc) {
+	id = strings.TrimSpace(id)
+	if id == "" || up == nil {
+		return
+	}
+
+	migrationsMu.Lock()
+	defer migrationsMu.Unlock()
+
+	migrations[id] = Migration{ID: id, Checksum: checksum, Up: up, Down: down}
+}
+
+// registeredMigrations returns the registered migrations.
+//
+// > NOTE: This function is internal only.
+func registeredMigrations() []Migration {
+	migrationsMu.RLock()
+	defer migrationsMu.RUnlock()
+
+	registered := make([]Migration, 0, len(migrations))
+	for _, migration := range migrations {
+		registered = append(registered, migration)
+	}
+
+	return registered
+}
+
+// MigratorOptions configures a Migrator.
+type MigratorOptions struct {
+	// Collection names the collection recording applied migrations and holding the
+	// lock document. Defaults to DefaultMigrationsCollection.
+	Collection *string
+	// LockTimeout is how long the lock is held before another process may take it
+	// over. Defaults to DefaultMigrationLockTimeout.
+	LockTimeout time.Duration
+	// MongoClient runs each migration in a transaction. Backends created with
+	// NewBackend use their own client when it is not set.
+	MongoClient *mongo.Client
+	// Migrations replaces the registered migrations.
+	Migrations []Migration
+}
+
+// MigrationStatus is the state of one migration, as reported by Migrator.Status.
+type MigrationStatus struct {
+	ID string
+	// Applied reports whether the migration is recorded in the migrations collection.
+	Applied   bool
+	AppliedAt *time.Time
+	// Changed reports an applied migration whose checksum no longer matches.
+	Changed bool
+	// Missing reports an applied migration that is no longer registered.
+	Missing bool
+}
+
+// migrationRecord is the document recorded for an applied migration.
+//
+// > NOTE: This type is internal only.
+type migrationRecord struct {
+	ID        string    `bson:"_id"`
+	Checksum  string    `bson:"checksum"`
+	AppliedAt time.Time `bson:"applied_at"`
+}
+
+// Migrator applies and reverts migrations against a database. Applied migrations are
+// recorded with their checksum in the migrations collection, and a lock document in the
+// same collection keeps two processes from migrating at once.
+//
+// Example usage:
+//
+//	client, _ := mongorm.NewClient("mongodb://localhost:27017")
+//	migrator := mongorm.NewMigrator(mongorm.NewBackend(client.Database("app")), nil)
+//	applied, err := migrator.Up(ctx)
+type Migrator struct {
+	backend     Backend
+	client      *mongo.Client
+	collection  string
+	lockTimeout time.Duration
+	migrations  []Migration
+	owner       string
+}
+
+// NewMigrator creates a migrator for the database behind backend. opts may be nil.
+func NewMigrator(backend Backend, opts *MigratorOptions) *Migrator {
+	if opts == nil {
+		opts = &MigratorOptions{}
+	}
+
+	m := &Migrator{
+		backend:     backend,
+		client:      opts.MongoClient,
+		collection:  DefaultMigrationsCollection,
+		lockTimeout: opts.LockTimeout,
+		migrations:  opts.Migrations,
+		owner:       migrationLockOwner(),
+	}
+
+	if opts.Collection != nil {
+		m.collection = *opts.Collection
+	}
+	if m.lockTimeout <= 0 {
+		m.lockTimeout = DefaultMigrationLockTimeout
+	}
+	if m.client == nil {
+		if mb, ok := backend.(*mongoBackend); ok {
+			m.client = mb.db.Client()
+		}
+	}
+
+	return m
+}
+
+// Up applies every pending migration in ID order and returns the IDs it applied. Each
+// migration and its record are written in one transaction when the server supports it.
+// It fails with ErrMigrationChanged when an applied migration no longer matches its
+// recorded checksum, and with ErrMigrationLocked while another process holds the lock.
+func (m *Migrator) Up(ctx context.Context) ([]string, error) {
+	applied := []string{}
+
+	err := m.withLock(ctx, func() error {
+		known, records, err := m.load(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range known {
+			record, ok := records[migration.ID]
+			if !ok {
+				continue
+			}
+			if migration.changedFrom(record) {
+				return fmt.Errorf("%w: %s", ErrMigrationChanged, migration.ID)
+			}
+		}
+
+		for _, migration := range known {
+			if _, ok := records[migration.ID]; ok {
+				continue
+			}
+
+			err := m.run(ctx, func(ctx context.Context) error {
+				if err := migration.Up(ctx, m.backend); err != nil {
+					return err
+				}
+
+				_, err := m.backend.Collection(m.collection).InsertOne(ctx, migrationRecord{
+					ID:        migration.ID,
+					Checksum:  migration.Checksum,
+					AppliedAt: time.Now().UTC(),
+				})
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("migration %s: %w", migration.ID, err)
+			}
+
+			applied = append(applied, migration.ID)
+		}
+
+		return nil
+	})
+
+	return applied, err
+}
+
+// Down reverts the last steps applied migrations, newest first, and returns the IDs it
+// reverted. It fails before reverting anything when one of them has no Down function or
+// is no longer registered.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]string, error) {
+	if steps <= 0 {
+		return nil, configErrorf("migration steps must be positive")
+	}
+
+	reverted := []string{}
+
+	err := m.withLock(ctx, func() error {
+		known, records, err := m.load(ctx)
+		if err != nil {
+			return err
+		}
+
+		byID := map[string]Migration{}
+		for _, migration := range known {
+			byID[migration.ID] = migration
+		}
+
+		ids := make([]string, 0, len(records))
+		for id := range records {
+			ids = append(ids, id)
+		}
+		slices.Sort(ids)
+		slices.Reverse(ids)
+		ids = ids[:min(steps, len(ids))]
+
+		for _, id := range ids {
+			migration, ok := byID[id]
+			if !ok {
+				return configErrorf("applied migration %s is not registered", id)
+			}
+			if migration.Down == nil {
+				return configErrorf("migration %s cannot be reverted", id)
+			}
+		}
+
+		for _, id := range ids {
+			migration := byID[id]
+
+			err := m.run(ctx, func(ctx context.Context) error {
+				if err := migration.Down(ctx, m.backend); err != nil {
+					return err
+				}
+
+				_, err := m.backend.Collection(m.collection).DeleteOne(ctx, bson.D{{Key: "_id", Value: id}})
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("migration %s: %w", id, err)
+			}
+
+			reverted = append(reverted, id)
+		}
+
+		return nil
+	})
+
+	return reverted, err
+}
+
+// Status reports every registered or applied migration in ID order.
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	known, records, err := m.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := []MigrationStatus{}
+	for _, migration := range known {
+		status := MigrationStatus{ID: migration.ID}
+		if record, ok := records[migration.ID]; ok {
+			status.Applied = true
+			status.AppliedAt = Timestamp(record.AppliedAt)
+			status.Changed = migration.changedFrom(record)
+			delete(records, migration.ID)
+		}
+		statuses = append(statuses, status)
+	}
+
+	for _, record := range records {
+		statuses = append(statuses, MigrationStatus{
+			ID:        record.ID,
+			Applied:   true,
+			AppliedAt: Timestamp(record.AppliedAt),
+			Missing:   true,
+		})
+	}
+
+	slices.SortFunc(statuses, func(a, b MigrationStatus) int { return strings.Compare(a.ID, b.ID) })
+
+	return statuses, nil
+}
+
+// Unlock removes the lock document regardless of its owner. Use it to recover from a
+// process that died while migrating, before the lock times out.
+func (m *Migrator) Unlock(ctx context.Context) error {
+	_, err := m.backend.Collection(m.collection).DeleteOne(ctx, bson.D{{Key: "_id", Value: migrationLockID}})
+
+	return normalizeError(err)
+}
+
+// load returns the known migrations sorted by ID and the applied records by ID.
+//
+// > NOTE: This method is internal only.
+func (m *Migrator) load(ctx context.Context) ([]Migration, map[string]migrationRecord, error) {
+	if m.backend == nil {
+		return nil, nil, configErrorf("migrator database is not configured")
+	}
+
+	known := m.migrations
+	if known == nil {
+		known = registeredMigrations()
+	}
+	known = slices.Clone(known)
+	slices.SortFunc(known, func(a, b Migration) int { return strings.Compare(a.ID, b.ID) })
+
+	for i, migration := range known {
+		if migration.ID == "" || strings.HasPrefix(migration.ID, "_") {
+			return nil, nil, configErrorf("migration id %q must not be empty or start with an underscore", migration.ID)
+		}
+		if migration.Up == nil {
+			return nil, nil, configErrorf("migration %s has no Up function", migration.ID)
+		}
+		if i > 0 && known[i-1].ID == migration.ID {
+			return nil, nil, configErrorf("migration %s is defined twice", migration.ID)
+		}
+	}
+
+	cursor, err := m.backend.Collection(m.collection).Find(ctx, bson.D{{Key: "_id", Value: bson.D{{Key: "$ne", Value: migrationLockID}}}})
+	if err != nil {
+		return nil, nil, normalizeError(err)
+	}
+
+	var applied []migrationRecord
+	if err := cursor.All(ctx, &applied); err != nil {
+		return nil, nil, normalizeError(err)
+	}
+
+	records := make(map[string]migrationRecord, len(applied))
+	for _, record := range applied {
+		records[record.ID] = record
+	}
+
+	return known, records, nil
+}
+
+// run runs fn in a transaction when a client is available, and directly when there is
+// none or the server does not support transactions.
+//
+// > NOTE: This method is internal only.
+func (m *Migrator) run(ctx context.Context, fn func(ctx context.Context) error) error {
+	if m.client != nil {
+		err := runInTransaction(ctx, m.client, fn)
+		if !IsTransactionUnsupported(err) {
+			return err
+		}
+	}
+
+	return normalizeError(fn(ctx))
+}
+
+// withLock runs fn while holding the lock document. An existing lock is taken over once
+// it has expired.
+//
+// > NOTE: This method is internal only.
+func (m *Migrator) withLock(ctx context.Context, fn func() error) error {
+	if m.backend == nil {
+		return configErrorf("migrator database is not configured")
+	}
+
+	coll := m.backend.Collection(m.collection)
+	now := time.Now().UTC()
+	lock := bson.D{
+		{Key: "owner", Value: m.owner},
+		{Key: "locked_at", Value: now},
+		{Key: "expires_at", Value: now.Add(m.lockTimeout)},
+	}
+
+	_, err := coll.InsertOne(ctx, append(bson.D{{Key: "_id", Value: migrationLockID}}, lock...))
+	if mongo.IsDuplicateKeyError(err) {
+		err = coll.FindOneAndUpdate(
+			ctx,
+			bson.D{{Key: "_id", Value: migrationLockID}, {Key: "expires_at", Value: bson.D{{Key: "$lt", Value: now}}}},
+			bson.D{{Key: "$set", Value: lock}},
+		).Err()
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return ErrMigrationLocked
+		}
+	}
+	if err != nil {
+		return normalizeError(err)
+	}
+
+	defer func() {
+		_, _ = coll.DeleteOne(
+			context.WithoutCancel(ctx),
+			bson.D{{Key: "_id", Value: migrationLockID}, {Key: "owner", Value: m.owner}},
+		)
+	}()
+
+	return fn()
+}
+
+// migrationLockOwner identifies the process holding a migration lock.
+//
+// > NOTE: This function is internal only.
+func migrationLockOwner() string {
+	host, _ := os.Hostname()
+	suffix := make([]byte, 4)
+	_, _ = rand.Read(suffix)
+
+	return fmt.Sprintf("%s:%d:%s", host, os.Getpid(), hex.EncodeToString(suffix))
+}
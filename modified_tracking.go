@@ -65,7 +65,11 @@ func (m *MongORM[T]) ModifiedValue(field any) (oldValue any, newValue any, ok bo
 		return nil, nil, false
 	}
 
-	oldValue, _ = m.schemaValueByPath(path)
+	if m.snapshot != nil {
+		oldValue, _ = valueByBSONPath(reflect.ValueOf(m.snapshot), path)
+	} else {
+		oldValue, _ = m.schemaValueByPath(path)
+	}
 	newValue, hasNew := m.modifiedNewValue(path, oldValue)
 
 	if !hasNew {
@@ -160,8 +164,12 @@ func (m *MongORM[T]) schemaValueByPath(path string) (any, bool) {
 		return nil, false
 	}
 
+	return valueByBSONPath(reflect.ValueOf(m.schema), path)
+}
+
+func valueByBSONPath(root reflect.Value, path string) (any, bool) {
 	parts := strings.Split(path, ".")
-	current := reflect.ValueOf(m.schema)
+	current := root
 
 	for _, part := range parts {
 		part = strings.TrimSpace(part)
@@ -12,7 +12,8 @@ import (
 type MongORMInfo struct {
 	dbName     *string
 	db         *mongo.Database
-	collection *mongo.Collection
+	backend    Backend
+	collection Collection
 	fields     map[string]Field
 }
 
@@ -27,6 +28,7 @@ type MongORM[T any] struct {
 	info       *MongORMInfo
 	operations *MongORMOperations
 	modified   map[string]struct{}
+	snapshot   *T
 	initErr    error
 }
 
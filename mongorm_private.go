@@ -61,5 +61,6 @@ func (m *MongORM[T]) reset() {
 		update: bson.M{},
 	}
 	m.modified = map[string]struct{}{}
+	m.snapshot = nil
 	m.schema = nil
 }
@@ -11,13 +11,15 @@ import (
 //
 // > NOTE: This struct is not intended for public use.
 type MongORMOperations struct {
-	query      bson.M `json:"-"`
-	update     bson.M `json:"-"`
-	sort       any    `json:"-"`
-	projection any    `json:"-"`
-	limit      *int64 `json:"-"`
-	skip       *int64 `json:"-"`
-	pipeline   bson.A `json:"-"`
+	query      bson.M          `json:"-"`
+	update     bson.M          `json:"-"`
+	sort       any             `json:"-"`
+	projection any             `json:"-"`
+	limit      *int64          `json:"-"`
+	skip       *int64          `json:"-"`
+	pipeline   bson.A          `json:"-"`
+	softDelete softDeleteScope `json:"-"`
+	skipHooks  bool            `json:"-"`
 }
 
 // Resets the MongORMOperations instance to its initial state. This is useful for reusing
@@ -34,6 +36,8 @@ func (o *MongORMOperations) reset() {
 	o.limit = nil
 	o.skip = nil
 	o.pipeline = nil
+	o.softDelete = softDeleteScopeDefault
+	o.skipHooks = false
 }
 
 // fixUpdate ensures that the update document is properly structured for MongoDB operations.
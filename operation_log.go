@@ -0,0 +1,152 @@
+package mongorm
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"reflect"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// newLoggingMiddleware returns the middleware logging every operation through
+// MongORMOptions.Logger, or nil when no logger is configured.
+//
+// > NOTE: This function is internal only.
+func newLoggingMiddleware(opts *MongORMOptions) Middleware {
+	if opts == nil || opts.Logger == nil {
+		return nil
+	}
+
+	logger := opts.Logger
+	configured := opts.LogLevel
+	if configured == nil {
+		configured = slog.LevelDebug
+	}
+	threshold := opts.SlowOperationThreshold
+
+	return func(next OperationFunc) OperationFunc {
+		return func(ctx context.Context, op *Operation) (any, error) {
+			start := time.Now()
+			res, err := next(ctx, op)
+			duration := time.Since(start)
+
+			// Failed calls are logged at error level (except ErrNoDocuments, which is an
+			// expected outcome), calls reaching the slow threshold at warn level and
+			// every other call at the configured level.
+			level := configured.Level()
+			slow := threshold > 0 && duration >= threshold
+			switch {
+			case err != nil && !errors.Is(err, mongo.ErrNoDocuments):
+				level = slog.LevelError
+			case slow:
+				level = max(level, slog.LevelWarn)
+			}
+
+			if !logger.Enabled(ctx, level) {
+				return res, err
+			}
+
+			attrs := []slog.Attr{
+				slog.String("operation", string(op.Kind)),
+				slog.String("collection", op.Collection),
+				slog.Duration("duration", duration),
+			}
+			if slow {
+				attrs = append(attrs, slog.Bool("slow", true))
+			}
+			if op.Filter != nil {
+				attrs = append(attrs, slog.Any("filter", bsonLogValue{op.Filter}))
+			}
+			if op.Update != nil {
+				attrs = append(attrs, slog.Any("update", bsonLogValue{op.Update}))
+			}
+			if op.Pipeline != nil {
+				attrs = append(attrs, slog.Any("pipeline", bsonLogValue{op.Pipeline}))
+			}
+			attrs = append(attrs, resultLogAttrs(res)...)
+			if err != nil {
+				attrs = append(attrs, slog.String("error", err.Error()))
+			}
+
+			logger.LogAttrs(ctx, level, "mongorm operation", attrs...)
+
+			return res, err
+		}
+	}
+}
+
+// resultLogAttrs returns the document counts of a write or count result.
+//
+// > NOTE: This function is internal only.
+func resultLogAttrs(result any) []slog.Attr {
+	switch res := result.(type) {
+	case *mongo.InsertOneResult:
+		if res != nil {
+			return []slog.Attr{slog.Int64("inserted", 1)}
+		}
+	case *mongo.InsertManyResult:
+		if res != nil {
+			return []slog.Attr{slog.Int("inserted", len(res.InsertedIDs))}
+		}
+	case *mongo.UpdateResult:
+		if res != nil {
+			return []slog.Attr{
+				slog.Int64("matched", res.MatchedCount),
+				slog.Int64("modified", res.ModifiedCount),
+				slog.Int64("upserted", res.UpsertedCount),
+			}
+		}
+	case *mongo.DeleteResult:
+		if res != nil {
+			return []slog.Attr{slog.Int64("deleted", res.DeletedCount)}
+		}
+	case *mongo.BulkWriteResult:
+		if res != nil {
+			return []slog.Attr{
+				slog.Int64("inserted", res.InsertedCount),
+				slog.Int64("matched", res.MatchedCount),
+				slog.Int64("modified", res.ModifiedCount),
+				slog.Int64("upserted", res.UpsertedCount),
+				slog.Int64("deleted", res.DeletedCount),
+			}
+		}
+	case int64:
+		return []slog.Attr{slog.Int64("count", res)}
+	}
+
+	return nil
+}
+
+// bsonLogValue renders a filter, update or pipeline as relaxed extended JSON, only when
+// the record is actually written.
+//
+// > NOTE: This type is internal only.
+type bsonLogValue struct {
+	value any
+}
+
+func (v bsonLogValue) LogValue() slog.Value {
+	if raw, err := bson.MarshalExtJSON(v.value, false, false); err == nil {
+		return slog.StringValue(string(raw))
+	}
+
+	// Pipelines are arrays, which extended JSON cannot encode at the top level.
+	ref := reflect.ValueOf(v.value)
+	if ref.Kind() == reflect.Slice {
+		stages := make([]string, 0, ref.Len())
+		for i := range ref.Len() {
+			raw, err := bson.MarshalExtJSON(ref.Index(i).Interface(), false, false)
+			if err != nil {
+				return slog.AnyValue(v.value)
+			}
+			stages = append(stages, string(raw))
+		}
+		return slog.StringValue("[" + strings.Join(stages, ",") + "]")
+	}
+
+	return slog.AnyValue(v.value)
+}
@@ -1,6 +1,9 @@
 package mongorm
 
 import (
+	"log/slog"
+	"time"
+
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 )
@@ -27,6 +30,42 @@ type MongORMOptions struct {
 	CollectionName *string       `json:"-"`
 	DatabaseName   *string       `json:"-"`
 	MongoClient    *mongo.Client `json:"-"`
+	// IDGenerator fills the primary field before insert when it is empty. It overrides
+	// any `id:<name>` tag on the primary field.
+	IDGenerator IDGenerator `json:"-"`
+	// CountersCollection names the collection backing `sequence:<name>` fields.
+	// Defaults to DefaultCountersCollection.
+	CountersCollection *string `json:"-"`
+	// Backend replaces the MongoDB client and database. When set, the connection
+	// string, MongoClient and DatabaseName settings are ignored and every collection
+	// is resolved through the backend.
+	Backend Backend `json:"-"`
+	// Collection replaces the collection the instance operates on, for example with a
+	// decorator around NewCollection or a fake. CollectionName and the collection tag
+	// are ignored. Without a Backend or MongoClient no database is resolved, so
+	// sequences and transactions are unavailable.
+	Collection Collection `json:"-"`
+	// Middleware wraps every collection call, outermost first. See Middleware.
+	Middleware []Middleware `json:"-"`
+	// RetryPolicy retries idempotent reads and WithTransaction callbacks that fail
+	// with a transient error. Retries are off when nil. See RetryPolicy.
+	RetryPolicy *RetryPolicy `json:"-"`
+	// Tracer starts a span around every operation, such as First or SaveMulti.
+	// Defaults to NoopTracer.
+	Tracer Tracer `json:"-"`
+	// Metrics records the count, latency and document count of every operation.
+	// Defaults to NoopMetrics.
+	Metrics Metrics `json:"-"`
+	// Logger receives a record of every collection call with the operation, collection,
+	// duration, filter, update or pipeline, result counts and error. Logging is off
+	// when nil.
+	Logger *slog.Logger `json:"-"`
+	// LogLevel is the level of successful operations. Defaults to slog.LevelDebug.
+	// Failed operations are logged at slog.LevelError.
+	LogLevel slog.Leveler `json:"-"`
+	// SlowOperationThreshold logs operations taking at least this long at
+	// slog.LevelWarn or above. Zero disables slow operation detection.
+	SlowOperationThreshold time.Duration `json:"-"`
 }
 
 // FromOptions creates a new MongORM instance with the provided schema and options. This function
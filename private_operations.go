@@ -10,24 +10,25 @@ import (
 )
 
 // applySchema applies the given document to the schema of the MongORM instance, calling
-// the BeforeFinalize and AfterFinalize hooks if they are implemented by the schema. This
+// the BeforeFinalize and AfterFinalize hooks if they are implemented by the schema. The
+// document is also kept as a snapshot so that a later Save only writes changed fields. This
 // function is used internally to update the schema with the results of database operations,
 // ensuring that any necessary hooks are executed in the correct order. It returns an error
 // if any of the hooks fail or if there is an issue applying the document to the schema.
 //
 // > NOTE: This method is internal only.
-func (m *MongORM[T]) applySchema(doc *T) error {
-	schema := any(m.schema)
-	if hook, ok := schema.(BeforeFinalizeHook[T]); ok {
-		if err := hook.BeforeFinalize(m); err != nil {
+func (m *MongORM[T]) applySchema(ctx context.Context, doc *T) error {
+	if hook, ok := m.beforeFinalizeHook(); ok {
+		if err := hook(ctx, m); err != nil {
 			return err
 		}
 	}
 
 	*m.schema = *doc
+	m.takeSnapshot(doc)
 
-	if hook, ok := schema.(AfterFinalizeHook[T]); ok {
-		if err := hook.AfterFinalize(m); err != nil {
+	if hook, ok := m.afterFinalizeHook(); ok {
+		if err := hook(ctx, m); err != nil {
 			return err
 		}
 	}
@@ -38,6 +39,28 @@ func (m *MongORM[T]) applySchema(doc *T) error {
 	return nil
 }
 
+// loadDocument returns a clone of the model holding doc, a document read by a cursor. Like
+// a single-document load, it runs the BeforeFinalize, AfterFinalize and AfterFind hooks of
+// the schema.
+//
+// > NOTE: This method is internal only.
+func (m *MongORM[T]) loadDocument(ctx context.Context, doc *T) (*MongORM[T], error) {
+	clone := m.clone()
+	clone.schema = new(T)
+
+	if err := clone.applySchema(ctx, doc); err != nil {
+		return nil, err
+	}
+
+	if hook, ok := clone.afterFindHook(); ok {
+		if err := hook(ctx, clone); err != nil {
+			return nil, err
+		}
+	}
+
+	return clone, nil
+}
+
 // updateSchema retrieves the document with the specified ID from the database and updates
 // the schema of the MongORM instance with the retrieved document. It calls the BeforeFind
 // and AfterFind hooks if they are implemented by the schema. This function is used internally
@@ -47,17 +70,21 @@ func (m *MongORM[T]) applySchema(doc *T) error {
 // applying the document to the schema.
 //
 // > NOTE: This method is internal only.
-func (m *MongORM[T]) updateSchema(ctx context.Context, id *bson.ObjectID) error {
+func (m *MongORM[T]) updateSchema(ctx context.Context, id any) error {
 	_, primaryField, err := m.getFieldByTag(ModelTagPrimary)
 	if err != nil {
 		return err
 	}
 
+	id, err = m.primaryKeyOf(id)
+	if err != nil {
+		return err
+	}
+
 	filter := bson.M{primaryField: id}
 
-	schema := any(m.schema)
-	if hook, ok := schema.(BeforeFindHook[T]); ok {
-		if err := hook.BeforeFind(m, &filter); err != nil {
+	if hook, ok := m.beforeFindHook(); ok {
+		if err := hook(ctx, m, &filter); err != nil {
 			return err
 		}
 	}
@@ -67,13 +94,13 @@ func (m *MongORM[T]) updateSchema(ctx context.Context, id *bson.ObjectID) error
 		return normalizeError(err)
 	}
 
-	if hook, ok := schema.(AfterFindHook[T]); ok {
-		if err := hook.AfterFind(m); err != nil {
+	if hook, ok := m.afterFindHook(); ok {
+		if err := hook(ctx, m); err != nil {
 			return err
 		}
 	}
 
-	return m.applySchema(&doc)
+	return m.applySchema(ctx, &doc)
 }
 
 // insertOne inserts a new document into the collection based on the current schema of the
@@ -94,10 +121,17 @@ func (m *MongORM[T]) insertOne(ctx context.Context) error {
 		return err
 	}
 
-	schema := any(m.schema)
+	if err := m.generatePrimaryID(); err != nil {
+		return err
+	}
+
+	if err := m.assignSequences(ctx, m.schema); err != nil {
+		return err
+	}
+
 	m.rebuildModifiedFromSchema()
-	if hook, ok := schema.(BeforeCreateHook[T]); ok {
-		if err := hook.BeforeCreate(m); err != nil {
+	if hook, ok := m.beforeCreateHook(); ok {
+		if err := hook(ctx, m); err != nil {
 			return err
 		}
 		m.rebuildModifiedFromSchema()
@@ -110,20 +144,19 @@ func (m *MongORM[T]) insertOne(ctx context.Context) error {
 
 	ins, err := m.info.collection.InsertOne(ctx, insertDoc)
 	if err != nil {
-		return normalizeError(err)
+		return m.normalizeWriteError(err)
 	}
 
-	id, ok := ins.InsertedID.(bson.ObjectID)
-	if !ok {
+	if ins.InsertedID == nil {
 		return configErrorf("invalid document from database: missing identifier")
 	}
 
-	if err := m.updateSchema(ctx, &id); err != nil {
+	if err := m.updateSchema(ctx, ins.InsertedID); err != nil {
 		return err
 	}
 
-	if hook, ok := schema.(AfterCreateHook[T]); ok {
-		if err := hook.AfterCreate(m); err != nil {
+	if hook, ok := m.afterCreateHook(); ok {
+		if err := hook(ctx, m); err != nil {
 			return err
 		}
 	}
@@ -155,10 +188,9 @@ func (m *MongORM[T]) updateOne(
 ) error {
 	var doc T
 
-	schema := any(m.schema)
 	m.rebuildModifiedFromUpdate(*update)
-	if hook, ok := schema.(BeforeUpdateHook[T]); ok {
-		if err := hook.BeforeUpdate(m, filter, update); err != nil {
+	if hook, ok := m.beforeUpdateHook(); ok {
+		if err := hook(ctx, m, filter, update); err != nil {
 			return err
 		}
 		m.operations.fixUpdate()
@@ -187,15 +219,15 @@ func (m *MongORM[T]) updateOne(
 		update,
 		opts...,
 	).Decode(&doc); err != nil {
-		return mapUpdateOneError(err, optimisticLockEnabled)
+		return m.withDuplicateKeyFields(mapUpdateOneError(err, optimisticLockEnabled))
 	}
 
-	if err := m.applySchema(&doc); err != nil {
+	if err := m.applySchema(ctx, &doc); err != nil {
 		return err
 	}
 
-	if hook, ok := schema.(AfterUpdateHook[T]); ok {
-		if err := hook.AfterUpdate(m); err != nil {
+	if hook, ok := m.afterUpdateHook(); ok {
+		if err := hook(ctx, m); err != nil {
 			return err
 		}
 	}
@@ -224,9 +256,8 @@ func (m *MongORM[T]) findOne(
 	filter *bson.M,
 	opts ...options.Lister[options.FindOneOptions],
 ) error {
-	schema := any(m.schema)
-	if hook, ok := schema.(BeforeFindHook[T]); ok {
-		if err := hook.BeforeFind(m, filter); err != nil {
+	if hook, ok := m.beforeFindHook(); ok {
+		if err := hook(ctx, m, filter); err != nil {
 			return err
 		}
 	}
@@ -240,12 +271,12 @@ func (m *MongORM[T]) findOne(
 		return normalizeError(err)
 	}
 
-	if err := m.applySchema(&doc); err != nil {
+	if err := m.applySchema(ctx, &doc); err != nil {
 		return err
 	}
 
-	if hook, ok := schema.(AfterFindHook[T]); ok {
-		if err := hook.AfterFind(m); err != nil {
+	if hook, ok := m.afterFindHook(); ok {
+		if err := hook(ctx, m); err != nil {
 			return err
 		}
 	}
@@ -271,9 +302,8 @@ func (m *MongORM[T]) deleteOne(
 	ctx context.Context,
 	filter *bson.M,
 ) error {
-	schema := any(m.schema)
-	if hook, ok := schema.(BeforeDeleteHook[T]); ok {
-		if err := hook.BeforeDelete(m, filter); err != nil {
+	if hook, ok := m.beforeDeleteHook(); ok {
+		if err := hook(ctx, m, filter); err != nil {
 			return err
 		}
 	}
@@ -287,10 +317,13 @@ func (m *MongORM[T]) deleteOne(
 		return ErrNotFound
 	}
 
+	// Resolve the hooks before the reset clears the schema they run on.
+	hook, ok := m.afterDeleteHook()
+
 	m.reset() // clear all
 
-	if hook, ok := schema.(AfterDeleteHook[T]); ok {
-		if err := hook.AfterDelete(m); err != nil {
+	if ok {
+		if err := hook(ctx, m); err != nil {
 			return err
 		}
 	}
@@ -300,13 +333,14 @@ func (m *MongORM[T]) deleteOne(
 
 // withPrimaryFilters constructs a filter that includes the primary key field based on
 // the current state of the MongORM instance. It retrieves the primary field from the
-// schema and checks if it exists and is not nil. If the primary field exists, it is
-// added to the filter, and the method returns the updated filter along with the primary
-// key value. If the primary field does not exist or is nil, the method returns the
-// original filter without modification.
+// schema and checks if it exists and holds a non-zero value. If it does, the value is
+// added to the filter, converted by primaryKeyFor, so any BSON-encodable key type
+// (ObjectID, string, UUID, integer or custom types) is supported, and the method returns
+// the updated filter along with the primary key value. If the primary field does not exist or is empty,
+// the method returns the original filter without modification.
 //
 // > NOTE: This method is internal only.
-func (m *MongORM[T]) withPrimaryFilters() (bson.M, *bson.ObjectID, error) {
+func (m *MongORM[T]) withPrimaryFilters() (bson.M, any, error) {
 	m.operations.fixQuery()
 
 	filters := bson.M{}
@@ -327,43 +361,114 @@ func (m *MongORM[T]) withPrimaryFilters() (bson.M, *bson.ObjectID, error) {
 		return nil, nil, configErrorf("primary field is invalid")
 	}
 
-	if field.Kind() == reflect.Pointer {
+	id, ok := primaryKeyValue(field)
+	if !ok {
+		return filters, nil, nil
+	}
+
+	id, err = primaryKeyFor(field.Type(), id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	filters[primaryFieldName] = id
+
+	return filters, id, nil
+}
+
+// primaryKeyValue returns the value held by the primary key field, dereferencing pointers
+// and interfaces. It reports false when the field is nil or holds the zero value of its
+// type, meaning the document has no identifier yet.
+//
+// > NOTE: This function is internal only.
+func primaryKeyValue(field reflect.Value) (any, bool) {
+	for field.IsValid() && (field.Kind() == reflect.Pointer || field.Kind() == reflect.Interface) {
 		if field.IsNil() {
-			return filters, nil, nil
+			return nil, false
 		}
 		field = field.Elem()
 	}
 
-	var id bson.ObjectID
-	switch field.Kind() {
-	case reflect.Array:
-		decoded, ok := field.Interface().(bson.ObjectID)
-		if !ok {
-			return nil, nil, configErrorf("primary field must be a bson.ObjectID or hex string")
-		}
-		id = decoded
-	case reflect.String:
-		hex := field.String()
-		if hex == "" {
-			return filters, nil, nil
-		}
+	if !field.IsValid() || field.IsZero() {
+		return nil, false
+	}
 
-		decoded, err := bson.ObjectIDFromHex(hex)
-		if err != nil {
-			return nil, nil, err
-		}
-		id = decoded
-	default:
-		return nil, nil, configErrorf("primary field must be a bson.ObjectID or hex string")
+	return field.Interface(), true
+}
+
+// objectIDType is the reflected type of bson.ObjectID.
+var objectIDType = reflect.TypeFor[bson.ObjectID]()
+
+// primaryKeyFor converts id to the key type of a primary field declared as fieldType.
+// For bson.ObjectID fields, hex strings are converted to a bson.ObjectID; keys of every
+// other type are used as-is, so string keys are never coerced into ObjectIDs.
+//
+// > NOTE: This function is internal only.
+func primaryKeyFor(fieldType reflect.Type, id any) (any, error) {
+	for fieldType.Kind() == reflect.Pointer {
+		fieldType = fieldType.Elem()
 	}
 
-	filters[primaryFieldName] = id
+	if fieldType != objectIDType {
+		return id, nil
+	}
+
+	hex, ok := id.(string)
+	if !ok {
+		return id, nil
+	}
+
+	oid, err := bson.ObjectIDFromHex(hex)
+	if err != nil {
+		return nil, configErrorf("primary key %q is not a valid ObjectID hex: %v", hex, err)
+	}
 
-	return filters, &id, nil
+	return oid, nil
+}
+
+// primaryAcceptsObjectID reports whether the primary field of the model can hold a
+// bson.ObjectID, either as its type or through an interface such as any.
+//
+// > NOTE: This method is internal only.
+func (m *MongORM[T]) primaryAcceptsObjectID() bool {
+	primaryFieldGoName, _, err := m.getFieldByTag(ModelTagPrimary)
+	if err != nil {
+		return false
+	}
+
+	field, ok := reflect.TypeFor[T]().FieldByName(primaryFieldGoName)
+	if !ok {
+		return false
+	}
+
+	fieldType := field.Type
+	for fieldType.Kind() == reflect.Pointer {
+		fieldType = fieldType.Elem()
+	}
+
+	return objectIDType.AssignableTo(fieldType)
+}
+
+// primaryKeyOf converts id to the key type of the primary field of the model, like
+// primaryKeyFor. It is used for identifiers that come back from the collection, such as
+// the inserted ID reported by a custom Collection.
+//
+// > NOTE: This method is internal only.
+func (m *MongORM[T]) primaryKeyOf(id any) (any, error) {
+	primaryFieldGoName, _, err := m.getFieldByTag(ModelTagPrimary)
+	if err != nil {
+		return nil, err
+	}
+
+	field, ok := reflect.TypeFor[T]().FieldByName(primaryFieldGoName)
+	if !ok {
+		return nil, configErrorf("primary field is invalid")
+	}
 
+	return primaryKeyFor(field.Type, id)
 }
 
-func (m *MongORM[T]) withPrimaryAndSchemaFilters() (bson.M, *bson.ObjectID, error) {
+func (m *MongORM[T]) withPrimaryAndSchemaFilters() (bson.M, any, error) {
 	filters, id, err := m.withPrimaryFilters()
 	if err != nil {
 		return nil, nil, err
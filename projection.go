@@ -12,7 +12,10 @@ func FindOneAs[T any, R any](
 	m *MongORM[T],
 	ctx context.Context,
 	opts ...options.Lister[options.FindOneOptions],
-) (*R, error) {
+) (_ *R, err error) {
+	ctx, telemetry := m.startOperation(ctx, "FindOneAs")
+	defer func() { telemetry.end(int64(1), err) }()
+
 	if err := m.ensureReady(); err != nil {
 		return nil, err
 	}
@@ -22,6 +25,8 @@ func FindOneAs[T any, R any](
 		return nil, err
 	}
 
+	filter = m.withSoftDeleteScope(filter, softDeleteScopeWithoutTrashed)
+
 	allOpts := []options.Lister[options.FindOneOptions]{
 		m.operations.findOneOptions(),
 	}
@@ -42,7 +47,10 @@ func FindAllAs[T any, R any](
 	m *MongORM[T],
 	ctx context.Context,
 	opts ...options.Lister[options.FindOptions],
-) ([]R, error) {
+) (results []R, err error) {
+	ctx, telemetry := m.startOperation(ctx, "FindAllAs")
+	defer func() { telemetry.end(results, err) }()
+
 	if err := m.ensureReady(); err != nil {
 		return nil, err
 	}
@@ -52,6 +60,8 @@ func FindAllAs[T any, R any](
 		return nil, err
 	}
 
+	filter = m.withSoftDeleteScope(filter, softDeleteScopeWithoutTrashed)
+
 	allOpts := []options.Lister[options.FindOptions]{
 		m.operations.findOptions(),
 	}
@@ -63,8 +73,8 @@ func FindAllAs[T any, R any](
 		return nil, normalizeError(err)
 	}
 
-	results := []R{}
-	if err := cursor.All(ctx, &results); err != nil {
+	results, err = decodeAllAs[T, R](ctx, m, cursor)
+	if err != nil {
 		if closeErr := cursor.Close(ctx); closeErr != nil {
 			return nil, errors.Join(normalizeError(err), normalizeError(closeErr))
 		}
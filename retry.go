@@ -0,0 +1,203 @@
+package mongorm
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"reflect"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// Default retry settings used for the zero fields of a RetryPolicy.
+const (
+	DefaultRetryMaxAttempts = 3
+	DefaultRetryBackoff     = 100 * time.Millisecond
+	DefaultRetryMaxBackoff  = 5 * time.Second
+)
+
+// writeConflictCode is the server error code of a WriteConflict.
+const writeConflictCode = 112
+
+// RetryPolicy retries idempotent reads and transactions that fail with a transient error
+// (see IsRetryableError). Set it with MongORMOptions.RetryPolicy.
+//
+// Reads (First, FindAll, Count, Distinct, Aggregate without $out or $merge, ...) retry
+// their collection call; WithTransaction re-runs its whole callback in a new
+// transaction. Reads inside a transaction are not retried on their own. Writes outside a
+// transaction rely on the driver's retryable writes.
+//
+// Example usage:
+//
+//	orm := mongorm.FromOptions(&ToDo{}, &mongorm.MongORMOptions{
+//	    RetryPolicy: &mongorm.RetryPolicy{MaxAttempts: 5, Backoff: 50 * time.Millisecond, Jitter: 0.5},
+//	})
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first. Defaults to
+	// DefaultRetryMaxAttempts; 1 disables retries.
+	MaxAttempts int
+	// Backoff is the wait before the first retry. It doubles after every retry.
+	// Defaults to DefaultRetryBackoff.
+	Backoff time.Duration
+	// MaxBackoff caps the wait between attempts. Defaults to DefaultRetryMaxBackoff.
+	MaxBackoff time.Duration
+	// Jitter randomly shortens every wait by up to this fraction, between 0 and 1.
+	// Zero waits exactly the backoff.
+	Jitter float64
+}
+
+// IsRetryableError reports whether err is transient: an error labeled
+// TransientTransactionError or UnknownTransactionCommitResult, a network error or a
+// WriteConflict.
+func IsRetryableError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	if mongo.IsNetworkError(err) {
+		return true
+	}
+
+	var serverErr mongo.ServerError
+	if !errors.As(err, &serverErr) {
+		return false
+	}
+
+	return serverErr.HasErrorLabel("TransientTransactionError") ||
+		serverErr.HasErrorLabel("UnknownTransactionCommitResult") ||
+		serverErr.HasErrorCode(writeConflictCode)
+}
+
+// attempts returns the number of attempts the policy allows.
+//
+// > NOTE: This method is internal only.
+func (p *RetryPolicy) attempts() int {
+	if p == nil {
+		return 1
+	}
+
+	if p.MaxAttempts <= 0 {
+		return DefaultRetryMaxAttempts
+	}
+
+	return p.MaxAttempts
+}
+
+// delay returns the wait before the given retry, starting at 1.
+//
+// > NOTE: This method is internal only.
+func (p *RetryPolicy) delay(retry int) time.Duration {
+	backoff := p.Backoff
+	if backoff <= 0 {
+		backoff = DefaultRetryBackoff
+	}
+
+	maxBackoff := p.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = DefaultRetryMaxBackoff
+	}
+
+	delay := backoff
+	for i := 1; i < retry && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	delay = min(delay, maxBackoff)
+
+	if jitter := min(max(p.Jitter, 0), 1); jitter > 0 {
+		delay -= time.Duration(rand.Float64() * jitter * float64(delay))
+	}
+
+	return delay
+}
+
+// retry runs fn until it succeeds, fails with an error that is not retryable, or the
+// policy runs out of attempts. It stops waiting when ctx is done and returns the last
+// error of fn.
+//
+// > NOTE: This function is internal only.
+func retry[R any](ctx context.Context, policy *RetryPolicy, fn func() (R, error)) (R, error) {
+	attempts := policy.attempts()
+
+	for attempt := 1; ; attempt++ {
+		result, err := fn()
+		if err == nil || attempt >= attempts || !IsRetryableError(err) {
+			return result, err
+		}
+
+		timer := time.NewTimer(policy.delay(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return result, err
+		case <-timer.C:
+		}
+	}
+}
+
+// newRetryMiddleware returns the middleware retrying idempotent reads with policy, or nil
+// when policy is nil.
+//
+// > NOTE: This function is internal only.
+func newRetryMiddleware(policy *RetryPolicy) Middleware {
+	if policy == nil {
+		return nil
+	}
+
+	return func(next OperationFunc) OperationFunc {
+		return func(ctx context.Context, op *Operation) (any, error) {
+			if !idempotentRead(op) || mongo.SessionFromContext(ctx) != nil {
+				return next(ctx, op)
+			}
+
+			return retry(ctx, policy, func() (any, error) {
+				attempt := *op
+				return next(ctx, &attempt)
+			})
+		}
+	}
+}
+
+// idempotentRead reports whether op only reads, so that it can be sent again.
+//
+// > NOTE: This function is internal only.
+func idempotentRead(op *Operation) bool {
+	switch op.Kind {
+	case OperationFindOne, OperationFind, OperationDistinct, OperationCountDocuments,
+		OperationListIndexes, OperationListIndexSpecifications:
+		return true
+	case OperationAggregate:
+		return !pipelineWrites(op.Pipeline)
+	default:
+		return false
+	}
+}
+
+// pipelineWrites reports whether pipeline has an $out or $merge stage. Pipelines that
+// cannot be inspected are assumed to write.
+//
+// > NOTE: This function is internal only.
+func pipelineWrites(pipeline any) bool {
+	stages := reflect.ValueOf(pipeline)
+	if stages.Kind() != reflect.Slice {
+		return true
+	}
+
+	for i := 0; i < stages.Len(); i++ {
+		raw, err := bson.Marshal(stages.Index(i).Interface())
+		if err != nil {
+			return true
+		}
+
+		document := bson.Raw(raw)
+		if _, err := document.LookupErr("$out"); err == nil {
+			return true
+		}
+		if _, err := document.LookupErr("$merge"); err == nil {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,204 @@
+package mongorm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// DefaultCountersCollection is the collection used to store sequence counters when
+// MongORMOptions.CountersCollection is not set.
+const DefaultCountersCollection = "counters"
+
+// NextSequence atomically increments the named counter and returns its new value. The
+// counter document is created on first use, so the first value returned is 1. Counters
+// live in the same database as the model collection.
+//
+// When ctx is a transaction context (see WithTransaction), the increment takes part in
+// the transaction and is rolled back with it.
+//
+// Example usage:
+//
+//	next, err := orm.NextSequence(ctx, "invoice")
+//	if err != nil {
+//	    // Handle error
+//	}
+func (m *MongORM[T]) NextSequence(ctx context.Context, name string) (int64, error) {
+	if err := m.ensureReady(); err != nil {
+		return 0, err
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return 0, configErrorf("sequence name cannot be empty")
+	}
+
+	counters, err := m.countersCollection()
+	if err != nil {
+		return 0, err
+	}
+
+	var counter struct {
+		Seq int64 `bson:"seq"`
+	}
+
+	if err := counters.FindOneAndUpdate(
+		ctx,
+		bson.M{"_id": name},
+		bson.M{"$inc": bson.M{"seq": int64(1)}},
+		options.FindOneAndUpdate().
+			SetUpsert(true).
+			SetReturnDocument(options.After),
+	).Decode(&counter); err != nil {
+		return 0, normalizeError(err)
+	}
+
+	return counter.Seq, nil
+}
+
+func (m *MongORM[T]) countersCollection() (Collection, error) {
+	if m.info == nil || m.info.backend == nil {
+		return nil, configErrorf("mongodb database is not initialized")
+	}
+
+	name := DefaultCountersCollection
+	if m.options != nil && m.options.CountersCollection != nil {
+		name = *m.options.CountersCollection
+	}
+
+	return newMiddlewareCollection(m.info.backend.Collection(name), m.options), nil
+}
+
+// assignSequences fills every empty `sequence:<name>` field of doc with the next value
+// of its counter. Fields that already hold a non-zero value are left untouched.
+//
+// > NOTE: This method is internal only.
+func (m *MongORM[T]) assignSequences(ctx context.Context, doc *T) error {
+	if doc == nil {
+		return nil
+	}
+
+	value := reflect.ValueOf(doc).Elem()
+	if value.Kind() != reflect.Struct {
+		return nil
+	}
+
+	valueType := value.Type()
+	for i := 0; i < valueType.NumField(); i++ {
+		fieldType := valueType.Field(i)
+
+		name, ok := getModelTagValue(fieldType.Tag, ModelTagSequence)
+		if !ok {
+			continue
+		}
+
+		if fieldType.PkgPath != "" {
+			return configErrorf("sequence field %s must be exported", fieldType.Name)
+		}
+
+		field := value.Field(i)
+		current, hasValue, err := readSequenceValue(field)
+		if err != nil {
+			return configErrorf("sequence field %s: %v", fieldType.Name, err)
+		}
+		if hasValue && current != 0 {
+			continue
+		}
+
+		next, err := m.NextSequence(ctx, name)
+		if err != nil {
+			return err
+		}
+
+		if err := setSequenceValue(field, next); err != nil {
+			return configErrorf("sequence field %s: %v", fieldType.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// assignSequencesToModels fills sequence fields on the documents of insert models that
+// were built from *T values (for example through BulkWriteBuilder.InsertOne).
+//
+// > NOTE: This method is internal only.
+func (m *MongORM[T]) assignSequencesToModels(ctx context.Context, models []mongo.WriteModel) error {
+	if !hasSequenceFields[T]() {
+		return nil
+	}
+
+	for _, model := range models {
+		insert, ok := model.(*mongo.InsertOneModel)
+		if !ok {
+			continue
+		}
+
+		doc, ok := insert.Document.(*T)
+		if !ok {
+			continue
+		}
+
+		if err := m.assignSequences(ctx, doc); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func hasSequenceFields[T any]() bool {
+	modelType := reflect.TypeFor[T]()
+	if modelType.Kind() != reflect.Struct {
+		return false
+	}
+
+	for i := 0; i < modelType.NumField(); i++ {
+		if _, ok := getModelTagValue(modelType.Field(i).Tag, ModelTagSequence); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+func readSequenceValue(field reflect.Value) (int64, bool, error) {
+	if field.Kind() == reflect.Pointer {
+		if field.IsNil() {
+			return 0, false, nil
+		}
+		field = field.Elem()
+	}
+
+	switch field.Kind() {
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		return field.Int(), true, nil
+	default:
+		return 0, false, errors.New("must be an int, int32 or int64 type")
+	}
+}
+
+func setSequenceValue(field reflect.Value, value int64) error {
+	if !field.CanSet() {
+		return errors.New("must be settable")
+	}
+
+	if field.Kind() == reflect.Pointer {
+		if field.IsNil() {
+			field.Set(reflect.New(field.Type().Elem()))
+		}
+		field = field.Elem()
+	}
+
+	if field.OverflowInt(value) {
+		return fmt.Errorf("value %d overflows field type", value)
+	}
+
+	field.SetInt(value)
+	return nil
+}
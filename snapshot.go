@@ -0,0 +1,256 @@
+package mongorm
+
+import (
+	"reflect"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// takeSnapshot stores a deep copy of doc as loaded from the database. Save compares the
+// schema against this copy to send only the fields that changed since the load.
+//
+// > NOTE: This method is internal only.
+func (m *MongORM[T]) takeSnapshot(doc *T) {
+	if m == nil {
+		return
+	}
+
+	m.snapshot = nil
+	if doc == nil {
+		return
+	}
+
+	raw, err := bson.Marshal(doc)
+	if err != nil {
+		return
+	}
+
+	var snapshot T
+	if err := bson.Unmarshal(raw, &snapshot); err != nil {
+		return
+	}
+
+	m.snapshot = &snapshot
+}
+
+// hasSnapshotFor reports whether the stored snapshot belongs to the document identified
+// by id, so the schema can be diffed against it.
+//
+// > NOTE: This method is internal only.
+func (m *MongORM[T]) hasSnapshotFor(id any) bool {
+	if m == nil || m.snapshot == nil || id == nil {
+		return false
+	}
+
+	primaryFieldGoName, _, err := m.getFieldByTag(ModelTagPrimary)
+	if err != nil {
+		return false
+	}
+
+	field := reflect.ValueOf(m.snapshot).Elem().FieldByName(primaryFieldGoName)
+	snapshotID, ok := primaryKeyValue(field)
+	if !ok {
+		return false
+	}
+
+	return reflect.DeepEqual(snapshotID, id)
+}
+
+// applySnapshotChanges diffs the schema against the snapshot and merges the changed paths
+// into the accumulated update as $set and $unset entries. Paths that overlap a path already
+// present in the update are skipped so explicit Set()/Unset()/IncData() calls win.
+//
+// > NOTE: This method is internal only.
+func (m *MongORM[T]) applySnapshotChanges() error {
+	set, unset, err := m.snapshotChanges()
+	if err != nil {
+		return err
+	}
+
+	m.operations.fixUpdate()
+
+	explicit := map[string]struct{}{}
+	for key, value := range m.operations.update {
+		for _, path := range extractFieldPaths(value) {
+			explicit[path] = struct{}{}
+		}
+		if !strings.HasPrefix(key, "$") {
+			explicit[key] = struct{}{}
+		}
+	}
+
+	merge := func(operator string, changes bson.M) {
+		if len(changes) == 0 {
+			return
+		}
+
+		doc, ok := m.operations.update[operator].(bson.M)
+		if !ok || doc == nil {
+			doc = bson.M{}
+		}
+
+		for path, value := range changes {
+			if pathOverlapsAny(path, explicit) {
+				continue
+			}
+			doc[path] = value
+		}
+
+		m.operations.update[operator] = doc
+	}
+
+	merge("$set", set)
+	merge("$unset", unset)
+	m.operations.fixUpdate()
+
+	return nil
+}
+
+// snapshotChanges returns the $set and $unset documents that turn the snapshot into the
+// current schema. Nested documents are compared field by field; arrays and scalar values
+// are replaced as a whole. Primary, readonly and version fields are never included.
+//
+// > NOTE: This method is internal only.
+func (m *MongORM[T]) snapshotChanges() (bson.M, bson.M, error) {
+	set := bson.M{}
+	unset := bson.M{}
+
+	if m == nil || m.schema == nil || m.snapshot == nil {
+		return set, unset, nil
+	}
+
+	before, err := snapshotDocument(m.snapshot)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	after, err := snapshotDocument(m.schema)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, name := range protectedSnapshotFields[T]() {
+		delete(before, name)
+		delete(after, name)
+	}
+
+	diffSnapshotDocuments("", before, after, set, unset)
+
+	// Prefer the typed schema value over the decoded BSON value so that hooks reading
+	// ModifiedValue see the same Go types they assigned.
+	for path := range set {
+		if value, ok := m.schemaValueByPath(path); ok {
+			set[path] = value
+		}
+	}
+
+	return set, unset, nil
+}
+
+func snapshotDocument[T any](doc *T) (bson.M, error) {
+	raw, err := bson.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	out := bson.M{}
+	if err := bson.Unmarshal(raw, &out); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func protectedSnapshotFields[T any]() []string {
+	modelType := reflect.TypeFor[T]()
+	if modelType.Kind() != reflect.Struct {
+		return nil
+	}
+
+	names := []string{}
+	for i := 0; i < modelType.NumField(); i++ {
+		fieldType := modelType.Field(i)
+		if fieldType.PkgPath != "" {
+			continue
+		}
+
+		if !doesModelIncludeAnyModelFlags(
+			fieldType.Tag,
+			string(ModelTagPrimary),
+			string(ModelTagReadonly),
+			string(ModelTagVersion),
+		) {
+			continue
+		}
+
+		names = append(names, parseBSONName(fieldType.Tag.Get("bson"), fieldType.Name))
+	}
+
+	return names
+}
+
+func diffSnapshotDocuments(prefix string, before, after bson.M, set, unset bson.M) {
+	for key, newValue := range after {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+
+		oldValue, existed := before[key]
+		if !existed {
+			set[path] = newValue
+			continue
+		}
+
+		oldDoc, oldIsDoc := snapshotSubdocument(oldValue)
+		newDoc, newIsDoc := snapshotSubdocument(newValue)
+		if oldIsDoc && newIsDoc {
+			diffSnapshotDocuments(path, oldDoc, newDoc, set, unset)
+			continue
+		}
+
+		if !reflect.DeepEqual(oldValue, newValue) {
+			set[path] = newValue
+		}
+	}
+
+	for key := range before {
+		if _, exists := after[key]; exists {
+			continue
+		}
+
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+		unset[path] = ""
+	}
+}
+
+func snapshotSubdocument(value any) (bson.M, bool) {
+	switch typed := value.(type) {
+	case bson.M:
+		return typed, true
+	case bson.D:
+		doc := make(bson.M, len(typed))
+		for _, entry := range typed {
+			doc[entry.Key] = entry.Value
+		}
+		return doc, true
+	default:
+		return nil, false
+	}
+}
+
+func pathOverlapsAny(path string, paths map[string]struct{}) bool {
+	for other := range paths {
+		if path == other ||
+			strings.HasPrefix(path, other+".") ||
+			strings.HasPrefix(other, path+".") {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,283 @@
+package mongorm
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// softDeleteScope selects which documents reads see on models with a soft delete field.
+//
+// > NOTE: This type is internal only.
+type softDeleteScope int
+
+const (
+	softDeleteScopeDefault softDeleteScope = iota
+	softDeleteScopeWithoutTrashed
+	softDeleteScopeWithTrashed
+	softDeleteScopeOnlyTrashed
+)
+
+// WithTrashed includes soft-deleted documents in the next operation. It has no effect on
+// models without a `soft_delete` field.
+//
+// Example usage:
+//
+//	cursor, err := orm.WithTrashed().FindAll(ctx)
+func (m *MongORM[T]) WithTrashed() *MongORM[T] {
+	m.operations.softDelete = softDeleteScopeWithTrashed
+	return m
+}
+
+// OnlyTrashed restricts the next operation to soft-deleted documents. It has no effect on
+// models without a `soft_delete` field.
+//
+// Example usage:
+//
+//	count, err := orm.OnlyTrashed().Count(ctx)
+func (m *MongORM[T]) OnlyTrashed() *MongORM[T] {
+	m.operations.softDelete = softDeleteScopeOnlyTrashed
+	return m
+}
+
+// Restore clears the soft delete timestamp of a single soft-deleted document that matches
+// the current filters and decodes the restored document back into the schema. It runs the
+// BeforeUpdate and AfterUpdate hooks and never performs an upsert. It returns ErrNotFound
+// if no soft-deleted document matches.
+//
+// Example usage:
+//
+//	todo := &ToDo{ID: id}
+//	if err := mongorm.New(todo).Restore(ctx); err != nil {
+//	    // Handle error
+//	}
+func (m *MongORM[T]) Restore(ctx context.Context) (err error) {
+	ctx, telemetry := m.startOperation(ctx, "Restore")
+	defer func() { telemetry.end(int64(1), err) }()
+
+	if err := m.ensureReady(); err != nil {
+		return err
+	}
+
+	fieldName, ok := m.softDeleteFieldName()
+	if !ok {
+		return configErrorf("restore requires a field tagged with %s", ModelTagSoftDelete)
+	}
+
+	m.clearModified()
+
+	filter, _, err := m.withPrimaryAndSchemaFilters()
+	if err != nil {
+		return err
+	}
+
+	if len(filter) == 0 {
+		return configErrorf("restore requires a filter or primary key")
+	}
+
+	filter = m.withSoftDeleteScope(filter, softDeleteScopeOnlyTrashed)
+
+	m.operations.fixUpdate()
+	unset, ok := m.operations.update["$unset"].(bson.M)
+	if !ok || unset == nil {
+		unset = bson.M{}
+	}
+	unset[fieldName] = ""
+	m.operations.update["$unset"] = unset
+
+	return m.updateOne(
+		ctx,
+		&filter,
+		&m.operations.update,
+		false,
+		options.FindOneAndUpdate().SetUpsert(false),
+	)
+}
+
+// ForceDelete permanently removes a single document that matches the current filters,
+// bypassing soft delete. Soft-deleted documents are matched as well unless OnlyTrashed()
+// is used. On models without a `soft_delete` field it behaves like Delete.
+//
+// Example usage:
+//
+//	todo := &ToDo{ID: id}
+//	if err := mongorm.New(todo).ForceDelete(ctx); err != nil {
+//	    // Handle error
+//	}
+func (m *MongORM[T]) ForceDelete(ctx context.Context) (err error) {
+	ctx, telemetry := m.startOperation(ctx, "ForceDelete")
+	defer func() { telemetry.end(int64(1), err) }()
+
+	if err := m.ensureReady(); err != nil {
+		return err
+	}
+
+	filter, _, err := m.withPrimaryAndSchemaFilters()
+	if err != nil {
+		return err
+	}
+
+	filter = m.withSoftDeleteScope(filter, softDeleteScopeWithTrashed)
+
+	return m.deleteOne(ctx, &filter)
+}
+
+// softDeleteFieldName returns the BSON name of the field tagged with `soft_delete`, if any.
+//
+// > NOTE: This method is internal only.
+func (m *MongORM[T]) softDeleteFieldName() (string, bool) {
+	if m == nil || m.schema == nil {
+		return "", false
+	}
+
+	_, fieldName, err := m.getFieldByTag(ModelTagSoftDelete)
+	if err != nil || fieldName == "" {
+		return "", false
+	}
+
+	return fieldName, true
+}
+
+// withSoftDeleteScope adds the soft delete condition to filter. The scope selected with
+// WithTrashed() or OnlyTrashed() wins and is cleared, so it applies to one operation only;
+// otherwise fallback is used. A condition on a field that is already filtered is appended
+// to $and so the caller's condition is kept.
+//
+// > NOTE: This method is internal only.
+func (m *MongORM[T]) withSoftDeleteScope(filter bson.M, fallback softDeleteScope) bson.M {
+	scope := m.operations.softDelete
+	m.operations.softDelete = softDeleteScopeDefault
+
+	fieldName, ok := m.softDeleteFieldName()
+	if !ok {
+		return filter
+	}
+
+	if scope == softDeleteScopeDefault {
+		scope = fallback
+	}
+
+	var condition any
+	switch scope {
+	case softDeleteScopeWithTrashed:
+		return filter
+	case softDeleteScopeOnlyTrashed:
+		condition = bson.M{"$ne": nil}
+	default:
+		// Matches both a missing field and an explicit null.
+		condition = nil
+	}
+
+	if filter == nil {
+		filter = bson.M{}
+	}
+
+	if _, exists := filter[fieldName]; !exists {
+		filter[fieldName] = condition
+		return filter
+	}
+
+	clauses := expressionClauses(filter["$and"])
+	filter["$and"] = append(clauses, bson.M{fieldName: condition})
+
+	return filter
+}
+
+// softDeleteOne marks a single document as deleted by setting its soft delete field to
+// the current time. It runs the delete hooks like deleteOne.
+//
+// > NOTE: This method is internal only.
+func (m *MongORM[T]) softDeleteOne(
+	ctx context.Context,
+	fieldName string,
+	filter *bson.M,
+) error {
+	if hook, ok := m.beforeDeleteHook(); ok {
+		if err := hook(ctx, m, filter); err != nil {
+			return err
+		}
+	}
+
+	update := m.softDeleteUpdate(fieldName)
+	res, err := m.info.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return m.normalizeWriteError(err)
+	}
+
+	if res.MatchedCount == 0 {
+		return ErrNotFound
+	}
+
+	// Resolve the hooks before the reset clears the schema they run on.
+	hook, ok := m.afterDeleteHook()
+
+	m.reset() // clear all
+
+	if ok {
+		if err := hook(ctx, m); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// softDeleteMany marks every document matching filter as deleted. The returned result
+// reports the number of documents that were soft-deleted.
+//
+// > NOTE: This method is internal only.
+func (m *MongORM[T]) softDeleteMany(
+	ctx context.Context,
+	fieldName string,
+	filter bson.M,
+) (*mongo.DeleteResult, error) {
+	update := m.softDeleteUpdate(fieldName)
+	res, err := m.info.collection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		return nil, m.normalizeWriteErrors(err)
+	}
+
+	return &mongo.DeleteResult{
+		DeletedCount: res.ModifiedCount,
+		Acknowledged: res.Acknowledged,
+	}, nil
+}
+
+func (m *MongORM[T]) softDeleteUpdate(fieldName string) bson.M {
+	update := bson.M{
+		"$set": bson.M{fieldName: time.Now()},
+	}
+	m.applyTimestampsToUpdateDoc(&update)
+
+	return update
+}
+
+// validateSoftDeleteField checks that the `soft_delete` field, if present, is a
+// *time.Time so that live documents can be matched by a null or missing value.
+//
+// > NOTE: This method is internal only.
+func (m *MongORM[T]) validateSoftDeleteField() error {
+	ref := reflect.ValueOf(m.schema).Elem()
+	t := ref.Type()
+
+	for i := 0; i < ref.NumField(); i++ {
+		fieldType := t.Field(i)
+		if !doesModelIncludeAnyModelFlags(fieldType.Tag, string(ModelTagSoftDelete)) {
+			continue
+		}
+
+		if fieldType.PkgPath != "" {
+			return configErrorf("soft delete field %s must be exported", fieldType.Name)
+		}
+
+		if fieldType.Type != reflect.TypeFor[*time.Time]() {
+			return configErrorf("soft delete field %s must be a *time.Time", fieldType.Name)
+		}
+	}
+
+	return nil
+}
@@ -41,6 +41,7 @@ const (
 //	   ID *string `bson:"_id" mongorm:"primary"`
 //	   CreatedAt *time.Time `bson:"created_at" mongorm:"timestamp:created_at"`
 //	   UpdatedAt *time.Time `bson:"updated_at" mongorm:"timestamp:updated_at"`
+//	   DeletedAt *time.Time `bson:"deleted_at,omitempty" mongorm:"soft_delete"`
 //	}
 const (
 	ModelTagPrimary            ModelTags = "primary"
@@ -48,6 +49,46 @@ const (
 	ModelTagReadonly           ModelTags = "readonly"
 	ModelTagTimestampCreatedAt ModelTags = "timestamp:created_at"
 	ModelTagTimestampUpdatedAt ModelTags = "timestamp:updated_at"
+	ModelTagSoftDelete         ModelTags = "soft_delete"
+)
+
+// Field tag keys
+// These tags carry a value after a colon, for example `mongorm:"primary,id:uuidv7"`. Use
+// getModelTagValue to read the value associated with a key.
+// Example usage:
+//
+//	type ToDo struct {
+//	   // Generates a UUIDv7 string before insert when the ID is empty
+//	   ID *string `bson:"_id,omitempty" mongorm:"primary,id:uuidv7"`
+//	   // Filled from the "invoice" counter on insert
+//	   Number int64 `bson:"number" mongorm:"sequence:invoice"`
+//	}
+const (
+	ModelTagIDGenerator ModelTags = "id"
+	ModelTagSequence    ModelTags = "sequence"
+)
+
+// Index tags
+// These tags declare the indexes of a model. SyncIndexes creates them on the collection.
+// `index`, `unique` and `ttl:<seconds>` declare a single-field index; `index:<name>` adds
+// the field to the compound index with that name, ordered by `order:<n>`. `desc` makes the
+// key descending and `text` adds the field to the collection text index.
+// Example usage:
+//
+//	type Task struct {
+//	   Email     *string    `bson:"email" mongorm:"unique"`
+//	   UserID    *string    `bson:"user_id" mongorm:"index:user_status,order:1"`
+//	   Status    *string    `bson:"status" mongorm:"index:user_status,order:2"`
+//	   ExpiresAt *time.Time `bson:"expires_at" mongorm:"ttl:0"`
+//	   Body      *string    `bson:"body" mongorm:"text"`
+//	}
+const (
+	ModelTagIndex      ModelTags = "index"
+	ModelTagUnique     ModelTags = "unique"
+	ModelTagIndexOrder ModelTags = "order"
+	ModelTagIndexDesc  ModelTags = "desc"
+	ModelTagTTL        ModelTags = "ttl"
+	ModelTagText       ModelTags = "text"
 )
 
 // getFieldNameFromTag extracts the field name from the provided struct tag. If the tag is empty
@@ -87,6 +128,24 @@ func getModelTags(tag reflect.StructTag) []string {
 	return tags
 }
 
+// getModelTagValue returns the value of a `key:value` entry in the "mongorm" struct tag. For
+// example, for the tag `mongorm:"primary,id:uuidv7"` and key "id" it returns "uuidv7". The
+// second return value reports whether the key was present with a non-empty value.
+//
+// > NOTE: This function is internal only.
+func getModelTagValue(tag reflect.StructTag, key ModelTags) (string, bool) {
+	prefix := string(key) + ":"
+
+	for _, entry := range getModelTags(tag) {
+		if value, ok := strings.CutPrefix(entry, prefix); ok {
+			value = strings.TrimSpace(value)
+			return value, value != ""
+		}
+	}
+
+	return "", false
+}
+
 // MongORMOptions holds the configuration options for a MongORM instance, including settings for
 // timestamps, collection and database names, and the MongoDB client. This struct is used to
 // customize the behavior of the MongORM instance when connecting to the database and performing
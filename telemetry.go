@@ -0,0 +1,247 @@
+package mongorm
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// Attribute keys reported on operation spans and metrics.
+const (
+	AttributeOperation  = "mongorm.operation"
+	AttributeCollection = "mongorm.collection"
+	AttributeDocuments  = "mongorm.documents"
+	AttributeErrorClass = "mongorm.error_class"
+)
+
+// Metric names reported through Metrics.
+const (
+	// MetricOperations counts operations, by operation, collection and error class.
+	MetricOperations = "mongorm.operations"
+	// MetricOperationDuration observes operation latency in seconds.
+	MetricOperationDuration = "mongorm.operation.duration"
+	// MetricOperationDocuments observes the number of documents an operation returned
+	// or affected, when it is known.
+	MetricOperationDocuments = "mongorm.operation.documents"
+)
+
+// Attribute is a key/value pair attached to spans and metrics.
+type Attribute struct {
+	Key   string
+	Value any
+}
+
+// Tracer starts a span around every MongORM operation. Adapt it to OpenTelemetry or any
+// other tracing library; MongORM itself imports none.
+//
+// Example usage:
+//
+//	type otelTracer struct{ tracer trace.Tracer }
+//
+//	func (t otelTracer) StartSpan(ctx context.Context, name string, attrs ...mongorm.Attribute) (context.Context, mongorm.Span) {
+//	    ctx, span := t.tracer.Start(ctx, name, trace.WithAttributes(toOtel(attrs)...))
+//	    return ctx, otelSpan{span}
+//	}
+type Tracer interface {
+	// StartSpan starts a span named after the operation, such as "mongorm.Save". The
+	// returned context is passed to the operation, so its collection calls and hooks
+	// run inside the span.
+	StartSpan(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span)
+}
+
+// Span is a span started by a Tracer.
+type Span interface {
+	// End finishes the span with the operation error, or nil on success, and the
+	// attributes known once the operation completed.
+	End(err error, attrs ...Attribute)
+}
+
+// Metrics records counters and histograms for every MongORM operation. Adapt it to
+// Prometheus, OpenTelemetry or any other metrics library.
+type Metrics interface {
+	AddCounter(name string, value float64, attrs ...Attribute)
+	ObserveHistogram(name string, value float64, attrs ...Attribute)
+}
+
+// NoopTracer is a Tracer that records nothing. It is used when MongORMOptions.Tracer is
+// not set.
+type NoopTracer struct{}
+
+// StartSpan returns ctx and a span that records nothing.
+func (NoopTracer) StartSpan(ctx context.Context, _ string, _ ...Attribute) (context.Context, Span) {
+	return ctx, noopSpan{}
+}
+
+type noopSpan struct{}
+
+func (noopSpan) End(error, ...Attribute) {}
+
+// NoopMetrics is a Metrics implementation that records nothing. It is used when
+// MongORMOptions.Metrics is not set.
+type NoopMetrics struct{}
+
+// AddCounter does nothing.
+func (NoopMetrics) AddCounter(string, float64, ...Attribute) {}
+
+// ObserveHistogram does nothing.
+func (NoopMetrics) ObserveHistogram(string, float64, ...Attribute) {}
+
+// ErrorClass groups operation errors for spans and metrics.
+type ErrorClass string
+
+// Error classes reported under AttributeErrorClass.
+const (
+	ErrorClassNone                   ErrorClass = "none"
+	ErrorClassNotFound               ErrorClass = "NotFound"
+	ErrorClassDuplicateKey           ErrorClass = "DuplicateKey"
+	ErrorClassOptimisticLockConflict ErrorClass = "OptimisticLockConflict"
+	ErrorClassInvalidConfig          ErrorClass = "InvalidConfig"
+	ErrorClassTransactionUnsupported ErrorClass = "TransactionUnsupported"
+	ErrorClassCanceled               ErrorClass = "Canceled"
+	ErrorClassTimeout                ErrorClass = "Timeout"
+	ErrorClassOther                  ErrorClass = "Other"
+)
+
+// ClassifyError returns the class of an error returned by a MongORM operation.
+func ClassifyError(err error) ErrorClass {
+	switch {
+	case err == nil:
+		return ErrorClassNone
+	case errors.Is(err, ErrOptimisticLockConflict):
+		return ErrorClassOptimisticLockConflict
+	case errors.Is(err, ErrNotFound), errors.Is(err, mongo.ErrNoDocuments):
+		return ErrorClassNotFound
+	case errors.Is(err, ErrDuplicateKey), mongo.IsDuplicateKeyError(err):
+		return ErrorClassDuplicateKey
+	case errors.Is(err, ErrInvalidConfig):
+		return ErrorClassInvalidConfig
+	case IsTransactionUnsupported(err):
+		return ErrorClassTransactionUnsupported
+	case errors.Is(err, context.Canceled):
+		return ErrorClassCanceled
+	case errors.Is(err, context.DeadlineExceeded), mongo.IsTimeout(err):
+		return ErrorClassTimeout
+	default:
+		return ErrorClassOther
+	}
+}
+
+// operationTelemetry reports one operation to the configured Tracer and Metrics. A nil
+// *operationTelemetry reports nothing.
+//
+// > NOTE: This type is internal only.
+type operationTelemetry struct {
+	metrics Metrics
+	span    Span
+	start   time.Time
+	attrs   []Attribute
+}
+
+// startOperation starts reporting the operation named name. It returns the context to
+// run the operation with; call end on the result once the operation completed.
+//
+// > NOTE: This method is internal only.
+func (m *MongORM[T]) startOperation(ctx context.Context, name string) (context.Context, *operationTelemetry) {
+	if m == nil || m.options == nil || (m.options.Tracer == nil && m.options.Metrics == nil) {
+		return ctx, nil
+	}
+
+	collection := ""
+	if m.info != nil && m.info.collection != nil {
+		collection = m.info.collection.Name()
+	}
+
+	t := &operationTelemetry{
+		metrics: m.options.Metrics,
+		start:   time.Now(),
+		attrs: []Attribute{
+			{Key: AttributeOperation, Value: name},
+			{Key: AttributeCollection, Value: collection},
+		},
+	}
+	if t.metrics == nil {
+		t.metrics = NoopMetrics{}
+	}
+
+	tracer := m.options.Tracer
+	if tracer == nil {
+		tracer = NoopTracer{}
+	}
+	ctx, t.span = tracer.StartSpan(ctx, "mongorm."+name, t.attrs...)
+
+	return ctx, t
+}
+
+// end reports the outcome of the operation. result is the value returned by the
+// operation, from which the document count is derived when possible.
+//
+// > NOTE: This method is internal only.
+func (t *operationTelemetry) end(result any, err error) {
+	if t == nil {
+		return
+	}
+
+	class := ClassifyError(err)
+	attrs := append(t.attrs[:len(t.attrs):len(t.attrs)], Attribute{Key: AttributeErrorClass, Value: string(class)})
+
+	documents, known := operationDocuments(result)
+	known = known && err == nil
+
+	spanAttrs := attrs
+	if known {
+		spanAttrs = append(append([]Attribute{}, attrs...), Attribute{Key: AttributeDocuments, Value: documents})
+	}
+	if t.span != nil {
+		t.span.End(err, spanAttrs...)
+	}
+
+	t.metrics.AddCounter(MetricOperations, 1, attrs...)
+	t.metrics.ObserveHistogram(MetricOperationDuration, time.Since(t.start).Seconds(), attrs...)
+	if known {
+		t.metrics.ObserveHistogram(MetricOperationDocuments, float64(documents), attrs...)
+	}
+}
+
+// operationDocuments returns the number of documents an operation result returned or
+// affected. Cursors and streams report no count.
+//
+// > NOTE: This function is internal only.
+func operationDocuments(result any) (int64, bool) {
+	switch res := result.(type) {
+	case nil:
+		return 0, false
+	case int64:
+		return res, true
+	case *WriteResult:
+		if res != nil && (res.Inserted || res.Matched) {
+			return 1, true
+		}
+		return 0, res != nil
+	case *CreateManyResult:
+		if res != nil {
+			return res.InsertedCount, true
+		}
+	case *mongo.UpdateResult:
+		if res != nil {
+			return res.MatchedCount + res.UpsertedCount, true
+		}
+	case *mongo.DeleteResult:
+		if res != nil {
+			return res.DeletedCount, true
+		}
+	case *mongo.BulkWriteResult:
+		if res != nil {
+			return res.InsertedCount + res.MatchedCount + res.UpsertedCount + res.DeletedCount, true
+		}
+	default:
+		ref := reflect.ValueOf(result)
+		if ref.Kind() == reflect.Slice {
+			return int64(ref.Len()), true
+		}
+	}
+
+	return 0, false
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/azayn-labs/mongorm"
+	"github.com/azayn-labs/mongorm/memdb"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestResumeTokenStore(t *testing.T) {
+	db := memdb.New("orm-test")
+	store := mongorm.NewResumeTokenStore(db.Collection(mongorm.DefaultResumeTokensCollection))
+
+	t.Run("Missing checkpoints load as nil", func(t *testing.T) {
+		token, err := store.LoadResumeToken(t.Context(), "todo-consumer")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if token != nil {
+			t.Fatalf("expected no token, got %v", token)
+		}
+	})
+
+	t.Run("Saved checkpoints are replaced", func(t *testing.T) {
+		for _, data := range []string{"8263A1", "8263A2"} {
+			token, err := bson.Marshal(bson.M{"_data": data})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := store.SaveResumeToken(t.Context(), "todo-consumer", token); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		token, err := store.LoadResumeToken(t.Context(), "todo-consumer")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if data, _ := token.Lookup("_data").StringValueOK(); data != "8263A2" {
+			t.Fatalf("expected the latest token, got %v", token)
+		}
+
+		count, err := db.Collection(mongorm.DefaultResumeTokensCollection).CountDocuments(t.Context(), bson.M{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if count != 1 {
+			t.Fatalf("expected one checkpoint per consumer, got %d", count)
+		}
+	})
+}
+
+func TestConsume(t *testing.T) {
+	db := memdb.New("orm-test")
+	coll := &watchingCollection{Collection: db.Collection("todo_consumed")}
+	orm := func() *mongorm.MongORM[MemToDo] {
+		return mongorm.FromOptions(&MemToDo{}, &mongorm.MongORMOptions{Collection: coll})
+	}
+	handler := func(context.Context, *mongorm.ChangeEvent[MemToDo]) error { return nil }
+
+	t.Run("Consumers resume after their checkpoint", func(t *testing.T) {
+		store := mongorm.NewResumeTokenStore(db.Collection("checkpoints"))
+		token, err := bson.Marshal(bson.M{"_data": "8263A1"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := store.SaveResumeToken(t.Context(), "todo-consumer", token); err != nil {
+			t.Fatal(err)
+		}
+
+		err = orm().
+			Where(MemToDoFields.Count.Gt(1)).
+			Consume(t.Context(), "todo-consumer", handler, mongorm.ConsumeOptions{Store: store})
+		if !errors.Is(err, memdb.ErrUnsupported) {
+			t.Fatalf("expected memdb to reject change streams, got %v", err)
+		}
+
+		if resumeAfter, _ := coll.opts.ResumeAfter.(bson.Raw); !bytes.Equal(resumeAfter, token) {
+			t.Fatalf("expected the stream to resume after the checkpoint, got %v", coll.opts.ResumeAfter)
+		}
+		if len(coll.pipeline) != 1 {
+			t.Fatalf("expected Where filters to be applied, got %v", coll.pipeline)
+		}
+	})
+
+	t.Run("New consumers start from now", func(t *testing.T) {
+		store := mongorm.NewResumeTokenStore(db.Collection("checkpoints"))
+		_ = orm().Consume(t.Context(), "new-consumer", handler, mongorm.ConsumeOptions{Store: store})
+
+		if coll.opts.ResumeAfter != nil {
+			t.Fatalf("expected no resume token, got %v", coll.opts.ResumeAfter)
+		}
+	})
+
+	t.Run("Invalid consumers are rejected", func(t *testing.T) {
+		if err := orm().Consume(t.Context(), " ", handler); !errors.Is(err, mongorm.ErrInvalidConfig) {
+			t.Fatalf("expected an empty name to be rejected, got %v", err)
+		}
+		if err := orm().Consume(t.Context(), "todo-consumer", nil); !errors.Is(err, mongorm.ErrInvalidConfig) {
+			t.Fatalf("expected a nil handler to be rejected, got %v", err)
+		}
+		if err := orm().Consume(t.Context(), "todo-consumer", handler); !errors.Is(err, mongorm.ErrInvalidConfig) {
+			t.Fatalf("expected a custom collection without a store to be rejected, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/azayn-labs/mongorm"
+	"github.com/azayn-labs/mongorm/memdb"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// watchingCollection decorates a Collection and records the change stream it is asked to open.
+type watchingCollection struct {
+	mongorm.Collection
+	pipeline bson.A
+	opts     *options.ChangeStreamOptions
+}
+
+func (c *watchingCollection) Watch(
+	ctx context.Context,
+	pipeline any,
+	opts ...options.Lister[options.ChangeStreamOptions],
+) (*mongo.ChangeStream, error) {
+	c.pipeline, _ = pipeline.(bson.A)
+	c.opts = &options.ChangeStreamOptions{}
+	for _, opt := range opts {
+		for _, setter := range opt.List() {
+			if err := setter(c.opts); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return c.Collection.Watch(ctx, pipeline, opts...)
+}
+
+func TestChangeStream(t *testing.T) {
+	coll := &watchingCollection{Collection: memdb.New("orm-test").Collection("todo_changes")}
+	orm := func() *mongorm.MongORM[MemToDo] {
+		return mongorm.FromOptions(&MemToDo{}, &mongorm.MongORMOptions{Collection: coll})
+	}
+
+	t.Run("Where filters match the changed documents", func(t *testing.T) {
+		id := bson.NewObjectID()
+		_, err := orm().
+			Where(MemToDoFields.Count.Gt(1)).
+			Where(MemToDoFields.Meta.Source.Eq("api")).
+			OrWhere(MemToDoFields.ID.Eq(id)).
+			Watch(t.Context(), options.ChangeStream().SetBatchSize(5))
+		if !errors.Is(err, memdb.ErrUnsupported) {
+			t.Fatalf("expected memdb to reject change streams, got %v", err)
+		}
+
+		if len(coll.pipeline) != 1 {
+			t.Fatalf("expected a single $match stage, got %v", coll.pipeline)
+		}
+		match, _ := coll.pipeline[0].(bson.M)["$match"].(bson.M)
+		if match["fullDocument.count"] == nil || match["fullDocument.meta.source"] != "api" {
+			t.Fatalf("expected fields to be matched on fullDocument, got %v", match)
+		}
+		or, _ := match["$or"].(bson.A)
+		if len(or) != 1 || or[0].(bson.M)["documentKey._id"] != id {
+			t.Fatalf("expected _id to be matched on documentKey, got %v", match["$or"])
+		}
+
+		if coll.opts.FullDocument == nil || *coll.opts.FullDocument != options.UpdateLookup {
+			t.Fatal("expected update events to look up the full document")
+		}
+		if coll.opts.BatchSize == nil || *coll.opts.BatchSize != 5 {
+			t.Fatal("expected caller options to be applied")
+		}
+	})
+
+	t.Run("Unfiltered streams have an empty pipeline", func(t *testing.T) {
+		_, _ = orm().Watch(t.Context(), options.ChangeStream().SetFullDocument(options.Required))
+
+		if len(coll.pipeline) != 0 {
+			t.Fatalf("expected no stages, got %v", coll.pipeline)
+		}
+		if *coll.opts.FullDocument != options.Required {
+			t.Fatal("expected the caller FullDocument mode to take precedence")
+		}
+	})
+
+	t.Run("Operators without a document path are rejected", func(t *testing.T) {
+		_, err := orm().Where(bson.M{"$expr": bson.M{"$gt": bson.A{"$count", 1}}}).Watch(t.Context())
+		if !errors.Is(err, mongorm.ErrInvalidConfig) {
+			t.Fatalf("expected ErrInvalidConfig, got %v", err)
+		}
+	})
+
+	t.Run("Events decode into the schema type", func(t *testing.T) {
+		id := bson.NewObjectID()
+		wallTime := time.Now().UTC().Truncate(time.Millisecond)
+		raw, err := bson.Marshal(bson.M{
+			"_id":           bson.M{"_data": "8263A1"},
+			"operationType": "update",
+			"documentKey":   bson.M{"_id": id},
+			"fullDocument":  bson.M{"_id": id, "text": "updated", "count": int64(3)},
+			"updateDescription": bson.M{
+				"updatedFields":   bson.M{"text": "updated"},
+				"removedFields":   bson.A{"tags"},
+				"truncatedArrays": bson.A{},
+			},
+			"ns":          bson.M{"db": "orm-test", "coll": "todo_changes"},
+			"clusterTime": bson.Timestamp{T: 1, I: 2},
+			"wallTime":    wallTime,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var event mongorm.ChangeEvent[MemToDo]
+		if err := bson.Unmarshal(raw, &event); err != nil {
+			t.Fatal(err)
+		}
+
+		if event.OperationType != mongorm.ChangeOperationUpdate || event.DocumentKey["_id"] != id {
+			t.Fatalf("expected an update of %s, got %+v", id.Hex(), event)
+		}
+		if event.FullDocument == nil || mongorm.StringVal(event.FullDocument.Text) != "updated" || event.FullDocument.Count != 3 {
+			t.Fatalf("expected the full document to be decoded, got %+v", event.FullDocument)
+		}
+		if event.UpdateDescription == nil || event.UpdateDescription.UpdatedFields["text"] != "updated" || event.UpdateDescription.RemovedFields[0] != "tags" {
+			t.Fatalf("expected the update description to be decoded, got %+v", event.UpdateDescription)
+		}
+		if event.Namespace.Coll != "todo_changes" || event.ClusterTime.I != 2 || event.WallTime == nil || !event.WallTime.Equal(wallTime) {
+			t.Fatalf("expected the event metadata to be decoded, got %+v", event)
+		}
+		if data, _ := event.ResumeToken.Lookup("_data").StringValueOK(); data != "8263A1" {
+			t.Fatalf("expected the resume token to be kept, got %v", event.ResumeToken)
+		}
+	})
+
+	t.Run("Nil streams report errors", func(t *testing.T) {
+		var stream *mongorm.ChangeStream[MemToDo]
+		if stream.Next(t.Context()) || stream.ResumeToken() != nil || !errors.Is(stream.Err(), mongorm.ErrInvalidConfig) {
+			t.Fatal("expected a nil stream to be unusable")
+		}
+	})
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/azayn-labs/mongorm"
+	"github.com/azayn-labs/mongorm/memdb"
+	"github.com/azayn-labs/mongorm/primitives"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type CreateManyToDo struct {
+	ID        *bson.ObjectID `bson:"_id,omitempty" mongorm:"primary"`
+	Text      *string        `bson:"text,omitempty"`
+	Batch     *string        `bson:"batch,omitempty"`
+	Version   int64          `bson:"_version,omitempty" mongorm:"version"`
+	CreatedAt *time.Time     `bson:"created_at,omitempty" mongorm:"true,timestamp:created_at"`
+	UpdatedAt *time.Time     `bson:"updated_at,omitempty" mongorm:"true,timestamp:updated_at"`
+	Created   bool           `bson:"-"`
+
+	connectionString *string `mongorm:"mongodb://localhost:27017,connection:url"`
+	database         *string `mongorm:"orm-test,connection:database"`
+	collection       *string `mongorm:"todo_library_create_many,connection:collection"`
+}
+
+type CreateManyToDoSchema struct {
+	ID    *primitives.ObjectIDField
+	Text  *primitives.StringField
+	Batch *primitives.StringField
+}
+
+var CreateManyToDoFields = mongorm.FieldsOf[CreateManyToDo, CreateManyToDoSchema]()
+
+var errCreateManyRejected = errors.New("rejected by hook")
+
+func (t *CreateManyToDo) BeforeCreate(_ *mongorm.MongORM[CreateManyToDo]) error {
+	if mongorm.StringVal(t.Text) == "reject" {
+		return errCreateManyRejected
+	}
+	return nil
+}
+
+func (t *CreateManyToDo) AfterCreate(_ *mongorm.MongORM[CreateManyToDo]) error {
+	t.Created = true
+	return nil
+}
+
+func TestCreateManyResultErrSummarizesFailures(t *testing.T) {
+	var empty *mongorm.CreateManyResult
+	if empty.Err() != nil {
+		t.Fatal("expected nil result to have no error")
+	}
+
+	res := &mongorm.CreateManyResult{
+		InsertedIDs: []any{1, nil, nil},
+		Errors:      []error{nil, mongorm.ErrDuplicateKey, mongorm.ErrNotAttempted},
+	}
+
+	err := res.Err()
+	if !errors.Is(err, mongorm.ErrDuplicateKey) || !errors.Is(err, mongorm.ErrNotAttempted) {
+		t.Fatalf("expected summarized error to wrap document errors, got %v", err)
+	}
+}
+
+func TestCreateManyRequiresNonObjectIDKeys(t *testing.T) {
+	db := memdb.New("orm-test")
+	docs := []*StringKeyToDo{
+		{ID: mongorm.String("keyed"), Text: mongorm.String("keyed")},
+		{Text: mongorm.String("unkeyed")},
+	}
+
+	res, err := mongorm.FromOptions(&StringKeyToDo{}, &mongorm.MongORMOptions{Backend: db}).
+		CreateMany(t.Context(), docs, options.InsertMany().SetOrdered(false))
+	if !errors.Is(err, mongorm.ErrInvalidConfig) || !errors.Is(res.Errors[1], mongorm.ErrInvalidConfig) {
+		t.Fatalf("expected the document without a key to fail, got %v", err)
+	}
+	if res.Errors[0] != nil || res.InsertedIDs[0] != "keyed" || docs[1].ID != nil {
+		t.Fatalf("expected only the keyed document to be inserted, got %+v", res)
+	}
+}
+
+func ValidateLibraryCreateMany(t *testing.T) {
+	batch := "create-many-" + time.Now().Format(time.RFC3339Nano)
+	newDoc := func(text string) *CreateManyToDo {
+		return &CreateManyToDo{Text: mongorm.String(text), Batch: mongorm.String(batch)}
+	}
+
+	defer func() {
+		_, _ = mongorm.New(&CreateManyToDo{}).
+			Where(CreateManyToDoFields.Batch.Eq(batch)).
+			DeleteMulti(t.Context())
+	}()
+
+	t.Run("Inserts every document", func(t *testing.T) {
+		docs := []*CreateManyToDo{newDoc("a"), newDoc("b")}
+
+		res, err := mongorm.New(&CreateManyToDo{}).CreateMany(t.Context(), docs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.InsertedCount != 2 {
+			t.Fatalf("expected 2 inserted documents, got %d", res.InsertedCount)
+		}
+
+		for i, doc := range docs {
+			if doc.ID == nil || res.InsertedIDs[i] != *doc.ID {
+				t.Fatalf("expected generated id to be filled back into document %d", i)
+			}
+			if doc.Version != 1 {
+				t.Fatalf("expected version 1 on document %d, got %d", i, doc.Version)
+			}
+			if doc.CreatedAt == nil || doc.UpdatedAt == nil {
+				t.Fatalf("expected timestamps on document %d", i)
+			}
+			if !doc.Created {
+				t.Fatalf("expected AfterCreate to run for document %d", i)
+			}
+		}
+	})
+
+	t.Run("Ordered insert stops at the first failure", func(t *testing.T) {
+		existing := newDoc("dup")
+		if err := mongorm.New(existing).Save(t.Context()); err != nil {
+			t.Fatal(err)
+		}
+
+		duplicate := newDoc("dup")
+		duplicate.ID = existing.ID
+		docs := []*CreateManyToDo{newDoc("c"), duplicate, newDoc("d")}
+
+		res, err := mongorm.New(&CreateManyToDo{}).CreateMany(t.Context(), docs)
+		if !errors.Is(err, mongorm.ErrDuplicateKey) {
+			t.Fatalf("expected duplicate key error, got %v", err)
+		}
+		if res.InsertedCount != 1 || res.Errors[0] != nil {
+			t.Fatalf("expected only the first document to be inserted, got %+v", res)
+		}
+		if !errors.Is(res.Errors[1], mongorm.ErrDuplicateKey) {
+			t.Fatalf("expected duplicate key error for document 1, got %v", res.Errors[1])
+		}
+		if !errors.Is(res.Errors[2], mongorm.ErrNotAttempted) || docs[2].Created {
+			t.Fatalf("expected document 2 to be skipped, got %v", res.Errors[2])
+		}
+	})
+
+	t.Run("Unordered insert reports per-document errors", func(t *testing.T) {
+		docs := []*CreateManyToDo{newDoc("e"), newDoc("reject"), newDoc("f")}
+
+		res, err := mongorm.New(&CreateManyToDo{}).CreateMany(
+			t.Context(),
+			docs,
+			options.InsertMany().SetOrdered(false),
+		)
+		if !errors.Is(err, errCreateManyRejected) {
+			t.Fatalf("expected hook error to be reported, got %v", err)
+		}
+		if res.InsertedCount != 2 || res.Errors[0] != nil || res.Errors[2] != nil {
+			t.Fatalf("expected documents 0 and 2 to be inserted, got %+v", res)
+		}
+		if !errors.Is(res.Errors[1], errCreateManyRejected) || res.InsertedIDs[1] != nil {
+			t.Fatalf("expected document 1 to be rejected, got %v", res.Errors[1])
+		}
+	})
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/azayn-labs/mongorm"
+	"github.com/azayn-labs/mongorm/memdb"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type CursorHookToDo struct {
+	ID    *bson.ObjectID `bson:"_id,omitempty" mongorm:"primary"`
+	Text  *string        `bson:"text,omitempty"`
+	Label string         `bson:"-"`
+
+	collection *string `mongorm:"todo_cursor_hooks,connection:collection"`
+}
+
+func (t *CursorHookToDo) AfterFinalize(*mongorm.MongORM[CursorHookToDo]) error {
+	if t.Text != nil && *t.Text == "broken" {
+		return errors.New("broken document")
+	}
+	return nil
+}
+
+func (t *CursorHookToDo) AfterFind(*mongorm.MongORM[CursorHookToDo]) error {
+	t.Label = strings.ToUpper(*t.Text)
+	return nil
+}
+
+type cursorHookDocumentsKey struct{}
+
+type CursorHookPreview struct {
+	Text     string `bson:"text"`
+	Label    string `bson:"-"`
+	Prepared bool   `bson:"-"`
+}
+
+func (p *CursorHookPreview) BeforeFinalize(*mongorm.MongORM[CursorHookToDo]) error {
+	p.Prepared = p.Text != ""
+	return nil
+}
+
+func (p *CursorHookPreview) AfterFind(*mongorm.MongORM[CursorHookToDo]) error {
+	p.Label = strings.ToUpper(p.Text)
+	return nil
+}
+
+func TestCursorHooks(t *testing.T) {
+	db := memdb.New("orm-test")
+	orm := func() *mongorm.MongORM[CursorHookToDo] {
+		return mongorm.FromOptions(&CursorHookToDo{}, &mongorm.MongORMOptions{Backend: db})
+	}
+
+	for _, text := range []string{"first", "second"} {
+		if _, err := mongorm.FromOptions(&CursorHookToDo{Text: mongorm.String(text)}, &mongorm.MongORMOptions{Backend: db}).Create(t.Context()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("Next and All run the load hooks", func(t *testing.T) {
+		cursor, err := orm().Sort(bson.D{{Key: "_id", Value: 1}}).FindAll(t.Context())
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer cursor.Close(t.Context())
+
+		var labels []string
+		for cursor.Next(t.Context()) {
+			labels = append(labels, cursor.Current().Document().Label)
+		}
+		if err := cursor.Err(); err != nil {
+			t.Fatal(err)
+		}
+		if strings.Join(labels, ",") != "FIRST,SECOND" {
+			t.Fatalf("expected AfterFind to fill every document, got %v", labels)
+		}
+
+		cursor, err = orm().Aggregate(t.Context(), bson.A{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		items, err := cursor.All(t.Context())
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, item := range items {
+			if item.Document().Label == "" {
+				t.Fatalf("expected All to run AfterFind, got %+v", item.Document())
+			}
+		}
+	})
+
+	t.Run("Projections run the hooks of the result type", func(t *testing.T) {
+		previews, err := mongorm.FindAllAs[CursorHookToDo, CursorHookPreview](orm(), t.Context())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(previews) != 2 || previews[0].Label != strings.ToUpper(previews[0].Text) {
+			t.Fatalf("expected FindAllAs to run AfterFind, got %+v", previews)
+		}
+		if !previews[0].Prepared {
+			t.Fatalf("expected BeforeFinalize to run on the decoded value, got %+v", previews[0])
+		}
+
+		previews, err = mongorm.AggregateAs[CursorHookToDo, CursorHookPreview](orm(), t.Context(), bson.A{})
+		if err != nil || len(previews) != 2 || previews[1].Label == "" {
+			t.Fatalf("expected AggregateAs to run AfterFind, got %+v %v", previews, err)
+		}
+	})
+
+	t.Run("Projections pass the decoded values to the hooks", func(t *testing.T) {
+		mongorm.RegisterHook(mongorm.HookAfterFind, func(ctx context.Context, _ *mongorm.MongORM[CursorHookToDo], args *mongorm.HookArgs) error {
+			if documents, ok := ctx.Value(cursorHookDocumentsKey{}).(*[]any); ok {
+				*documents = append(*documents, args.Document)
+			}
+			return nil
+		})
+
+		documents := &[]any{}
+		ctx := context.WithValue(t.Context(), cursorHookDocumentsKey{}, documents)
+		previews, err := mongorm.FindAllAs[CursorHookToDo, CursorHookPreview](orm().Sort(bson.D{{Key: "_id", Value: 1}}), ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(previews) != 2 || !previews[0].Prepared || !previews[1].Prepared {
+			t.Fatalf("expected BeforeFinalize to see the decoded values, got %+v", previews)
+		}
+		if len(*documents) != 2 {
+			t.Fatalf("expected the registered hook to run for every document, got %v", *documents)
+		}
+		if preview, ok := (*documents)[0].(*CursorHookPreview); !ok || preview.Text != "first" {
+			t.Fatalf("expected the registered hook to receive the decoded result, got %#v", (*documents)[0])
+		}
+	})
+
+	t.Run("WithoutCursorHooks skips them", func(t *testing.T) {
+		skipped := orm()
+		cursor, err := skipped.WithoutCursorHooks().FindAll(t.Context())
+		if err != nil {
+			t.Fatal(err)
+		}
+		items, err := cursor.All(t.Context())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(items) != 2 || items[0].Document().Label != "" {
+			t.Fatalf("expected no hooks to run, got %+v", items[0].Document())
+		}
+
+		cursor, err = skipped.FindAll(t.Context())
+		if err != nil {
+			t.Fatal(err)
+		}
+		items, err = cursor.All(t.Context())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(items) != 2 || items[0].Document().Label == "" {
+			t.Fatalf("expected the next FindAll to run the hooks again, got %+v", items[0].Document())
+		}
+
+		skipped = orm()
+		previews, err := mongorm.FindAllAs[CursorHookToDo, CursorHookPreview](skipped.WithoutCursorHooks(), t.Context())
+		if err != nil || len(previews) != 2 || previews[0].Label != "" {
+			t.Fatalf("expected no hooks to run, got %+v %v", previews, err)
+		}
+
+		previews, err = mongorm.FindAllAs[CursorHookToDo, CursorHookPreview](skipped, t.Context())
+		if err != nil || len(previews) != 2 || previews[0].Label == "" {
+			t.Fatalf("expected the next FindAllAs to run the hooks again, got %+v %v", previews, err)
+		}
+	})
+
+	t.Run("Hook errors stop the iteration", func(t *testing.T) {
+		if _, err := db.Collection("todo_cursor_hooks").InsertOne(t.Context(), bson.M{"text": "broken"}); err != nil {
+			t.Fatal(err)
+		}
+
+		cursor, err := orm().FindAll(t.Context())
+		if err != nil {
+			t.Fatal(err)
+		}
+		for cursor.Next(t.Context()) {
+		}
+		if err := cursor.Err(); err == nil || err.Error() != "broken document" {
+			t.Fatalf("expected the hook error, got %v", err)
+		}
+	})
+}
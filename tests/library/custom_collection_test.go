@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/azayn-labs/mongorm"
+	"github.com/azayn-labs/mongorm/memdb"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// recordingCollection decorates a Collection and records the operations it receives.
+type recordingCollection struct {
+	mongorm.Collection
+	calls []string
+}
+
+func (c *recordingCollection) InsertOne(
+	ctx context.Context,
+	document any,
+	opts ...options.Lister[options.InsertOneOptions],
+) (*mongo.InsertOneResult, error) {
+	c.calls = append(c.calls, "InsertOne")
+	return c.Collection.InsertOne(ctx, document, opts...)
+}
+
+func (c *recordingCollection) FindOne(
+	ctx context.Context,
+	filter any,
+	opts ...options.Lister[options.FindOneOptions],
+) *mongo.SingleResult {
+	c.calls = append(c.calls, "FindOne")
+	return c.Collection.FindOne(ctx, filter, opts...)
+}
+
+func (c *recordingCollection) CountDocuments(
+	ctx context.Context,
+	filter any,
+	opts ...options.Lister[options.CountOptions],
+) (int64, error) {
+	c.calls = append(c.calls, "CountDocuments")
+	return c.Collection.CountDocuments(ctx, filter, opts...)
+}
+
+func TestCustomCollection(t *testing.T) {
+	coll := &recordingCollection{Collection: memdb.New("orm-test").Collection("todo_custom")}
+	orm := func(doc *MemToDo) *mongorm.MongORM[MemToDo] {
+		return mongorm.FromOptions(doc, &mongorm.MongORMOptions{
+			Collection:     coll,
+			CollectionName: mongorm.String("ignored"),
+		})
+	}
+
+	if _, err := orm(&MemToDo{Text: mongorm.String("custom")}).Create(t.Context()); err != nil {
+		t.Fatal(err)
+	}
+
+	found := &MemToDo{}
+	if err := orm(found).Where(MemToDoFields.Text.Eq("custom")).First(t.Context()); err != nil {
+		t.Fatal(err)
+	}
+	if mongorm.StringVal(found.Text) != "custom" {
+		t.Fatalf("expected the created document, got %+v", found)
+	}
+
+	total, err := orm(&MemToDo{}).Count(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 1 {
+		t.Fatalf("expected 1 document, got %d", total)
+	}
+
+	for _, call := range []string{"InsertOne", "FindOne", "CountDocuments"} {
+		if !slices.Contains(coll.calls, call) {
+			t.Fatalf("expected %s to go through the custom collection, got %v", call, coll.calls)
+		}
+	}
+
+	_, err = orm(&MemToDo{}).NextSequence(t.Context(), "todos")
+	if !errors.Is(err, mongorm.ErrInvalidConfig) {
+		t.Fatalf("expected sequences without a database to fail with ErrInvalidConfig, got %v", err)
+	}
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/azayn-labs/mongorm"
+	"github.com/azayn-labs/mongorm/memdb"
+	"github.com/azayn-labs/mongorm/primitives"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type StringKeyToDo struct {
+	ID      *string `bson:"_id,omitempty" mongorm:"primary"`
+	Text    *string `bson:"text,omitempty"`
+	Version int64   `bson:"_version,omitempty" mongorm:"version"`
+
+	connectionString *string `mongorm:"mongodb://localhost:27017,connection:url"`
+	database         *string `mongorm:"orm-test,connection:database"`
+	collection       *string `mongorm:"todo_library_string_keys,connection:collection"`
+}
+
+type StringKeyToDoSchema struct {
+	ID      *primitives.StringField
+	Text    *primitives.StringField
+	Version *primitives.Int64Field
+}
+
+var StringKeyToDoFields = mongorm.FieldsOf[StringKeyToDo, StringKeyToDoSchema]()
+
+type Int64KeyToDo struct {
+	ID   int64   `bson:"_id,omitempty" mongorm:"primary"`
+	Text *string `bson:"text,omitempty"`
+
+	connectionString *string `mongorm:"mongodb://localhost:27017,connection:url"`
+	database         *string `mongorm:"orm-test,connection:database"`
+	collection       *string `mongorm:"todo_library_int_keys,connection:collection"`
+}
+
+func TestResolvedRawQueryUsesStringPrimaryKeyAsIs(t *testing.T) {
+	// A 24-char hex string must not be coerced into an ObjectID.
+	id := "65f0c0ffee65f0c0ffee65f0"
+	model := mongorm.New(&StringKeyToDo{ID: mongorm.String(id)})
+
+	resolved, err := model.GetResolvedRawQuery()
+	if err != nil {
+		t.Fatalf("expected resolved query without error, got: %v", err)
+	}
+
+	if value, ok := resolved["_id"].(string); !ok || value != id {
+		t.Fatalf("expected string _id filter %q, got: %#v", id, resolved["_id"])
+	}
+}
+
+func TestResolvedRawQueryUsesInt64PrimaryKey(t *testing.T) {
+	model := mongorm.New(&Int64KeyToDo{ID: 42})
+
+	resolved, err := model.GetResolvedRawQuery()
+	if err != nil {
+		t.Fatalf("expected resolved query without error, got: %v", err)
+	}
+
+	if value, ok := resolved["_id"].(int64); !ok || value != 42 {
+		t.Fatalf("expected int64 _id filter 42, got: %#v", resolved["_id"])
+	}
+
+	empty := mongorm.New(&Int64KeyToDo{})
+	resolved, err = empty.GetResolvedRawQuery()
+	if err != nil {
+		t.Fatalf("expected resolved query without error, got: %v", err)
+	}
+
+	if _, exists := resolved["_id"]; exists {
+		t.Fatalf("expected zero int64 key to be ignored, got: %#v", resolved)
+	}
+}
+
+// hexIDCollection reports inserted ObjectIDs as hex strings, like some collection adapters.
+type hexIDCollection struct {
+	mongorm.Collection
+}
+
+func (c *hexIDCollection) InsertOne(ctx context.Context, document any, opts ...options.Lister[options.InsertOneOptions]) (*mongo.InsertOneResult, error) {
+	res, err := c.Collection.InsertOne(ctx, document, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if id, ok := res.InsertedID.(bson.ObjectID); ok {
+		res.InsertedID = id.Hex()
+	}
+	return res, nil
+}
+
+func TestObjectIDPrimaryKeyConvertsHexStrings(t *testing.T) {
+	coll := &hexIDCollection{Collection: memdb.New("orm-test").Collection("todo_hex_ids")}
+
+	todo := &MemToDo{Text: mongorm.String("hex")}
+	if err := mongorm.FromOptions(todo, &mongorm.MongORMOptions{Collection: coll}).Save(t.Context()); err != nil {
+		t.Fatal(err)
+	}
+	if todo.ID == nil || todo.ID.IsZero() {
+		t.Fatalf("expected the hex inserted id to be loaded as an ObjectID, got %+v", todo)
+	}
+
+	resolved, err := mongorm.FromOptions(todo, &mongorm.MongORMOptions{Collection: coll}).GetResolvedRawQuery()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id, ok := resolved["_id"].(bson.ObjectID); !ok || id != *todo.ID {
+		t.Fatalf("expected an ObjectID _id filter, got %#v", resolved["_id"])
+	}
+}
+
+func ValidateLibraryCustomPrimaryKeys(t *testing.T) {
+	t.Run("String keys", func(t *testing.T) {
+		id := fmt.Sprintf("todo-%d", time.Now().UnixNano())
+		text := "string-key-" + time.Now().Format(time.RFC3339Nano)
+
+		created := &StringKeyToDo{ID: mongorm.String(id), Text: mongorm.String(text)}
+		if err := mongorm.New(created).Save(t.Context()); err != nil {
+			t.Fatal(err)
+		}
+
+		if mongorm.StringVal(created.ID) != id || created.Version != 1 {
+			t.Fatalf("expected created document with id=%s version=1, got: %+v", id, created)
+		}
+
+		found := &StringKeyToDo{ID: mongorm.String(id)}
+		if err := mongorm.New(found).First(t.Context()); err != nil {
+			t.Fatal(err)
+		}
+
+		if mongorm.StringVal(found.Text) != text {
+			t.Fatalf("expected text %q, got %q", text, mongorm.StringVal(found.Text))
+		}
+
+		updater := mongorm.New(found)
+		updater.Set(&StringKeyToDo{Text: mongorm.String(text + "-v2")})
+		if err := updater.Save(t.Context()); err != nil {
+			t.Fatal(err)
+		}
+
+		if found.Version != 2 || mongorm.StringVal(found.Text) != text+"-v2" {
+			t.Fatalf("expected versioned update, got: %+v", found)
+		}
+
+		if err := mongorm.New(&StringKeyToDo{ID: mongorm.String(id), Version: found.Version}).
+			SetData(StringKeyToDoFields.Text, text+"-v3").
+			FindOneAndUpdate(t.Context()); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := mongorm.New(&StringKeyToDo{ID: mongorm.String(id)}).Delete(t.Context()); err != nil {
+			t.Fatal(err)
+		}
+
+		err := mongorm.New(&StringKeyToDo{ID: mongorm.String(id)}).First(t.Context())
+		if !errors.Is(err, mongorm.ErrNotFound) {
+			t.Fatalf("expected ErrNotFound after delete, got: %v", err)
+		}
+	})
+
+	t.Run("Int64 keys", func(t *testing.T) {
+		id := time.Now().UnixNano()
+
+		created := &Int64KeyToDo{ID: id, Text: mongorm.String("int64-key")}
+		if err := mongorm.New(created).Save(t.Context()); err != nil {
+			t.Fatal(err)
+		}
+		defer func() {
+			_ = mongorm.New(&Int64KeyToDo{ID: id}).Delete(t.Context())
+		}()
+
+		found := &Int64KeyToDo{ID: id}
+		if err := mongorm.New(found).First(t.Context()); err != nil {
+			t.Fatal(err)
+		}
+
+		if mongorm.StringVal(found.Text) != "int64-key" {
+			t.Fatalf("expected text int64-key, got %q", mongorm.StringVal(found.Text))
+		}
+	})
+}
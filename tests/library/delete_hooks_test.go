@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/azayn-labs/mongorm"
+	"github.com/azayn-labs/mongorm/memdb"
+	"github.com/azayn-labs/mongorm/primitives"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type DeleteHookToDo struct {
+	ID        *bson.ObjectID `bson:"_id,omitempty" mongorm:"primary"`
+	Text      *string        `bson:"text,omitempty"`
+	DeletedAt *time.Time     `bson:"deleted_at,omitempty" mongorm:"soft_delete"`
+
+	collection *string `mongorm:"todo_delete_hooks,connection:collection"`
+}
+
+type DeleteHookToDoSchema struct {
+	ID   *primitives.ObjectIDField
+	Text *primitives.StringField
+}
+
+var DeleteHookToDoFields = mongorm.FieldsOf[DeleteHookToDo, DeleteHookToDoSchema]()
+
+func (t *DeleteHookToDo) AfterDelete(*mongorm.MongORM[DeleteHookToDo]) error {
+	if t.Text == nil {
+		return errors.New("AfterDelete ran without the deleted document")
+	}
+	return nil
+}
+
+func (t *DeleteHookToDo) AfterDeleteWithContext(ctx context.Context, _ *mongorm.MongORM[DeleteHookToDo]) error {
+	return recordHook(ctx, "AfterDeleteWithContext "+*t.Text)
+}
+
+func TestAfterDeleteHooks(t *testing.T) {
+	db := memdb.New("orm-test")
+	orm := func(doc *DeleteHookToDo) *mongorm.MongORM[DeleteHookToDo] {
+		return mongorm.FromOptions(doc, &mongorm.MongORMOptions{Backend: db})
+	}
+
+	var registered []string
+	mongorm.RegisterHook(mongorm.HookAfterDelete, func(_ context.Context, _ *mongorm.MongORM[DeleteHookToDo], args *mongorm.HookArgs) error {
+		doc, ok := args.Document.(*DeleteHookToDo)
+		if !ok || doc == nil {
+			return errors.New("AfterDelete registered hook ran without the deleted document")
+		}
+		registered = append(registered, *doc.Text)
+		return nil
+	})
+
+	for _, tc := range []struct {
+		name   string
+		delete func(*mongorm.MongORM[DeleteHookToDo], context.Context) error
+	}{
+		{"Soft delete", (*mongorm.MongORM[DeleteHookToDo]).Delete},
+		{"Hard delete", (*mongorm.MongORM[DeleteHookToDo]).ForceDelete},
+	} {
+		t.Run(tc.name+" runs AfterDelete on the deleted document", func(t *testing.T) {
+			todo := &DeleteHookToDo{Text: mongorm.String(tc.name)}
+			if _, err := orm(todo).Create(t.Context()); err != nil {
+				t.Fatal(err)
+			}
+
+			calls := &[]string{}
+			ctx := context.WithValue(t.Context(), hookCallsKey{}, calls)
+			if err := tc.delete(orm(todo), ctx); err != nil {
+				t.Fatal(err)
+			}
+			if len(*calls) != 1 || (*calls)[0] != "AfterDeleteWithContext "+tc.name {
+				t.Fatalf("expected AfterDeleteWithContext to read the document, got %v", *calls)
+			}
+			if len(registered) == 0 || registered[len(registered)-1] != tc.name {
+				t.Fatalf("expected the registered hook to read the document, got %v", registered)
+			}
+		})
+	}
+}
@@ -0,0 +1,283 @@
+package main
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/azayn-labs/mongorm"
+	"github.com/azayn-labs/mongorm/memdb"
+	"github.com/azayn-labs/mongorm/primitives"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type WriteToDo struct {
+	ID        *bson.ObjectID `bson:"_id,omitempty" mongorm:"primary"`
+	Email     *string        `bson:"email,omitempty"`
+	Name      *string        `bson:"name,omitempty"`
+	CreatedAt *time.Time     `bson:"created_at,omitempty" mongorm:"true,timestamp:created_at"`
+	UpdatedAt *time.Time     `bson:"updated_at,omitempty" mongorm:"true,timestamp:updated_at"`
+
+	connectionString *string `mongorm:"mongodb://localhost:27017,connection:url"`
+	database         *string `mongorm:"orm-test,connection:database"`
+	collection       *string `mongorm:"todo_library_writes,connection:collection"`
+}
+
+type WriteToDoSchema struct {
+	ID        *primitives.ObjectIDField
+	Email     *primitives.StringField
+	Name      *primitives.StringField
+	CreatedAt *primitives.TimestampField
+	UpdatedAt *primitives.TimestampField
+}
+
+var WriteToDoFields = mongorm.FieldsOf[WriteToDo, WriteToDoSchema]()
+
+type GeneratedWriteToDo struct {
+	ID    *string `bson:"_id,omitempty" mongorm:"primary,id:uuidv7"`
+	Email *string `bson:"email,omitempty"`
+	Name  *string `bson:"name,omitempty"`
+
+	collection *string `mongorm:"todo_generated_writes,connection:collection"`
+}
+
+type GeneratedWriteToDoSchema struct {
+	ID    *primitives.StringField
+	Email *primitives.StringField
+	Name  *primitives.StringField
+}
+
+var GeneratedWriteToDoFields = mongorm.FieldsOf[GeneratedWriteToDo, GeneratedWriteToDoSchema]()
+
+var writeHookCalls []string
+
+func (t *WriteToDo) BeforeSave(_ *mongorm.MongORM[WriteToDo], _ *bson.M) error {
+	writeHookCalls = append(writeHookCalls, "BeforeSave")
+	return nil
+}
+
+func (t *WriteToDo) BeforeCreate(_ *mongorm.MongORM[WriteToDo]) error {
+	writeHookCalls = append(writeHookCalls, "BeforeCreate")
+	return nil
+}
+
+func (t *WriteToDo) AfterCreate(_ *mongorm.MongORM[WriteToDo]) error {
+	writeHookCalls = append(writeHookCalls, "AfterCreate")
+	return nil
+}
+
+func (t *WriteToDo) BeforeUpdate(_ *mongorm.MongORM[WriteToDo], _ *bson.M, _ *bson.M) error {
+	writeHookCalls = append(writeHookCalls, "BeforeUpdate")
+	return nil
+}
+
+func (t *WriteToDo) AfterUpdate(_ *mongorm.MongORM[WriteToDo]) error {
+	writeHookCalls = append(writeHookCalls, "AfterUpdate")
+	return nil
+}
+
+func (t *WriteToDo) AfterSave(_ *mongorm.MongORM[WriteToDo]) error {
+	writeHookCalls = append(writeHookCalls, "AfterSave")
+	return nil
+}
+
+func expectWriteHooks(t *testing.T, expected ...string) {
+	t.Helper()
+
+	if len(writeHookCalls) != len(expected) {
+		t.Fatalf("expected hooks %v, got %v", expected, writeHookCalls)
+	}
+	for i, name := range expected {
+		if writeHookCalls[i] != name {
+			t.Fatalf("expected hooks %v, got %v", expected, writeHookCalls)
+		}
+	}
+
+	writeHookCalls = nil
+}
+
+func ValidateLibraryExplicitWrites(t *testing.T) {
+	email := "writes-" + bson.NewObjectID().Hex() + "@example.com"
+	writeHookCalls = nil
+
+	created := &WriteToDo{Email: mongorm.String(email), Name: mongorm.String("Ann")}
+	defer func() {
+		_, _ = mongorm.New(&WriteToDo{}).Where(WriteToDoFields.Email.Eq(email)).DeleteMulti(t.Context())
+	}()
+
+	t.Run("Create always inserts", func(t *testing.T) {
+		res, err := mongorm.New(created).Create(t.Context())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !res.Inserted || res.Matched || res.ID == nil {
+			t.Fatalf("expected inserted result with id, got %+v", res)
+		}
+		if created.CreatedAt == nil || created.UpdatedAt == nil {
+			t.Fatal("expected timestamps to be applied on create")
+		}
+		expectWriteHooks(t, "BeforeSave", "BeforeCreate", "AfterCreate", "AfterSave")
+
+		duplicate := &WriteToDo{ID: created.ID, Email: mongorm.String(email)}
+		_, err = mongorm.New(duplicate).Create(t.Context())
+		if !errors.Is(err, mongorm.ErrDuplicateKey) {
+			t.Fatalf("expected duplicate key error when creating an existing id, got %v", err)
+		}
+		writeHookCalls = nil
+	})
+
+	t.Run("Update modifies existing documents only", func(t *testing.T) {
+		res, err := mongorm.New(&WriteToDo{}).
+			Where(WriteToDoFields.Email.Eq(email)).
+			Set(&WriteToDo{Name: mongorm.String("Bob")}).
+			Update(t.Context())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Inserted || !res.Matched || !res.Modified || res.ID != *created.ID {
+			t.Fatalf("expected matched and modified result for %s, got %+v", created.ID.Hex(), res)
+		}
+		expectWriteHooks(t, "BeforeSave", "BeforeUpdate", "AfterUpdate", "AfterSave")
+
+		_, err = mongorm.New(&WriteToDo{}).
+			Where(WriteToDoFields.Email.Eq("missing-" + email)).
+			Set(&WriteToDo{Name: mongorm.String("Nobody")}).
+			Update(t.Context())
+		if !errors.Is(err, mongorm.ErrNotFound) {
+			t.Fatalf("expected not found when updating a missing document, got %v", err)
+		}
+		writeHookCalls = nil
+
+		total, err := mongorm.New(&WriteToDo{}).
+			Where(WriteToDoFields.Name.Eq("Nobody")).
+			Where(WriteToDoFields.Email.Eq("missing-" + email)).
+			Count(t.Context())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if total != 0 {
+			t.Fatalf("expected Update to never insert, found %d documents", total)
+		}
+	})
+
+	t.Run("Upsert by key fields", func(t *testing.T) {
+		model := &WriteToDo{Email: mongorm.String(email), Name: mongorm.String("Cleo")}
+		res, err := mongorm.New(model).Upsert(t.Context(), WriteToDoFields.Email)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Inserted || !res.Matched || !res.Modified || res.ID != *created.ID {
+			t.Fatalf("expected upsert to match the existing document, got %+v", res)
+		}
+		if mongorm.StringVal(model.Name) != "Cleo" || model.CreatedAt == nil || !model.CreatedAt.Equal(*created.CreatedAt) {
+			t.Fatalf("expected name update with created_at preserved, got %+v", model)
+		}
+		expectWriteHooks(t, "BeforeSave", "BeforeUpdate", "AfterUpdate", "AfterSave")
+
+		otherEmail := "other-" + email
+		defer func() {
+			_, _ = mongorm.New(&WriteToDo{}).Where(WriteToDoFields.Email.Eq(otherEmail)).DeleteMulti(t.Context())
+		}()
+
+		inserted := &WriteToDo{Email: mongorm.String(otherEmail), Name: mongorm.String("Dan")}
+		res, err = mongorm.New(inserted).Upsert(t.Context(), WriteToDoFields.Email)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !res.Inserted || res.Matched || inserted.ID == nil || res.ID != *inserted.ID {
+			t.Fatalf("expected upsert to insert a new document, got %+v", res)
+		}
+		if inserted.CreatedAt == nil || inserted.UpdatedAt == nil {
+			t.Fatal("expected timestamps to be applied on upsert insert")
+		}
+		expectWriteHooks(t, "BeforeSave", "BeforeUpdate", "AfterCreate", "AfterSave")
+	})
+}
+
+func TestExplicitWritesInMemory(t *testing.T) {
+	db := memdb.New("orm-test")
+	orm := func(doc *WriteToDo) *mongorm.MongORM[WriteToDo] {
+		return mongorm.FromOptions(doc, &mongorm.MongORMOptions{Backend: db})
+	}
+	writeHookCalls = nil
+
+	t.Run("Upsert runs BeforeUpdate for both outcomes", func(t *testing.T) {
+		todo := &WriteToDo{Email: mongorm.String("upsert@example.com"), Name: mongorm.String("Ann")}
+		if _, err := orm(todo).Upsert(t.Context(), WriteToDoFields.Email); err != nil {
+			t.Fatal(err)
+		}
+		expectWriteHooks(t, "BeforeSave", "BeforeUpdate", "AfterCreate", "AfterSave")
+
+		todo = &WriteToDo{Email: mongorm.String("upsert@example.com"), Name: mongorm.String("Bob")}
+		if _, err := orm(todo).Upsert(t.Context(), WriteToDoFields.Email); err != nil {
+			t.Fatal(err)
+		}
+		expectWriteHooks(t, "BeforeSave", "BeforeUpdate", "AfterUpdate", "AfterSave")
+	})
+
+	t.Run("Upsert with a generated key reloads the matched document", func(t *testing.T) {
+		upsert := func(name string) (*GeneratedWriteToDo, *mongorm.WriteResult) {
+			todo := &GeneratedWriteToDo{Email: mongorm.String("generated@example.com"), Name: mongorm.String(name)}
+			res, err := mongorm.FromOptions(todo, &mongorm.MongORMOptions{Backend: db}).Upsert(t.Context(), GeneratedWriteToDoFields.Email)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return todo, res
+		}
+
+		first, res := upsert("Ann")
+		if !res.Inserted || first.ID == nil {
+			t.Fatalf("expected an insert with a generated key, got %+v %+v", res, first)
+		}
+
+		second, res := upsert("Bob")
+		if res.Inserted || !res.Matched || mongorm.StringVal(second.ID) != mongorm.StringVal(first.ID) || mongorm.StringVal(second.Name) != "Bob" {
+			t.Fatalf("expected the stored document with its original key, got %+v %+v", res, second)
+		}
+	})
+
+	t.Run("Update reloads the document it changed", func(t *testing.T) {
+		updated := &WriteToDo{}
+		res, err := orm(updated).
+			Where(WriteToDoFields.Email.Eq("upsert@example.com")).
+			Set(&WriteToDo{Email: mongorm.String("moved@example.com")}).
+			Update(t.Context())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !res.Matched || !res.Modified || mongorm.StringVal(updated.Email) != "moved@example.com" || mongorm.StringVal(updated.Name) != "Bob" {
+			t.Fatalf("expected the moved document, got %+v %+v", res, updated)
+		}
+		writeHookCalls = nil
+	})
+
+	t.Run("Update reports unchanged documents", func(t *testing.T) {
+		same := &WriteToDo{}
+		res, err := orm(same).
+			Where(WriteToDoFields.Email.Eq("moved@example.com")).
+			Set(&WriteToDo{Name: mongorm.String("Bob")}).
+			Update(t.Context())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !res.Matched || res.Modified {
+			t.Fatalf("expected a match without modification despite updated_at, got %+v", res)
+		}
+
+		loaded := &WriteToDo{}
+		model := orm(loaded)
+		if err := model.Where(WriteToDoFields.Email.Eq("moved@example.com")).First(t.Context()); err != nil {
+			t.Fatal(err)
+		}
+		writeHookCalls = nil
+
+		res, err = model.Update(t.Context())
+		if err != nil {
+			t.Fatalf("expected an unchanged document to update like Save, got %v", err)
+		}
+		if !res.Matched || res.Modified || res.ID == nil {
+			t.Fatalf("expected an unmodified match, got %+v", res)
+		}
+		expectWriteHooks(t, "BeforeSave", "AfterSave")
+	})
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/azayn-labs/mongorm"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestBooleanCombinatorsNest(t *testing.T) {
+	expr := mongorm.And(
+		ToDoFields.Done.Eq(false),
+		mongorm.Or(
+			ToDoFields.Count.Gte(10),
+			mongorm.And(ToDoFields.Text.Eq("urgent"), ToDoFields.Count.Gte(1)),
+		),
+	)
+
+	expected := bson.M{"$and": bson.A{
+		bson.M{"done": false},
+		bson.M{"$or": bson.A{
+			bson.M{"count": bson.M{"$gte": int64(10)}},
+			bson.M{"$and": bson.A{
+				bson.M{"text": "urgent"},
+				bson.M{"count": bson.M{"$gte": int64(1)}},
+			}},
+		}},
+	}}
+
+	if !reflect.DeepEqual(expr, expected) {
+		t.Fatalf("unexpected nested expression: %#v", expr)
+	}
+}
+
+func TestBooleanCombinatorsFlattenAndSkipEmpty(t *testing.T) {
+	expr := mongorm.Or(
+		mongorm.Or(ToDoFields.Text.Eq("a"), ToDoFields.Text.Eq("b")),
+		nil,
+		bson.M{},
+		ToDoFields.Text.Eq("c"),
+	)
+
+	expected := bson.M{"$or": bson.A{
+		bson.M{"text": "a"},
+		bson.M{"text": "b"},
+		bson.M{"text": "c"},
+	}}
+	if !reflect.DeepEqual(expr, expected) {
+		t.Fatalf("unexpected flattened $or: %#v", expr)
+	}
+
+	if single := mongorm.And(ToDoFields.Text.Eq("a")); !reflect.DeepEqual(single, bson.M{"text": "a"}) {
+		t.Fatalf("expected single And clause to be returned as-is, got: %#v", single)
+	}
+
+	if empty := mongorm.And(); len(empty) != 0 {
+		t.Fatalf("expected empty And to produce empty filter, got: %#v", empty)
+	}
+
+	nor := mongorm.Nor(ToDoFields.Done.Eq(true))
+	if !reflect.DeepEqual(nor, bson.M{"$nor": bson.A{bson.M{"done": true}}}) {
+		t.Fatalf("unexpected $nor: %#v", nor)
+	}
+}
+
+func TestNotCombinator(t *testing.T) {
+	notGt := mongorm.Not(ToDoFields.Count.Gt(5))
+	if !reflect.DeepEqual(notGt, bson.M{"count": bson.M{"$not": bson.M{"$gt": int64(5)}}}) {
+		t.Fatalf("unexpected field-level $not: %#v", notGt)
+	}
+
+	if back := mongorm.Not(notGt); !reflect.DeepEqual(back, ToDoFields.Count.Gt(5)) {
+		t.Fatalf("expected double negation to unwrap, got: %#v", back)
+	}
+
+	notEq := mongorm.Not(ToDoFields.Text.Eq("a"))
+	if !reflect.DeepEqual(notEq, bson.M{"$nor": bson.A{bson.M{"text": "a"}}}) {
+		t.Fatalf("expected equality negation via $nor, got: %#v", notEq)
+	}
+
+	notOr := mongorm.Not(mongorm.Or(ToDoFields.Text.Eq("a"), ToDoFields.Text.Eq("b")))
+	if _, ok := notOr["$nor"]; !ok {
+		t.Fatalf("expected logical negation via $nor, got: %#v", notOr)
+	}
+}
+
+func TestWhereMergesRepeatedFieldsIntoAnd(t *testing.T) {
+	model := mongorm.New(&ToDo{})
+	model.
+		Where(ToDoFields.Count.Gte(1)).
+		Where(ToDoFields.Count.Lte(10)).
+		Where(mongorm.Or(ToDoFields.Text.Eq("a"), ToDoFields.Text.Eq("b"))).
+		Where(mongorm.Or(ToDoFields.Done.Eq(true), ToDoFields.Done.NotExists()))
+
+	expected := bson.M{
+		"count": bson.M{"$gte": int64(1)},
+		"$or":   bson.A{bson.M{"text": "a"}, bson.M{"text": "b"}},
+		"$and": bson.A{
+			bson.M{"count": bson.M{"$lte": int64(10)}},
+			bson.M{"$or": bson.A{bson.M{"done": true}, bson.M{"done": bson.M{"$exists": false}}}},
+		},
+	}
+
+	if !reflect.DeepEqual(model.GetRawQuery(), roundTripBSON(t, expected)) {
+		t.Fatalf("unexpected merged query: %#v", model.GetRawQuery())
+	}
+}
+
+func TestWhereMergesAndClauses(t *testing.T) {
+	model := mongorm.New(&ToDo{})
+	model.
+		WhereAnd(ToDoFields.Count.Gte(1)).
+		Where(mongorm.And(ToDoFields.Text.Eq("a"), ToDoFields.Done.Eq(true)))
+
+	expected := bson.M{"$and": bson.A{
+		bson.M{"count": bson.M{"$gte": int64(1)}},
+		bson.M{"text": "a"},
+		bson.M{"done": true},
+	}}
+
+	if !reflect.DeepEqual(model.GetRawQuery(), roundTripBSON(t, expected)) {
+		t.Fatalf("unexpected merged $and query: %#v", model.GetRawQuery())
+	}
+}
+
+// roundTripBSON encodes and decodes a document the same way GetRawQuery copies it, so
+// expectations can be compared with reflect.DeepEqual.
+func roundTripBSON(t *testing.T, doc bson.M) bson.M {
+	t.Helper()
+
+	raw, err := bson.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded := bson.M{}
+	if err := bson.Unmarshal(raw, &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	return decoded
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/azayn-labs/mongorm"
+	"github.com/azayn-labs/mongorm/memdb"
+	"github.com/azayn-labs/mongorm/primitives"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type RegistryToDo struct {
+	ID        *bson.ObjectID `bson:"_id,omitempty" mongorm:"primary"`
+	Text      *string        `bson:"text,omitempty"`
+	Tenant    *string        `bson:"tenant,omitempty"`
+	CreatedBy *string        `bson:"created_by,omitempty"`
+
+	collection *string `mongorm:"todo_hook_registry,connection:collection"`
+}
+
+type RegistryToDoSchema struct {
+	ID        *primitives.ObjectIDField
+	Text      *primitives.StringField
+	Tenant    *primitives.StringField
+	CreatedBy *primitives.StringField
+}
+
+var RegistryToDoFields = mongorm.FieldsOf[RegistryToDo, RegistryToDoSchema]()
+
+func (t *RegistryToDo) BeforeCreateWithContext(ctx context.Context, _ *mongorm.MongORM[RegistryToDo]) error {
+	return recordHook(ctx, "method")
+}
+
+type tenantKey struct{}
+
+// tenantPlugin stamps and scopes the documents of every model with a Tenant field.
+type tenantPlugin struct{}
+
+func (tenantPlugin) Name() string { return "tenant" }
+
+func (tenantPlugin) Hook(ctx context.Context, event mongorm.HookEvent, args *mongorm.HookArgs) error {
+	tenant, ok := ctx.Value(tenantKey{}).(string)
+	if !ok || args.Collection != "todo_hook_registry" {
+		return nil
+	}
+
+	switch event {
+	case mongorm.HookBeforeCreate:
+		if err := recordHook(ctx, "plugin"); err != nil {
+			return err
+		}
+		field := reflect.ValueOf(args.Document).Elem().FieldByName("Tenant")
+		if field.IsValid() {
+			field.Set(reflect.ValueOf(&tenant))
+		}
+	case mongorm.HookBeforeFind, mongorm.HookBeforeDeleteMany:
+		(*args.Filter)["tenant"] = tenant
+	case mongorm.HookBeforeDelete:
+		if tenant == "readonly" {
+			return errors.New("tenant is read-only")
+		}
+	}
+
+	return nil
+}
+
+func TestHookRegistry(t *testing.T) {
+	db := memdb.New("orm-test")
+	orm := func(doc *RegistryToDo) *mongorm.MongORM[RegistryToDo] {
+		return mongorm.FromOptions(doc, &mongorm.MongORMOptions{Backend: db})
+	}
+	withTenant := func(t *testing.T, tenant string) (context.Context, *[]string) {
+		calls := &[]string{}
+		ctx := context.WithValue(t.Context(), hookCallsKey{}, calls)
+		return context.WithValue(ctx, tenantKey{}, tenant), calls
+	}
+
+	mongorm.RegisterHook(mongorm.HookBeforeCreate, func(ctx context.Context, m *mongorm.MongORM[RegistryToDo], args *mongorm.HookArgs) error {
+		if args.Event != mongorm.HookBeforeCreate || args.Document != m.Document() {
+			return errors.New("unexpected hook arguments")
+		}
+		m.Document().CreatedBy = mongorm.String("registered")
+		return recordHook(ctx, "registered")
+	})
+	mongorm.RegisterPlugin(tenantPlugin{})
+	t.Cleanup(func() { mongorm.UnregisterPlugin("tenant") })
+
+	t.Run("Registered hooks and plugins run after the hook methods", func(t *testing.T) {
+		for _, tenant := range []string{"acme", "globex"} {
+			ctx, calls := withTenant(t, tenant)
+			todo := &RegistryToDo{Text: mongorm.String(tenant)}
+			if _, err := orm(todo).Create(ctx); err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(*calls, []string{"method", "registered", "plugin"}) {
+				t.Fatalf("expected the hooks in order, got %v", *calls)
+			}
+			if todo.Tenant == nil || *todo.Tenant != tenant || todo.CreatedBy == nil || *todo.CreatedBy != "registered" {
+				t.Fatalf("expected the hooks to stamp the document, got %+v", todo)
+			}
+		}
+	})
+
+	t.Run("Plugins can scope filters", func(t *testing.T) {
+		ctx, _ := withTenant(t, "acme")
+		todo := &RegistryToDo{}
+		if err := orm(todo).Where(RegistryToDoFields.Text.Eq("globex")).First(ctx); !errors.Is(err, mongorm.ErrNotFound) {
+			t.Fatalf("expected the tenant filter to hide other tenants, got %v %+v", err, todo)
+		}
+
+		res, err := orm(&RegistryToDo{}).Where(RegistryToDoFields.Text.Exists()).DeleteMulti(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.DeletedCount != 1 {
+			t.Fatalf("expected only the tenant's documents to be deleted, got %d", res.DeletedCount)
+		}
+	})
+
+	t.Run("Plugin errors abort the operation", func(t *testing.T) {
+		ctx, _ := withTenant(t, "readonly")
+		if _, err := orm(&RegistryToDo{}).Where(RegistryToDoFields.Text.Eq("globex")).DeleteMulti(ctx); err == nil || err.Error() != "tenant is read-only" {
+			t.Fatalf("expected the plugin error, got %v", err)
+		}
+	})
+}
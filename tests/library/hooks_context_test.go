@@ -0,0 +1,189 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/azayn-labs/mongorm"
+	"github.com/azayn-labs/mongorm/memdb"
+	"github.com/azayn-labs/mongorm/primitives"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type ContextHookToDo struct {
+	ID        *bson.ObjectID `bson:"_id,omitempty" mongorm:"primary"`
+	Text      *string        `bson:"text,omitempty"`
+	CreatedBy *string        `bson:"created_by,omitempty"`
+
+	collection *string `mongorm:"todo_context_hooks,connection:collection"`
+}
+
+type ContextHookToDoSchema struct {
+	ID        *primitives.ObjectIDField
+	Text      *primitives.StringField
+	CreatedBy *primitives.StringField
+}
+
+var ContextHookToDoFields = mongorm.FieldsOf[ContextHookToDo, ContextHookToDoSchema]()
+
+type hookCallsKey struct{}
+
+// recordHook appends name to the hook calls carried by ctx.
+func recordHook(ctx context.Context, name string) error {
+	calls, ok := ctx.Value(hookCallsKey{}).(*[]string)
+	if !ok {
+		return errors.New("hook called without the operation context")
+	}
+	*calls = append(*calls, name)
+	return ctx.Err()
+}
+
+func (t *ContextHookToDo) BeforeCreateWithContext(ctx context.Context, _ *mongorm.MongORM[ContextHookToDo]) error {
+	t.CreatedBy = mongorm.String("actor")
+	return recordHook(ctx, "BeforeCreateWithContext")
+}
+
+func (t *ContextHookToDo) BeforeCreate(*mongorm.MongORM[ContextHookToDo]) error {
+	return nil
+}
+
+func (t *ContextHookToDo) AfterCreateWithContext(ctx context.Context, _ *mongorm.MongORM[ContextHookToDo]) error {
+	return recordHook(ctx, "AfterCreateWithContext")
+}
+
+func (t *ContextHookToDo) BeforeSaveWithContext(ctx context.Context, _ *mongorm.MongORM[ContextHookToDo], _ *bson.M) error {
+	return recordHook(ctx, "BeforeSaveWithContext")
+}
+
+func (t *ContextHookToDo) AfterSaveWithContext(ctx context.Context, _ *mongorm.MongORM[ContextHookToDo]) error {
+	return recordHook(ctx, "AfterSaveWithContext")
+}
+
+func (t *ContextHookToDo) BeforeUpdateWithContext(ctx context.Context, _ *mongorm.MongORM[ContextHookToDo], _ *bson.M, _ *bson.M) error {
+	return recordHook(ctx, "BeforeUpdateWithContext")
+}
+
+func (t *ContextHookToDo) AfterUpdateWithContext(ctx context.Context, _ *mongorm.MongORM[ContextHookToDo]) error {
+	return recordHook(ctx, "AfterUpdateWithContext")
+}
+
+func (t *ContextHookToDo) BeforeFindWithContext(ctx context.Context, _ *mongorm.MongORM[ContextHookToDo], _ *bson.M) error {
+	return recordHook(ctx, "BeforeFindWithContext")
+}
+
+func (t *ContextHookToDo) AfterFindWithContext(ctx context.Context, _ *mongorm.MongORM[ContextHookToDo]) error {
+	return recordHook(ctx, "AfterFindWithContext")
+}
+
+func (t *ContextHookToDo) BeforeDeleteWithContext(ctx context.Context, _ *mongorm.MongORM[ContextHookToDo], _ *bson.M) error {
+	return recordHook(ctx, "BeforeDeleteWithContext")
+}
+
+func (t *ContextHookToDo) AfterDeleteWithContext(ctx context.Context, _ *mongorm.MongORM[ContextHookToDo]) error {
+	return recordHook(ctx, "AfterDeleteWithContext")
+}
+
+func (t *ContextHookToDo) BeforeFinalizeWithContext(ctx context.Context, _ *mongorm.MongORM[ContextHookToDo]) error {
+	return recordHook(ctx, "BeforeFinalizeWithContext")
+}
+
+func (t *ContextHookToDo) AfterFinalizeWithContext(ctx context.Context, _ *mongorm.MongORM[ContextHookToDo]) error {
+	return recordHook(ctx, "AfterFinalizeWithContext")
+}
+
+// AfterFinalize runs after its context-aware variant.
+func (t *ContextHookToDo) AfterFinalize(*mongorm.MongORM[ContextHookToDo]) error {
+	if t.Text != nil && *t.Text == "plain" {
+		return errors.New("plain AfterFinalize")
+	}
+	return nil
+}
+
+func TestContextHooks(t *testing.T) {
+	db := memdb.New("orm-test")
+	orm := func(doc *ContextHookToDo) *mongorm.MongORM[ContextHookToDo] {
+		return mongorm.FromOptions(doc, &mongorm.MongORMOptions{Backend: db})
+	}
+	withCalls := func(t *testing.T) (context.Context, *[]string) {
+		calls := &[]string{}
+		return context.WithValue(t.Context(), hookCallsKey{}, calls), calls
+	}
+
+	todo := &ContextHookToDo{Text: mongorm.String("context")}
+
+	t.Run("Create passes its context", func(t *testing.T) {
+		ctx, calls := withCalls(t)
+		if _, err := orm(todo).Create(ctx); err != nil {
+			t.Fatal(err)
+		}
+
+		expected := []string{
+			"BeforeSaveWithContext",
+			"BeforeCreateWithContext",
+			"BeforeFindWithContext",
+			"AfterFindWithContext",
+			"BeforeFinalizeWithContext",
+			"AfterFinalizeWithContext",
+			"AfterCreateWithContext",
+			"AfterSaveWithContext",
+		}
+		if !slices.Equal(*calls, expected) {
+			t.Fatalf("expected %v, got %v", expected, *calls)
+		}
+		if todo.CreatedBy == nil || *todo.CreatedBy != "actor" {
+			t.Fatalf("expected BeforeCreateWithContext to fill the document, got %v", todo.CreatedBy)
+		}
+	})
+
+	t.Run("Update, find and delete pass their context", func(t *testing.T) {
+		ctx, calls := withCalls(t)
+		if err := orm(todo).Set(&ContextHookToDo{Text: mongorm.String("updated")}).Save(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Contains(*calls, "BeforeSaveWithContext") ||
+			!slices.Contains(*calls, "BeforeUpdateWithContext") ||
+			!slices.Contains(*calls, "AfterUpdateWithContext") ||
+			!slices.Contains(*calls, "AfterSaveWithContext") {
+			t.Fatalf("expected the save hooks, got %v", *calls)
+		}
+
+		ctx, calls = withCalls(t)
+		if err := orm(&ContextHookToDo{}).Where(ContextHookToDoFields.ID.Eq(*todo.ID)).First(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if (*calls)[0] != "BeforeFindWithContext" || (*calls)[len(*calls)-1] != "AfterFindWithContext" {
+			t.Fatalf("expected the find hooks, got %v", *calls)
+		}
+
+		ctx, calls = withCalls(t)
+		if _, err := orm(&ContextHookToDo{}).Where(ContextHookToDoFields.ID.Eq(*todo.ID)).DeleteMulti(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Equal(*calls, []string{"BeforeDeleteWithContext", "AfterDeleteWithContext"}) {
+			t.Fatalf("expected the delete hooks, got %v", *calls)
+		}
+	})
+
+	t.Run("Hooks see cancellation", func(t *testing.T) {
+		ctx, _ := withCalls(t)
+		ctx, cancel := context.WithCancel(ctx)
+		cancel()
+
+		if _, err := orm(&ContextHookToDo{Text: mongorm.String("canceled")}).Create(ctx); !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected the hook to report the canceled context, got %v", err)
+		}
+	})
+
+	t.Run("Plain hooks run after context-aware ones", func(t *testing.T) {
+		ctx, calls := withCalls(t)
+		_, err := orm(&ContextHookToDo{Text: mongorm.String("plain")}).Create(ctx)
+		if err == nil || err.Error() != "plain AfterFinalize" {
+			t.Fatalf("expected the plain hook error, got %v", err)
+		}
+		if (*calls)[len(*calls)-1] != "AfterFinalizeWithContext" {
+			t.Fatalf("expected the context-aware hook to run first, got %v", *calls)
+		}
+	})
+}
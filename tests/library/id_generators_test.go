@@ -0,0 +1,148 @@
+package main
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/azayn-labs/mongorm"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type GeneratedUUIDToDo struct {
+	ID   *string `bson:"_id,omitempty" mongorm:"primary,id:uuidv7"`
+	Text *string `bson:"text,omitempty"`
+
+	connectionString *string `mongorm:"mongodb://localhost:27017,connection:url"`
+	database         *string `mongorm:"orm-test,connection:database"`
+	collection       *string `mongorm:"todo_library_string_keys,connection:collection"`
+}
+
+type GeneratedULIDToDo struct {
+	ID   *mongorm.ULID `bson:"_id,omitempty" mongorm:"primary"`
+	Text *string       `bson:"text,omitempty"`
+
+	connectionString *string `mongorm:"mongodb://localhost:27017,connection:url"`
+	database         *string `mongorm:"orm-test,connection:database"`
+	collection       *string `mongorm:"todo_library_string_keys,connection:collection"`
+}
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-([47])[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+func TestUUIDGeneratorsProduceVersionedUUIDs(t *testing.T) {
+	v4, err := mongorm.NewUUIDv4()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if match := uuidPattern.FindStringSubmatch(v4.String()); match == nil || match[1] != "4" {
+		t.Fatalf("expected version 4 UUID, got %s", v4)
+	}
+
+	v7, err := mongorm.NewUUIDv7()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if match := uuidPattern.FindStringSubmatch(v7.String()); match == nil || match[1] != "7" {
+		t.Fatalf("expected version 7 UUID, got %s", v7)
+	}
+
+	parsed, err := mongorm.ParseUUID(v7.String())
+	if err != nil || parsed != v7 {
+		t.Fatalf("expected UUID round trip, got %s (%v)", parsed, err)
+	}
+
+	time.Sleep(2 * time.Millisecond)
+	later, err := mongorm.NewUUIDv7()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if later.String() <= v7.String() {
+		t.Fatalf("expected UUIDv7 values to sort by time, got %s <= %s", later, v7)
+	}
+}
+
+func TestULIDGeneratorProducesSortableIDs(t *testing.T) {
+	first, err := mongorm.NewULID()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(first.String()) != 26 {
+		t.Fatalf("expected 26 character ULID, got %q", first.String())
+	}
+
+	parsed, err := mongorm.ParseULID(first.String())
+	if err != nil || parsed != first {
+		t.Fatalf("expected ULID round trip, got %s (%v)", parsed, err)
+	}
+
+	time.Sleep(2 * time.Millisecond)
+	second, err := mongorm.NewULID()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if second.String() <= first.String() {
+		t.Fatalf("expected ULIDs to sort by time, got %s <= %s", second, first)
+	}
+
+	if _, err := mongorm.ParseULID("not-a-ulid"); err == nil {
+		t.Fatal("expected invalid ULID error")
+	}
+}
+
+func ValidateLibraryIDGenerators(t *testing.T) {
+	t.Run("Tag generator fills string key before insert", func(t *testing.T) {
+		model := &GeneratedUUIDToDo{Text: mongorm.String("uuidv7-tag")}
+		if err := mongorm.New(model).Save(t.Context()); err != nil {
+			t.Fatal(err)
+		}
+		defer func() {
+			_ = mongorm.New(&GeneratedUUIDToDo{ID: model.ID}).Delete(t.Context())
+		}()
+
+		if !uuidPattern.MatchString(mongorm.StringVal(model.ID)) {
+			t.Fatalf("expected generated UUIDv7 string id, got %q", mongorm.StringVal(model.ID))
+		}
+
+		found := &GeneratedUUIDToDo{ID: model.ID}
+		if err := mongorm.New(found).First(t.Context()); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("Explicit key is kept", func(t *testing.T) {
+		model := &GeneratedUUIDToDo{ID: mongorm.String("explicit-" + bson.NewObjectID().Hex())}
+		explicit := *model.ID
+		if err := mongorm.New(model).Save(t.Context()); err != nil {
+			t.Fatal(err)
+		}
+		defer func() {
+			_ = mongorm.New(&GeneratedUUIDToDo{ID: model.ID}).Delete(t.Context())
+		}()
+
+		if mongorm.StringVal(model.ID) != explicit {
+			t.Fatalf("expected explicit id %q to be kept, got %q", explicit, mongorm.StringVal(model.ID))
+		}
+	})
+
+	t.Run("Options generator fills typed key", func(t *testing.T) {
+		model := &GeneratedULIDToDo{Text: mongorm.String("ulid-options")}
+		orm := mongorm.FromOptions(model, &mongorm.MongORMOptions{
+			IDGenerator: mongorm.ULIDGenerator(),
+		})
+		if err := orm.Save(t.Context()); err != nil {
+			t.Fatal(err)
+		}
+		defer func() {
+			_ = mongorm.New(&GeneratedULIDToDo{ID: model.ID}).Delete(t.Context())
+		}()
+
+		if model.ID == nil || *model.ID == (mongorm.ULID{}) {
+			t.Fatal("expected generated ULID id")
+		}
+	})
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/azayn-labs/mongorm"
+	"github.com/azayn-labs/mongorm/memdb"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+func TestIndexBuilder(t *testing.T) {
+	t.Run("Model options", func(t *testing.T) {
+		model := mongorm.NewIndexBuilder().
+			Asc(MemToDoFields.Text).
+			Desc(MemToDoFields.Count).
+			Name("text_count").
+			Unique().
+			Sparse().
+			Hidden().
+			ExpireAfterSeconds(60).
+			Collation(&options.Collation{Locale: "en", Strength: 2}).
+			Partial(MemToDoFields.Count.Gt(0), MemToDoFields.Text.Exists()).
+			Model()
+
+		keys, _ := model.Keys.(bson.D)
+		if len(keys) != 2 || keys[0] != (bson.E{Key: "text", Value: 1}) || keys[1] != (bson.E{Key: "count", Value: -1}) {
+			t.Fatalf("expected text ascending and count descending keys, got %v", model.Keys)
+		}
+
+		args := &options.IndexOptions{}
+		for _, setter := range model.Options.List() {
+			if err := setter(args); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if mongorm.StringVal(args.Name) != "text_count" ||
+			!mongorm.BoolVal(args.Unique) ||
+			!mongorm.BoolVal(args.Sparse) ||
+			!mongorm.BoolVal(args.Hidden) ||
+			args.ExpireAfterSeconds == nil || *args.ExpireAfterSeconds != 60 ||
+			args.Collation == nil || args.Collation.Locale != "en" {
+			t.Fatalf("expected every option to be set, got %+v", args)
+		}
+
+		partial, ok := args.PartialFilterExpression.(bson.M)
+		if !ok || len(partial) != 2 || partial["count"] == nil || partial["text"] == nil {
+			t.Fatalf("expected filters on different fields to be merged, got %v", args.PartialFilterExpression)
+		}
+
+		repeated := mongorm.NewIndexBuilder().
+			Asc(MemToDoFields.Count).
+			Partial(MemToDoFields.Count.Gt(0), MemToDoFields.Count.Lt(10)).
+			Model()
+		args = &options.IndexOptions{}
+		for _, setter := range repeated.Options.List() {
+			if err := setter(args); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if and, ok := args.PartialFilterExpression.(bson.M)["$and"].(bson.A); !ok || len(and) != 2 {
+			t.Fatalf("expected filters on the same field to be combined with $and, got %v", args.PartialFilterExpression)
+		}
+	})
+
+	t.Run("Text and wildcard indexes", func(t *testing.T) {
+		text := mongorm.NewIndexBuilder().
+			Text(MemToDoFields.Text).
+			Text(MemToDoFields.Meta.Source).
+			Weight(MemToDoFields.Text, 10).
+			DefaultLanguage("none").
+			Model()
+
+		args := &options.IndexOptions{}
+		for _, setter := range text.Options.List() {
+			if err := setter(args); err != nil {
+				t.Fatal(err)
+			}
+		}
+		weights, _ := args.Weights.(bson.D)
+		if len(weights) != 1 || weights[0] != (bson.E{Key: "text", Value: int32(10)}) || mongorm.StringVal(args.DefaultLanguage) != "none" {
+			t.Fatalf("expected text weight and language, got %+v", args)
+		}
+
+		wildcard := mongorm.NewIndexBuilder().
+			Wildcard(nil).
+			WildcardExclude(MemToDoFields.Tags).
+			Model()
+		keys, _ := wildcard.Keys.(bson.D)
+		if len(keys) != 1 || keys[0].Key != "$**" {
+			t.Fatalf("expected a whole-document wildcard key, got %v", wildcard.Keys)
+		}
+
+		nested := mongorm.NewIndexBuilder().Wildcard(mongorm.RawField("meta")).Model()
+		if keys, _ := nested.Keys.(bson.D); len(keys) != 1 || keys[0].Key != "meta.$**" {
+			t.Fatalf("expected a field wildcard key, got %v", nested.Keys)
+		}
+	})
+
+	t.Run("Built indexes round-trip through PlanIndexes", func(t *testing.T) {
+		db := memdb.New("orm-test")
+		orm := mongorm.FromOptions(&MemToDo{}, &mongorm.MongORMOptions{Backend: db})
+
+		models := []mongo.IndexModel{
+			mongorm.NewIndexBuilder().
+				Asc(MemToDoFields.Text).
+				Unique().
+				Partial(MemToDoFields.Count.Gt(0)).
+				Collation(&options.Collation{Locale: "en", Strength: 2}).
+				Model(),
+			mongorm.NewIndexBuilder().
+				Text(MemToDoFields.Text).
+				Weight(MemToDoFields.Text, 5).
+				DefaultLanguage("none").
+				Model(),
+			mongorm.NewIndexBuilder().Wildcard(nil).WildcardInclude(MemToDoFields.Meta.Source).Hidden().Model(),
+		}
+		if _, err := orm.EnsureIndexes(t.Context(), models); err != nil {
+			t.Fatal(err)
+		}
+
+		plan, err := orm.PlanIndexes(t.Context(), models)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !plan.Empty() {
+			t.Fatalf("expected the created indexes to match their models, got %+v", plan)
+		}
+
+		for range 2 {
+			if _, err := mongorm.FromOptions(&MemToDo{Text: mongorm.String("draft")}, &mongorm.MongORMOptions{Backend: db}).Create(t.Context()); err != nil {
+				t.Fatalf("expected documents outside the partial filter to be accepted, got %v", err)
+			}
+		}
+
+		if _, err := mongorm.FromOptions(&MemToDo{Text: mongorm.String("live"), Count: 1}, &mongorm.MongORMOptions{Backend: db}).Create(t.Context()); err != nil {
+			t.Fatal(err)
+		}
+		_, err = mongorm.FromOptions(&MemToDo{Text: mongorm.String("live"), Count: 2}, &mongorm.MongORMOptions{Backend: db}).Create(t.Context())
+		if !errors.Is(err, mongorm.ErrDuplicateKey) {
+			t.Fatalf("expected the partial unique index to be enforced, got %v", err)
+		}
+	})
+}
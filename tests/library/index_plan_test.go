@@ -0,0 +1,81 @@
+package main
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/azayn-labs/mongorm"
+	"github.com/azayn-labs/mongorm/memdb"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+func TestPlanIndexes(t *testing.T) {
+	db := memdb.New("orm-test")
+	orm := mongorm.FromOptions(&MemToDo{}, &mongorm.MongORMOptions{Backend: db})
+
+	if _, err := orm.EnsureIndexes(t.Context(), []mongo.IndexModel{
+		mongorm.IndexModelFromKeys(mongorm.Asc(MemToDoFields.Text)),
+		mongorm.IndexModelFromKeys(mongorm.Asc(MemToDoFields.Count)),
+		mongorm.NamedIndexModelFromKeys("tags_idx", mongorm.Asc(MemToDoFields.Tags)),
+		mongorm.IndexModelFromKeys(mongorm.Desc(mongorm.RawField("created_at"))),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	plan, err := orm.PlanIndexes(t.Context(), []mongo.IndexModel{
+		mongorm.IndexModelFromKeys(mongorm.Asc(MemToDoFields.Text)),
+		mongorm.UniqueIndexModelFromKeys(mongorm.Asc(MemToDoFields.Count)),
+		mongorm.NamedIndexModelFromKeys("tags_idx", mongorm.Desc(MemToDoFields.Tags)),
+		mongorm.IndexModelFromKeys(mongorm.Asc(mongorm.RawField("updated_at"))),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(plan.Create) != 1 {
+		t.Fatalf("expected 1 index to create, got %d", len(plan.Create))
+	}
+	if keys, _ := plan.Create[0].Keys.(bson.D); len(keys) != 1 || keys[0].Key != "updated_at" {
+		t.Fatalf("expected the updated_at index to be created, got %v", plan.Create[0].Keys)
+	}
+
+	if !slices.Equal(plan.Drop, []string{"created_at_-1"}) {
+		t.Fatalf("expected created_at_-1 to be dropped, got %v", plan.Drop)
+	}
+
+	if len(plan.Conflicts) != 2 {
+		t.Fatalf("expected 2 conflicts, got %+v", plan.Conflicts)
+	}
+	if conflict := plan.Conflicts[0]; conflict.Name != "count_1" ||
+		conflict.Existing != "count_1" ||
+		!slices.Equal(conflict.Differences, []string{"unique"}) {
+		t.Fatalf("expected count_1 to conflict on unique, got %+v", conflict)
+	}
+	if conflict := plan.Conflicts[1]; conflict.Name != "tags_idx" ||
+		conflict.Existing != "tags_idx" ||
+		!slices.Equal(conflict.Differences, []string{"keys"}) {
+		t.Fatalf("expected tags_idx to conflict on keys, got %+v", conflict)
+	}
+
+	specs, err := db.Collection("todo_memdb").Indexes().ListSpecifications(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(specs) != 5 {
+		t.Fatalf("expected planning to leave the 5 indexes untouched, got %d", len(specs))
+	}
+
+	plan, err = orm.PlanIndexes(t.Context(), []mongo.IndexModel{
+		mongorm.NamedIndexModelFromKeys("text_lookup", mongorm.Asc(MemToDoFields.Text)),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Conflicts) != 1 || !slices.Equal(plan.Conflicts[0].Differences, []string{"name"}) {
+		t.Fatalf("expected a renamed index to conflict on name, got %+v", plan.Conflicts)
+	}
+	if plan.Empty() {
+		t.Fatal("expected the plan to have changes")
+	}
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/azayn-labs/mongorm"
+	"github.com/azayn-labs/mongorm/memdb"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type IndexedTask struct {
+	ID        *bson.ObjectID `bson:"_id,omitempty" mongorm:"primary"`
+	Email     *string        `bson:"email,omitempty" mongorm:"unique"`
+	Status    *string        `bson:"status,omitempty" mongorm:"index:user_status,order:2,desc"`
+	UserID    *string        `bson:"user_id,omitempty" mongorm:"index:user_status,order:1"`
+	Title     *string        `bson:"title,omitempty" mongorm:"text"`
+	Body      *string        `bson:"body,omitempty" mongorm:"text"`
+	ExpiresAt *time.Time     `bson:"expires_at,omitempty" mongorm:"ttl:3600"`
+	Priority  int64          `bson:"priority" mongorm:"index"`
+
+	collection *string `mongorm:"tasks,connection:collection"`
+}
+
+type InvalidIndexedTask struct {
+	Status *string `bson:"status" mongorm:"order:1"`
+
+	collection *string `mongorm:"tasks,connection:collection"`
+}
+
+type DriftedTask struct {
+	ID     *bson.ObjectID `bson:"_id,omitempty" mongorm:"primary"`
+	Status *string        `bson:"status,omitempty" mongorm:"index"`
+	UserID *string        `bson:"user_id,omitempty" mongorm:"index:by_user,order:1"`
+	Title  *string        `bson:"title,omitempty" mongorm:"index:by_user,order:2"`
+
+	collection *string `mongorm:"drifted_tasks,connection:collection"`
+}
+
+func TestSyncIndexesRebuildsSharedDrift(t *testing.T) {
+	db := memdb.New("orm-test")
+	if _, err := db.Collection("drifted_tasks").Indexes().CreateOne(t.Context(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "status", Value: 1}},
+		Options: options.Index().SetName("by_user"),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := mongorm.FromOptions(&DriftedTask{}, &mongorm.MongORMOptions{Backend: db}).
+		SyncIndexes(t.Context(), mongorm.IndexSyncOptions{RebuildDrifted: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Drifted) != 2 || result.Drifted[0].Existing != "by_user" || result.Drifted[1].Existing != "by_user" {
+		t.Fatalf("expected both indexes to drift against by_user, got %+v", result.Drifted)
+	}
+	if !slices.Equal(result.Dropped, []string{"by_user"}) || !slices.Equal(result.Created, []string{"status_1", "by_user"}) {
+		t.Fatalf("expected by_user to be dropped once and both indexes created, got %+v", result)
+	}
+}
+
+func TestSyncIndexes(t *testing.T) {
+	db := memdb.New("orm-test")
+	orm := func() *mongorm.MongORM[IndexedTask] {
+		return mongorm.FromOptions(&IndexedTask{}, &mongorm.MongORMOptions{Backend: db})
+	}
+
+	t.Run("Index models from tags", func(t *testing.T) {
+		models, err := orm().IndexModels()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expected := []bson.D{
+			{{Key: "email", Value: 1}},
+			{{Key: "user_id", Value: 1}, {Key: "status", Value: -1}},
+			{{Key: "title", Value: "text"}, {Key: "body", Value: "text"}},
+			{{Key: "expires_at", Value: 1}},
+			{{Key: "priority", Value: 1}},
+		}
+		if len(models) != len(expected) {
+			t.Fatalf("expected %d index models, got %d", len(expected), len(models))
+		}
+		for i, model := range models {
+			keys, _ := model.Keys.(bson.D)
+			if len(keys) != len(expected[i]) {
+				t.Fatalf("expected keys %v, got %v", expected[i], keys)
+			}
+			for j := range keys {
+				if keys[j] != expected[i][j] {
+					t.Fatalf("expected keys %v, got %v", expected[i], keys)
+				}
+			}
+		}
+
+		_, err = mongorm.New(&InvalidIndexedTask{}).IndexModels()
+		if !errors.Is(err, mongorm.ErrInvalidConfig) {
+			t.Fatalf("expected an order without an index to be rejected, got %v", err)
+		}
+	})
+
+	t.Run("Create missing indexes", func(t *testing.T) {
+		result, err := orm().SyncIndexes(t.Context())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expected := []string{"email_1", "user_status", "title_text_body_text", "expires_at_1", "priority_1"}
+		if !slices.Equal(result.Created, expected) {
+			t.Fatalf("expected created indexes %v, got %v", expected, result.Created)
+		}
+
+		_, err = orm().Create(t.Context())
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = mongorm.FromOptions(&IndexedTask{Email: mongorm.String("a@example.com")}, &mongorm.MongORMOptions{Backend: db}).Create(t.Context())
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = mongorm.FromOptions(&IndexedTask{Email: mongorm.String("a@example.com")}, &mongorm.MongORMOptions{Backend: db}).Create(t.Context())
+		if !errors.Is(err, mongorm.ErrDuplicateKey) {
+			t.Fatalf("expected the unique tag to be enforced, got %v", err)
+		}
+	})
+
+	t.Run("Synced indexes are left alone", func(t *testing.T) {
+		result, err := orm().SyncIndexes(t.Context())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(result.Created) != 0 || len(result.Dropped) != 0 || len(result.Stale) != 0 || len(result.Drifted) != 0 {
+			t.Fatalf("expected no changes, got %+v", result)
+		}
+	})
+
+	t.Run("Report and fix stale and drifted indexes", func(t *testing.T) {
+		indexes := db.Collection("tasks").Indexes()
+		if err := indexes.DropOne(t.Context(), "expires_at_1"); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := indexes.CreateMany(t.Context(), []mongo.IndexModel{
+			{Keys: bson.D{{Key: "expires_at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(60)},
+			{Keys: bson.D{{Key: "legacy", Value: 1}}},
+		}); err != nil {
+			t.Fatal(err)
+		}
+
+		result, err := orm().SyncIndexes(t.Context())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Equal(result.Stale, []string{"legacy_1"}) || len(result.Dropped) != 0 {
+			t.Fatalf("expected legacy_1 to be reported as stale, got %+v", result)
+		}
+		if len(result.Drifted) != 1 ||
+			result.Drifted[0].Name != "expires_at_1" ||
+			!slices.Equal(result.Drifted[0].Differences, []string{"expireAfterSeconds"}) {
+			t.Fatalf("expected the ttl drift to be reported, got %+v", result.Drifted)
+		}
+
+		result, err = orm().SyncIndexes(t.Context(), mongorm.IndexSyncOptions{DropStale: true, RebuildDrifted: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Equal(result.Dropped, []string{"expires_at_1", "legacy_1"}) ||
+			!slices.Equal(result.Created, []string{"expires_at_1"}) {
+			t.Fatalf("expected the drifted index to be rebuilt and the stale one dropped, got %+v", result)
+		}
+
+		result, err = orm().SyncIndexes(t.Context())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(result.Stale) != 0 || len(result.Drifted) != 0 {
+			t.Fatalf("expected the collection to be in sync, got %+v", result)
+		}
+	})
+}
@@ -230,6 +230,34 @@ func TestMain(t *testing.T) {
 		ValidateLibraryBulkWrite(t)
 	})
 
+	t.Run("Custom primary keys", func(t *testing.T) {
+		ValidateLibraryCustomPrimaryKeys(t)
+	})
+
+	t.Run("ID generators", func(t *testing.T) {
+		ValidateLibraryIDGenerators(t)
+	})
+
+	t.Run("Sequences", func(t *testing.T) {
+		ValidateLibrarySequences(t)
+	})
+
+	t.Run("Soft delete", func(t *testing.T) {
+		ValidateLibrarySoftDelete(t)
+	})
+
+	t.Run("Snapshot save", func(t *testing.T) {
+		ValidateLibrarySnapshotSave(t)
+	})
+
+	t.Run("Create, Update and Upsert", func(t *testing.T) {
+		ValidateLibraryExplicitWrites(t)
+	})
+
+	t.Run("CreateMany", func(t *testing.T) {
+		ValidateLibraryCreateMany(t)
+	})
+
 	t.Run("Aggregate TODOs by text", func(t *testing.T) {
 		aggText := "aggregate-check-" + time.Now().Format(time.RFC3339Nano)
 		CreateLibraryTodo(t, &ToDo{Text: mongorm.String(aggText), Done: mongorm.Bool(false), Count: 1})
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/azayn-labs/mongorm"
+	"github.com/azayn-labs/mongorm/memdb"
+	"github.com/azayn-labs/mongorm/primitives"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type MemToDo struct {
+	ID        *bson.ObjectID `bson:"_id,omitempty" mongorm:"primary"`
+	Text      *string        `bson:"text,omitempty"`
+	Tags      []string       `bson:"tags,omitempty"`
+	Count     int64          `bson:"count"`
+	Meta      *ToDoMeta      `bson:"meta,omitempty"`
+	CreatedAt *time.Time     `bson:"created_at,omitempty" mongorm:"true,timestamp:created_at"`
+	UpdatedAt *time.Time     `bson:"updated_at,omitempty" mongorm:"true,timestamp:updated_at"`
+
+	collection *string `mongorm:"todo_memdb,connection:collection"`
+}
+
+type MemToDoSchema struct {
+	ID    *primitives.ObjectIDField
+	Text  *primitives.StringField
+	Tags  *primitives.StringArrayField
+	Count *primitives.Int64Field
+	Meta  *ToDoMetaSchema
+}
+
+var MemToDoFields = mongorm.FieldsOf[MemToDo, MemToDoSchema]()
+
+func TestMemDBBackend(t *testing.T) {
+	db := memdb.New("orm-test")
+	orm := func(doc *MemToDo) *mongorm.MongORM[MemToDo] {
+		return mongorm.FromOptions(doc, &mongorm.MongORMOptions{Backend: db})
+	}
+
+	docs := []*MemToDo{
+		{Text: mongorm.String("alpha"), Tags: []string{"a", "x"}, Count: 1, Meta: &ToDoMeta{Source: mongorm.String("api")}},
+		{Text: mongorm.String("beta"), Tags: []string{"b", "x", "y"}, Count: 2},
+		{Text: mongorm.String("gamma"), Tags: []string{"c"}, Count: 3},
+	}
+	if _, err := orm(&MemToDo{}).CreateMany(t.Context(), docs); err != nil {
+		t.Fatal(err)
+	}
+
+	findTexts := func(t *testing.T, m *mongorm.MongORM[MemToDo]) []string {
+		t.Helper()
+
+		cursor, err := m.FindAll(t.Context())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		results, err := cursor.All(t.Context())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		texts := []string{}
+		for _, result := range results {
+			texts = append(texts, mongorm.StringVal(result.Document().Text))
+		}
+		return texts
+	}
+
+	expectTexts := func(t *testing.T, got []string, expected ...string) {
+		t.Helper()
+
+		if len(got) != len(expected) {
+			t.Fatalf("expected %v, got %v", expected, got)
+		}
+		for i := range expected {
+			if got[i] != expected[i] {
+				t.Fatalf("expected %v, got %v", expected, got)
+			}
+		}
+	}
+
+	t.Run("Query operators", func(t *testing.T) {
+		expectTexts(t, findTexts(t, orm(&MemToDo{}).Where(MemToDoFields.Text.Reg("^(al|ga)"))), "alpha", "gamma")
+		expectTexts(t, findTexts(t, orm(&MemToDo{}).Where(MemToDoFields.Count.In([]int64{2, 3}))), "beta", "gamma")
+		expectTexts(t, findTexts(t, orm(&MemToDo{}).Where(MemToDoFields.Tags.Contains("x"))), "alpha", "beta")
+		expectTexts(t, findTexts(t, orm(&MemToDo{}).Where(MemToDoFields.Tags.ContainsAll([]string{"x", "y"}))), "beta")
+		expectTexts(t, findTexts(t, orm(&MemToDo{}).Where(MemToDoFields.Tags.Size(1))), "gamma")
+		expectTexts(t, findTexts(t, orm(&MemToDo{}).Where(MemToDoFields.Tags.ElemMatch(bson.M{"$gte": "y"}))), "beta")
+		expectTexts(t, findTexts(t, orm(&MemToDo{}).Where(MemToDoFields.Meta.Source.Exists())), "alpha")
+		expectTexts(t, findTexts(t, orm(&MemToDo{}).OrWhere(MemToDoFields.Count.Gt(2)).OrWhere(MemToDoFields.Text.Eq("alpha"))), "alpha", "gamma")
+	})
+
+	t.Run("Sort, skip, limit and projection", func(t *testing.T) {
+		expectTexts(t, findTexts(t, orm(&MemToDo{}).SortDesc(MemToDoFields.Count).Skip(1).Limit(1)), "beta")
+
+		projected := &MemToDo{}
+		if err := orm(projected).
+			Where(MemToDoFields.Text.Eq("alpha")).
+			ProjectionInclude(MemToDoFields.Text).
+			First(t.Context()); err != nil {
+			t.Fatal(err)
+		}
+		if projected.ID == nil || mongorm.StringVal(projected.Text) != "alpha" || projected.Tags != nil || projected.Meta != nil {
+			t.Fatalf("expected only _id and text to be loaded, got %+v", projected)
+		}
+	})
+
+	t.Run("Update operators", func(t *testing.T) {
+		updated := &MemToDo{}
+		if err := orm(updated).
+			Where(MemToDoFields.Text.Eq("beta")).
+			IncData(MemToDoFields.Count, int64(5)).
+			PushData(MemToDoFields.Tags, "z").
+			AddToSetData(MemToDoFields.Tags, "x").
+			FindOneAndUpdate(t.Context()); err != nil {
+			t.Fatal(err)
+		}
+		if updated.Count != 7 || len(updated.Tags) != 4 || updated.Tags[3] != "z" {
+			t.Fatalf("expected $inc, $push and $addToSet to apply, got %+v", updated)
+		}
+
+		if err := orm(updated).
+			Where(MemToDoFields.Text.Eq("beta")).
+			PullData(MemToDoFields.Tags, "x").
+			PopFirstData(MemToDoFields.Tags).
+			FindOneAndUpdate(t.Context()); err != nil {
+			t.Fatal(err)
+		}
+		if len(updated.Tags) != 2 || updated.Tags[0] != "y" {
+			t.Fatalf("expected $pull and $pop to apply, got %v", updated.Tags)
+		}
+
+		if err := orm(updated).
+			Where(MemToDoFields.Text.Eq("beta")).
+			UnsetData(MemToDoFields.Tags).
+			FindOneAndUpdate(t.Context()); err != nil {
+			t.Fatal(err)
+		}
+		if updated.Tags != nil || updated.UpdatedAt == nil {
+			t.Fatalf("expected tags to be unset and updated_at to be set, got %+v", updated)
+		}
+	})
+
+	t.Run("Load, modify and save", func(t *testing.T) {
+		loaded := &MemToDo{}
+		m := orm(loaded)
+		if err := m.Where(MemToDoFields.Text.Eq("gamma")).First(t.Context()); err != nil {
+			t.Fatal(err)
+		}
+
+		loaded.Count = 30
+		if err := m.Save(t.Context()); err != nil {
+			t.Fatal(err)
+		}
+
+		total, err := orm(&MemToDo{}).Where(MemToDoFields.Count.Eq(30)).Count(t.Context())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if total != 1 {
+			t.Fatalf("expected the saved count to be stored, got %d matches", total)
+		}
+	})
+
+	t.Run("Distinct, duplicates and delete", func(t *testing.T) {
+		texts, err := orm(&MemToDo{}).DistinctStrings(t.Context(), MemToDoFields.Text)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(texts) != 3 {
+			t.Fatalf("expected 3 distinct texts, got %v", texts)
+		}
+
+		_, err = orm(&MemToDo{ID: docs[0].ID, Text: mongorm.String("copy")}).Create(t.Context())
+		if !errors.Is(err, mongorm.ErrDuplicateKey) {
+			t.Fatalf("expected duplicate key error, got %v", err)
+		}
+
+		if err := orm(&MemToDo{ID: docs[0].ID}).Delete(t.Context()); err != nil {
+			t.Fatal(err)
+		}
+
+		total, err := orm(&MemToDo{}).Count(t.Context())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if total != 2 {
+			t.Fatalf("expected 2 documents after delete, got %d", total)
+		}
+	})
+
+	t.Run("Unsupported operators fail", func(t *testing.T) {
+		err := orm(&MemToDo{}).Where(bson.M{"text": bson.M{"$where": "true"}}).First(t.Context())
+		if !errors.Is(err, memdb.ErrUnsupported) {
+			t.Fatalf("expected ErrUnsupported, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/azayn-labs/mongorm"
+	"github.com/azayn-labs/mongorm/memdb"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+func TestMiddleware(t *testing.T) {
+	db := memdb.New("orm-test")
+	orm := func(doc *MemToDo, middleware ...mongorm.Middleware) *mongorm.MongORM[MemToDo] {
+		return mongorm.FromOptions(doc, &mongorm.MongORMOptions{Backend: db, Middleware: middleware})
+	}
+
+	for _, text := range []string{"alpha", "beta"} {
+		if _, err := orm(&MemToDo{Text: mongorm.String(text), Count: 1}).Create(t.Context()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("Operations are described", func(t *testing.T) {
+		var ops []mongorm.Operation
+		record := func(next mongorm.OperationFunc) mongorm.OperationFunc {
+			return func(ctx context.Context, op *mongorm.Operation) (any, error) {
+				ops = append(ops, *op)
+				return next(ctx, op)
+			}
+		}
+
+		if _, err := orm(&MemToDo{}, record).Where(MemToDoFields.Text.Eq("alpha")).Set(&MemToDo{Count: 2}).SaveMulti(t.Context()); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := orm(&MemToDo{}, record).Count(t.Context()); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := orm(&MemToDo{}, record).Distinct(t.Context(), MemToDoFields.Text); err != nil {
+			t.Fatal(err)
+		}
+
+		kinds := []mongorm.OperationKind{}
+		for _, op := range ops {
+			kinds = append(kinds, op.Kind)
+		}
+		if !slices.Equal(kinds, []mongorm.OperationKind{mongorm.OperationUpdateMany, mongorm.OperationCountDocuments, mongorm.OperationDistinct}) {
+			t.Fatalf("expected every call to pass through the middleware, got %v", kinds)
+		}
+
+		update := ops[0]
+		if update.Collection != "todo_memdb" || update.Filter.(bson.M)["text"] != "alpha" || update.Update.(bson.M)["$set"] == nil {
+			t.Fatalf("expected the update call to be described, got %+v", update)
+		}
+		if _, ok := update.Options.([]options.Lister[options.UpdateManyOptions]); !ok {
+			t.Fatalf("expected typed options, got %T", update.Options)
+		}
+		if ops[2].Field != "text" {
+			t.Fatalf("expected the distinct field, got %q", ops[2].Field)
+		}
+	})
+
+	t.Run("Middleware can rewrite operations", func(t *testing.T) {
+		onlyAlpha := func(next mongorm.OperationFunc) mongorm.OperationFunc {
+			return func(ctx context.Context, op *mongorm.Operation) (any, error) {
+				if op.Filter != nil {
+					op.Filter = bson.M{"$and": bson.A{op.Filter, bson.M{"text": "alpha"}}}
+				}
+				return next(ctx, op)
+			}
+		}
+
+		count, err := orm(&MemToDo{}, onlyAlpha).Count(t.Context())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if count != 1 {
+			t.Fatalf("expected the rewritten filter to match one document, got %d", count)
+		}
+	})
+
+	t.Run("Middleware runs outermost first and can short-circuit", func(t *testing.T) {
+		errOpen := errors.New("circuit open")
+		var order []string
+		named := func(name string) mongorm.Middleware {
+			return func(next mongorm.OperationFunc) mongorm.OperationFunc {
+				return func(ctx context.Context, op *mongorm.Operation) (any, error) {
+					order = append(order, name)
+					return next(ctx, op)
+				}
+			}
+		}
+		breaker := func(mongorm.OperationFunc) mongorm.OperationFunc {
+			return func(context.Context, *mongorm.Operation) (any, error) {
+				return nil, errOpen
+			}
+		}
+
+		err := orm(&MemToDo{}, named("outer"), named("inner"), breaker).Where(MemToDoFields.Text.Eq("alpha")).First(t.Context())
+		if !errors.Is(err, errOpen) {
+			t.Fatalf("expected the middleware error, got %v", err)
+		}
+		if !slices.Equal(order, []string{"outer", "inner"}) {
+			t.Fatalf("expected middleware to run in order, got %v", order)
+		}
+
+		if _, err := orm(&MemToDo{}, breaker).Distinct(t.Context(), MemToDoFields.Text); !errors.Is(err, errOpen) {
+			t.Fatalf("expected the middleware error from Distinct, got %v", err)
+		}
+	})
+
+	t.Run("Index and sequence calls pass through", func(t *testing.T) {
+		var ops []mongorm.Operation
+		record := func(next mongorm.OperationFunc) mongorm.OperationFunc {
+			return func(ctx context.Context, op *mongorm.Operation) (any, error) {
+				ops = append(ops, *op)
+				return next(ctx, op)
+			}
+		}
+
+		name, err := orm(&MemToDo{}, record).EnsureIndex(t.Context(), mongo.IndexModel{Keys: bson.D{{Key: "count", Value: 1}}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := orm(&MemToDo{}, record).PlanIndexes(t.Context(), nil); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := orm(&MemToDo{}, record).NextSequence(t.Context(), "todos"); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(ops) != 3 ||
+			ops[0].Kind != mongorm.OperationCreateIndexes || len(ops[0].IndexModels) != 1 ||
+			ops[1].Kind != mongorm.OperationListIndexes ||
+			ops[2].Kind != mongorm.OperationFindOneAndUpdate || ops[2].Collection != mongorm.DefaultCountersCollection {
+			t.Fatalf("expected the index and counter calls, got %+v", ops)
+		}
+		if name != "count_1" {
+			t.Fatalf("expected the index name, got %q", name)
+		}
+	})
+
+	t.Run("Mismatched options are rejected", func(t *testing.T) {
+		badOptions := func(next mongorm.OperationFunc) mongorm.OperationFunc {
+			return func(ctx context.Context, op *mongorm.Operation) (any, error) {
+				op.Options = []options.Lister[options.FindOptions]{}
+				return next(ctx, op)
+			}
+		}
+
+		if _, err := orm(&MemToDo{}, badOptions).Count(t.Context()); !errors.Is(err, mongorm.ErrInvalidConfig) {
+			t.Fatalf("expected ErrInvalidConfig, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/azayn-labs/mongorm"
+	"github.com/azayn-labs/mongorm/memdb"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func addMigrationStatus(ctx context.Context, db mongorm.Backend) error {
+	_, err := db.Collection("todo_migrations").UpdateMany(
+		ctx,
+		bson.M{"status": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"status": "open"}},
+	)
+	return err
+}
+
+func removeMigrationStatus(ctx context.Context, db mongorm.Backend) error {
+	_, err := db.Collection("todo_migrations").UpdateMany(ctx, bson.M{}, bson.M{"$unset": bson.M{"status": ""}})
+	return err
+}
+
+func seedMigrationTodos(ctx context.Context, db mongorm.Backend) error {
+	_, err := db.Collection("todo_migrations").InsertMany(ctx, []any{
+		bson.M{"text": "first"},
+		bson.M{"text": "second"},
+	})
+	return err
+}
+
+func unseedMigrationTodos(ctx context.Context, db mongorm.Backend) error {
+	_, err := db.Collection("todo_migrations").DeleteMany(ctx, bson.M{})
+	return err
+}
+
+func TestMigrations(t *testing.T) {
+	db := memdb.New("orm-test")
+	migrations := []mongorm.Migration{
+		{ID: "002_add_status", Up: addMigrationStatus, Down: removeMigrationStatus},
+		{ID: "001_seed", Checksum: "v1", Up: seedMigrationTodos, Down: unseedMigrationTodos},
+	}
+	migrator := mongorm.NewMigrator(db, &mongorm.MigratorOptions{Migrations: migrations})
+
+	countWithStatus := func(t *testing.T) int64 {
+		t.Helper()
+
+		count, err := db.Collection("todo_migrations").CountDocuments(t.Context(), bson.M{"status": "open"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return count
+	}
+
+	t.Run("Up applies pending migrations in order", func(t *testing.T) {
+		applied, err := migrator.Up(t.Context())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Equal(applied, []string{"001_seed", "002_add_status"}) {
+			t.Fatalf("expected both migrations in id order, got %v", applied)
+		}
+		if count := countWithStatus(t); count != 2 {
+			t.Fatalf("expected 2 migrated documents, got %d", count)
+		}
+
+		var record bson.M
+		if err := db.Collection(mongorm.DefaultMigrationsCollection).FindOne(t.Context(), bson.M{"_id": "001_seed"}).Decode(&record); err != nil {
+			t.Fatal(err)
+		}
+		if record["checksum"] != "v1" {
+			t.Fatalf("expected the checksum to be recorded, got %v", record)
+		}
+
+		applied, err = migrator.Up(t.Context())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(applied) != 0 {
+			t.Fatalf("expected no pending migrations, got %v", applied)
+		}
+	})
+
+	t.Run("Status reports applied migrations", func(t *testing.T) {
+		statuses, err := mongorm.NewMigrator(db, &mongorm.MigratorOptions{Migrations: migrations[:1]}).Status(t.Context())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(statuses) != 2 || !statuses[0].Missing || statuses[1].Missing || !statuses[1].Applied || statuses[1].AppliedAt == nil {
+			t.Fatalf("expected 001_seed to be missing and 002_add_status applied, got %+v", statuses)
+		}
+	})
+
+	t.Run("Down reverts the newest migrations", func(t *testing.T) {
+		reverted, err := migrator.Down(t.Context(), 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Equal(reverted, []string{"002_add_status"}) {
+			t.Fatalf("expected 002_add_status to be reverted, got %v", reverted)
+		}
+		if count := countWithStatus(t); count != 0 {
+			t.Fatalf("expected the status field to be removed, got %d documents", count)
+		}
+
+		statuses, err := migrator.Status(t.Context())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !statuses[0].Applied || statuses[1].Applied {
+			t.Fatalf("expected only 001_seed to stay applied, got %+v", statuses)
+		}
+
+		if _, err := migrator.Up(t.Context()); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("Changed migrations are rejected", func(t *testing.T) {
+		changed := mongorm.NewMigrator(db, &mongorm.MigratorOptions{Migrations: []mongorm.Migration{
+			{ID: "001_seed", Checksum: "v2", Up: seedMigrationTodos},
+		}})
+
+		_, err := changed.Up(t.Context())
+		if !errors.Is(err, mongorm.ErrMigrationChanged) {
+			t.Fatalf("expected ErrMigrationChanged, got %v", err)
+		}
+
+		unversioned := mongorm.NewMigrator(db, &mongorm.MigratorOptions{Migrations: []mongorm.Migration{
+			{ID: "001_seed", Up: func(context.Context, mongorm.Backend) error { return nil }},
+		}})
+		statuses, err := unversioned.Status(t.Context())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if statuses[0].Changed {
+			t.Fatalf("expected a migration without a checksum not to be compared, got %+v", statuses[0])
+		}
+	})
+
+	t.Run("Lock blocks concurrent migrators until it expires", func(t *testing.T) {
+		locks := db.Collection(mongorm.DefaultMigrationsCollection)
+		if _, err := locks.InsertOne(t.Context(), bson.M{
+			"_id":        "_lock",
+			"owner":      "other-instance",
+			"expires_at": time.Now().Add(time.Minute),
+		}); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err := migrator.Up(t.Context())
+		if !errors.Is(err, mongorm.ErrMigrationLocked) {
+			t.Fatalf("expected ErrMigrationLocked, got %v", err)
+		}
+
+		if _, err := locks.UpdateOne(t.Context(), bson.M{"_id": "_lock"}, bson.M{"$set": bson.M{"expires_at": time.Now().Add(-time.Minute)}}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := migrator.Up(t.Context()); err != nil {
+			t.Fatalf("expected an expired lock to be taken over, got %v", err)
+		}
+
+		count, err := locks.CountDocuments(t.Context(), bson.M{"_id": "_lock"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if count != 0 {
+			t.Fatal("expected the lock to be released after migrating")
+		}
+	})
+
+	t.Run("Registered migrations", func(t *testing.T) {
+		registryDB := memdb.New("orm-test")
+		mongorm.RegisterMigration("001_registered_seed", "v1", seedMigrationTodos, nil)
+
+		applied, err := mongorm.NewMigrator(registryDB, nil).Up(t.Context())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Contains(applied, "001_registered_seed") {
+			t.Fatalf("expected the registered migration to be applied, got %v", applied)
+		}
+
+		_, err = mongorm.NewMigrator(registryDB, nil).Down(t.Context(), 1)
+		if !errors.Is(err, mongorm.ErrInvalidConfig) {
+			t.Fatalf("expected a migration without Down to be irreversible, got %v", err)
+		}
+
+		mongorm.RegisterMigration("001_registered_seed", "v2", seedMigrationTodos, nil)
+		_, err = mongorm.NewMigrator(registryDB, nil).Up(t.Context())
+		if !errors.Is(err, mongorm.ErrMigrationChanged) {
+			t.Fatalf("expected a registered migration with a new checksum to be rejected, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,227 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/azayn-labs/mongorm"
+	"github.com/azayn-labs/mongorm/memdb"
+	"github.com/azayn-labs/mongorm/primitives"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+type MultiHookToDo struct {
+	ID        *bson.ObjectID `bson:"_id,omitempty" mongorm:"primary"`
+	Code      *string        `bson:"code,omitempty" mongorm:"readonly"`
+	Text      *string        `bson:"text,omitempty"`
+	Archived  bool           `bson:"archived"`
+	Version   int64          `bson:"_version,omitempty" mongorm:"version"`
+	CreatedAt *time.Time     `bson:"created_at,omitempty" mongorm:"true,timestamp:created_at"`
+	UpdatedAt *time.Time     `bson:"updated_at,omitempty" mongorm:"true,timestamp:updated_at"`
+
+	collection *string `mongorm:"todo_multi_hooks,connection:collection"`
+}
+
+type MultiHookToDoSchema struct {
+	ID       *primitives.ObjectIDField
+	Code     *primitives.StringField
+	Text     *primitives.StringField
+	Archived *primitives.BoolField
+}
+
+var MultiHookToDoFields = mongorm.FieldsOf[MultiHookToDo, MultiHookToDoSchema]()
+
+// BeforeUpdateMany keeps archived todos out of every multi-document update.
+func (t *MultiHookToDo) BeforeUpdateMany(_ *mongorm.MongORM[MultiHookToDo], filter *bson.M, _ *bson.M) error {
+	(*filter)["archived"] = false
+	return nil
+}
+
+func (t *MultiHookToDo) BeforeUpdateManyWithContext(ctx context.Context, _ *mongorm.MongORM[MultiHookToDo], _ *bson.M, update *bson.M) error {
+	if ctx.Value(injectUpdateKey{}) != nil {
+		(*update)["$set"].(bson.M)["code"] = "injected"
+	}
+	return recordHook(ctx, "BeforeUpdateMany")
+}
+
+func (t *MultiHookToDo) AfterUpdateManyWithContext(ctx context.Context, _ *mongorm.MongORM[MultiHookToDo], filter *bson.M, _ *bson.M, result *mongo.UpdateResult) error {
+	return recordHook(ctx, fmt.Sprintf("AfterUpdateMany %v %d", (*filter)["archived"], result.ModifiedCount))
+}
+
+func (t *MultiHookToDo) BeforeDeleteManyWithContext(ctx context.Context, _ *mongorm.MongORM[MultiHookToDo], filter *bson.M) error {
+	if len(*filter) == 0 {
+		return errors.New("refusing to delete every todo")
+	}
+	return recordHook(ctx, "BeforeDeleteMany")
+}
+
+func (t *MultiHookToDo) AfterDeleteManyWithContext(ctx context.Context, _ *mongorm.MongORM[MultiHookToDo], _ *bson.M, result *mongo.DeleteResult) error {
+	return recordHook(ctx, fmt.Sprintf("AfterDeleteMany %d", result.DeletedCount))
+}
+
+type injectUpdateKey struct{}
+
+func TestMultiDocumentHooks(t *testing.T) {
+	db := memdb.New("orm-test")
+	orm := func() *mongorm.MongORM[MultiHookToDo] {
+		return mongorm.FromOptions(&MultiHookToDo{}, &mongorm.MongORMOptions{Backend: db})
+	}
+	withCalls := func(t *testing.T) (context.Context, *[]string) {
+		calls := &[]string{}
+		return context.WithValue(t.Context(), hookCallsKey{}, calls), calls
+	}
+
+	past := time.Now().Add(-time.Hour).Truncate(time.Millisecond)
+	for i, archived := range []bool{false, false, true} {
+		todo := &MultiHookToDo{
+			Code:      mongorm.String(fmt.Sprintf("code-%d", i)),
+			Text:      mongorm.String("multi"),
+			Archived:  archived,
+			Version:   1,
+			CreatedAt: &past,
+			UpdatedAt: &past,
+		}
+		if _, err := db.Collection("todo_multi_hooks").InsertOne(t.Context(), todo); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("SaveMulti runs the hooks and the update rules", func(t *testing.T) {
+		ctx, calls := withCalls(t)
+		res, err := orm().Where(MultiHookToDoFields.Text.Eq("multi")).Set(&MultiHookToDo{Text: mongorm.String("updated")}).SaveMulti(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.ModifiedCount != 2 {
+			t.Fatalf("expected BeforeUpdateMany to skip the archived todo, got %d updates", res.ModifiedCount)
+		}
+		if !slices.Equal(*calls, []string{"BeforeUpdateMany", "AfterUpdateMany false 2"}) {
+			t.Fatalf("expected the update hooks, got %v", *calls)
+		}
+
+		cursor, err := orm().Where(MultiHookToDoFields.Text.Eq("updated")).FindAll(t.Context())
+		if err != nil {
+			t.Fatal(err)
+		}
+		items, err := cursor.All(t.Context())
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, item := range items {
+			doc := item.Document()
+			if doc.Version != 2 {
+				t.Fatalf("expected the version to be incremented, got %d", doc.Version)
+			}
+			if !doc.CreatedAt.Equal(past) || !doc.UpdatedAt.After(past) {
+				t.Fatalf("expected only updated_at to change, got %v %v", doc.CreatedAt, doc.UpdatedAt)
+			}
+		}
+	})
+
+	t.Run("SaveMulti rejects protected fields added by hooks", func(t *testing.T) {
+		ctx, _ := withCalls(t)
+		ctx = context.WithValue(ctx, injectUpdateKey{}, true)
+		_, err := orm().Where(MultiHookToDoFields.Text.Eq("updated")).Set(&MultiHookToDo{Text: mongorm.String("again")}).SaveMulti(ctx)
+		if !errors.Is(err, mongorm.ErrInvalidConfig) {
+			t.Fatalf("expected the readonly field to be rejected, got %v", err)
+		}
+	})
+
+	t.Run("BulkWrite applies the update rules to a copy", func(t *testing.T) {
+		ctx, _ := withCalls(t)
+		update := bson.M{"$set": bson.M{"text": "bulk"}}
+		models := mongorm.NewBulkWriteBuilder[MultiHookToDo]().
+			UpdateMany(bson.M{"text": "updated"}, update, false).
+			Models()
+
+		if _, err := orm().BulkWrite(ctx, models); err != nil {
+			t.Fatal(err)
+		}
+		if len(update["$set"].(bson.M)) != 1 || update["$inc"] != nil {
+			t.Fatalf("expected the caller's update to stay untouched, got %v", update)
+		}
+
+		todo := &MultiHookToDo{}
+		if err := mongorm.FromOptions(todo, &mongorm.MongORMOptions{Backend: db}).Where(MultiHookToDoFields.Text.Eq("bulk")).First(t.Context()); err != nil {
+			t.Fatal(err)
+		}
+		if todo.Version != 3 || !todo.UpdatedAt.After(past) {
+			t.Fatalf("expected the version and updated_at to be set, got %d %v", todo.Version, todo.UpdatedAt)
+		}
+
+		for _, protected := range []bson.M{
+			{"$set": bson.M{"_id": bson.NewObjectID()}},
+			{"$set": bson.M{"code": "changed"}},
+			{"$unset": bson.M{"created_at": ""}},
+		} {
+			models := mongorm.NewBulkWriteBuilder[MultiHookToDo]().UpdateOne(bson.M{"text": "bulk"}, protected, false).Models()
+			if _, err := orm().BulkWrite(ctx, models); !errors.Is(err, mongorm.ErrInvalidConfig) {
+				t.Fatalf("expected %v to be rejected, got %v", protected, err)
+			}
+		}
+
+		models = mongorm.NewBulkWriteBuilder[MultiHookToDo]().
+			UpdateOne(bson.M{"text": "upserted"}, bson.M{"$setOnInsert": bson.M{"code": "new"}}, true).
+			Models()
+		if _, err := orm().BulkWrite(ctx, models); err != nil {
+			t.Fatalf("expected $setOnInsert to accept readonly fields, got %v", err)
+		}
+	})
+
+	t.Run("BulkWrite runs the hooks and handles bson.D updates", func(t *testing.T) {
+		ctx, calls := withCalls(t)
+		models := []mongo.WriteModel{
+			mongo.NewUpdateManyModel().
+				SetFilter(bson.D{{Key: "text", Value: "bulk"}}).
+				SetUpdate(bson.D{{Key: "$set", Value: bson.D{{Key: "text", Value: "bulk"}}}}),
+			mongo.NewDeleteManyModel().SetFilter(bson.M{"text": "missing"}),
+		}
+		if _, err := orm().BulkWrite(ctx, models); err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Equal(*calls, []string{"BeforeUpdateMany", "BeforeDeleteMany", "AfterUpdateMany false 2", "AfterDeleteMany 0"}) {
+			t.Fatalf("expected the multi-document hooks, got %v", *calls)
+		}
+
+		todo := &MultiHookToDo{}
+		if err := mongorm.FromOptions(todo, &mongorm.MongORMOptions{Backend: db}).Where(MultiHookToDoFields.Text.Eq("bulk")).First(t.Context()); err != nil {
+			t.Fatal(err)
+		}
+		if todo.Version != 4 {
+			t.Fatalf("expected the bson.D update to increment the version, got %d", todo.Version)
+		}
+
+		for _, model := range []mongo.WriteModel{
+			mongo.NewUpdateOneModel().SetFilter(bson.M{"text": "bulk"}).SetUpdate(bson.D{{Key: "$set", Value: bson.D{{Key: "code", Value: "changed"}}}}),
+			mongo.NewUpdateOneModel().SetFilter(bson.M{"text": "bulk"}).SetUpdate(mongo.Pipeline{{{Key: "$set", Value: bson.M{"code": "changed"}}}}),
+		} {
+			if _, err := orm().BulkWrite(ctx, []mongo.WriteModel{model}); !errors.Is(err, mongorm.ErrInvalidConfig) {
+				t.Fatalf("expected %T update to be rejected, got %v", model, err)
+			}
+		}
+
+		if _, err := orm().BulkWrite(ctx, []mongo.WriteModel{mongo.NewDeleteManyModel().SetFilter(bson.M{})}); err == nil || err.Error() != "refusing to delete every todo" {
+			t.Fatalf("expected BeforeDeleteMany to stop the bulk delete, got %v", err)
+		}
+	})
+
+	t.Run("DeleteMulti runs the hooks", func(t *testing.T) {
+		ctx, calls := withCalls(t)
+		if _, err := orm().DeleteMulti(ctx); err == nil || err.Error() != "refusing to delete every todo" {
+			t.Fatalf("expected BeforeDeleteMany to stop the delete, got %v", err)
+		}
+
+		res, err := orm().Where(MultiHookToDoFields.Text.Eq("bulk")).DeleteMulti(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.DeletedCount != 2 || !slices.Equal(*calls, []string{"BeforeDeleteMany", "AfterDeleteMany 2"}) {
+			t.Fatalf("expected the delete hooks, got %v", *calls)
+		}
+	})
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/azayn-labs/mongorm"
+	"github.com/azayn-labs/mongorm/memdb"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func decodeLogRecords(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+
+	var records []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var record map[string]any
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatal(err)
+		}
+		records = append(records, record)
+	}
+	buf.Reset()
+
+	return records
+}
+
+func TestOperationLogging(t *testing.T) {
+	db := memdb.New("orm-test")
+	buf := &bytes.Buffer{}
+	logger := slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	orm := func(doc *MemToDo, opts mongorm.MongORMOptions) *mongorm.MongORM[MemToDo] {
+		opts.Backend = db
+		opts.Logger = logger
+		return mongorm.FromOptions(doc, &opts)
+	}
+
+	t.Run("Calls are logged with filters and result counts", func(t *testing.T) {
+		if _, err := orm(&MemToDo{Text: mongorm.String("logged")}, mongorm.MongORMOptions{}).Create(t.Context()); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := orm(&MemToDo{}, mongorm.MongORMOptions{}).
+			Where(MemToDoFields.Text.Eq("logged")).
+			Set(&MemToDo{Count: 2}).
+			SaveMulti(t.Context()); err != nil {
+			t.Fatal(err)
+		}
+
+		records := decodeLogRecords(t, buf)
+		var insert, update map[string]any
+		for _, record := range records {
+			switch record["operation"] {
+			case "InsertOne":
+				insert = record
+			case "UpdateMany":
+				update = record
+			}
+		}
+
+		if insert == nil || insert["level"] != "DEBUG" || insert["collection"] != "todo_memdb" || insert["inserted"] != float64(1) {
+			t.Fatalf("expected an InsertOne record, got %v", records)
+		}
+		if update == nil || update["filter"] != `{"text":"logged"}` || update["matched"] != float64(1) || update["modified"] != float64(1) {
+			t.Fatalf("expected an UpdateMany record with filter and counts, got %v", update)
+		}
+		if !strings.Contains(update["update"].(string), `"$set"`) {
+			t.Fatalf("expected the update document to be logged, got %v", update["update"])
+		}
+		if _, ok := update["duration"]; !ok {
+			t.Fatal("expected the duration to be logged")
+		}
+	})
+
+	t.Run("Levels and slow operations", func(t *testing.T) {
+		quiet := mongorm.MongORMOptions{LogLevel: slog.LevelDebug - 4}
+		if _, err := orm(&MemToDo{}, quiet).Count(t.Context()); err != nil {
+			t.Fatal(err)
+		}
+		if records := decodeLogRecords(t, buf); len(records) != 0 {
+			t.Fatalf("expected records below the handler level to be dropped, got %v", records)
+		}
+
+		slow := mongorm.MongORMOptions{SlowOperationThreshold: time.Nanosecond}
+		if _, err := orm(&MemToDo{}, slow).Count(t.Context()); err != nil {
+			t.Fatal(err)
+		}
+		records := decodeLogRecords(t, buf)
+		if len(records) != 1 || records[0]["level"] != "WARN" || records[0]["slow"] != true || records[0]["count"] != float64(1) {
+			t.Fatalf("expected a slow CountDocuments warning, got %v", records)
+		}
+	})
+
+	t.Run("Failed calls are logged as errors", func(t *testing.T) {
+		err := orm(&MemToDo{}, mongorm.MongORMOptions{}).Where(MemToDoFields.Text.Eq("missing")).First(t.Context())
+		if !errors.Is(err, mongorm.ErrNotFound) {
+			t.Fatalf("expected ErrNotFound, got %v", err)
+		}
+		records := decodeLogRecords(t, buf)
+		if len(records) != 1 || records[0]["level"] != "DEBUG" {
+			t.Fatalf("expected a missing document to be logged at the configured level, got %v", records)
+		}
+
+		_, err = orm(&MemToDo{}, mongorm.MongORMOptions{}).Where(bson.M{"$where": "true"}).Count(t.Context())
+		if !errors.Is(err, memdb.ErrUnsupported) {
+			t.Fatalf("expected memdb to reject $where, got %v", err)
+		}
+		records = decodeLogRecords(t, buf)
+		if len(records) != 1 || records[0]["level"] != "ERROR" || !strings.Contains(records[0]["error"].(string), "$where") {
+			t.Fatalf("expected the failure to be logged as an error, got %v", records)
+		}
+	})
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/azayn-labs/mongorm"
+	"github.com/azayn-labs/mongorm/memdb"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+var errNetwork = mongo.CommandError{Code: 6, Message: "connection reset", Labels: []string{"NetworkError"}}
+
+// flakyCollection decorates a Collection and fails the first calls of every method with err.
+type flakyCollection struct {
+	mongorm.Collection
+	err      error
+	failures int
+	calls    map[string]int
+}
+
+func (c *flakyCollection) fail(method string) error {
+	c.calls[method]++
+	if c.calls[method] <= c.failures {
+		return c.err
+	}
+	return nil
+}
+
+func (c *flakyCollection) FindOne(ctx context.Context, filter any, opts ...options.Lister[options.FindOneOptions]) *mongo.SingleResult {
+	if err := c.fail("FindOne"); err != nil {
+		return mongo.NewSingleResultFromDocument(bson.D{}, err, nil)
+	}
+	return c.Collection.FindOne(ctx, filter, opts...)
+}
+
+func (c *flakyCollection) CountDocuments(ctx context.Context, filter any, opts ...options.Lister[options.CountOptions]) (int64, error) {
+	if err := c.fail("CountDocuments"); err != nil {
+		return 0, err
+	}
+	return c.Collection.CountDocuments(ctx, filter, opts...)
+}
+
+func (c *flakyCollection) Aggregate(ctx context.Context, pipeline any, opts ...options.Lister[options.AggregateOptions]) (*mongo.Cursor, error) {
+	if err := c.fail("Aggregate"); err != nil {
+		return nil, err
+	}
+	return c.Collection.Aggregate(ctx, pipeline, opts...)
+}
+
+func (c *flakyCollection) InsertOne(ctx context.Context, document any, opts ...options.Lister[options.InsertOneOptions]) (*mongo.InsertOneResult, error) {
+	if err := c.fail("InsertOne"); err != nil {
+		return nil, err
+	}
+	return c.Collection.InsertOne(ctx, document, opts...)
+}
+
+func TestRetryPolicy(t *testing.T) {
+	db := memdb.New("orm-test")
+	if _, err := mongorm.FromOptions(&MemToDo{Text: mongorm.String("retry")}, &mongorm.MongORMOptions{Backend: db}).Create(t.Context()); err != nil {
+		t.Fatal(err)
+	}
+
+	flaky := func(err error, failures int) *flakyCollection {
+		return &flakyCollection{Collection: db.Collection("todo_memdb"), err: err, failures: failures, calls: map[string]int{}}
+	}
+	orm := func(coll mongorm.Collection, policy *mongorm.RetryPolicy) *mongorm.MongORM[MemToDo] {
+		return mongorm.FromOptions(&MemToDo{}, &mongorm.MongORMOptions{Collection: coll, RetryPolicy: policy})
+	}
+	policy := &mongorm.RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond, Jitter: 0.5}
+
+	t.Run("Reads are retried on transient errors", func(t *testing.T) {
+		coll := flaky(errNetwork, 2)
+		todo := &MemToDo{}
+		if err := mongorm.FromOptions(todo, &mongorm.MongORMOptions{Collection: coll, RetryPolicy: policy}).Where(MemToDoFields.Text.Eq("retry")).First(t.Context()); err != nil {
+			t.Fatal(err)
+		}
+		if coll.calls["FindOne"] != 3 || todo.Text == nil || *todo.Text != "retry" {
+			t.Fatalf("expected First to succeed on the third attempt, got %d calls", coll.calls["FindOne"])
+		}
+
+		coll = flaky(mongo.CommandError{Code: 112, Name: "WriteConflict"}, 1)
+		if count, err := orm(coll, policy).Count(t.Context()); err != nil || count != 1 {
+			t.Fatalf("expected Count to succeed after a write conflict, got %d %v", count, err)
+		}
+	})
+
+	t.Run("Retries stop after MaxAttempts", func(t *testing.T) {
+		coll := flaky(errNetwork, 5)
+		err := orm(coll, policy).First(t.Context())
+		if !mongo.IsNetworkError(err) || coll.calls["FindOne"] != 3 {
+			t.Fatalf("expected the network error after 3 attempts, got %v after %d", err, coll.calls["FindOne"])
+		}
+	})
+
+	t.Run("Only transient errors of idempotent reads are retried", func(t *testing.T) {
+		coll := flaky(errors.New("bad query"), 1)
+		if err := orm(coll, policy).First(t.Context()); err == nil || coll.calls["FindOne"] != 1 {
+			t.Fatalf("expected a permanent error to fail at once, got %v after %d", err, coll.calls["FindOne"])
+		}
+
+		coll = flaky(errNetwork, 1)
+		if err := orm(coll, policy).First(t.Context()); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := mongorm.FromOptions(&MemToDo{Text: mongorm.String("new")}, &mongorm.MongORMOptions{Collection: coll, RetryPolicy: policy}).Create(t.Context()); !mongo.IsNetworkError(err) || coll.calls["InsertOne"] != 1 {
+			t.Fatalf("expected writes not to be retried, got %v after %d", err, coll.calls["InsertOne"])
+		}
+
+		if _, err := orm(coll, policy).AggregateRaw(t.Context(), bson.A{bson.M{"$merge": "todo_copy"}}); !mongo.IsNetworkError(err) || coll.calls["Aggregate"] != 1 {
+			t.Fatalf("expected writing pipelines not to be retried, got %v after %d", err, coll.calls["Aggregate"])
+		}
+
+		coll = flaky(errNetwork, 1)
+		if err := orm(coll, nil).First(t.Context()); !mongo.IsNetworkError(err) || coll.calls["FindOne"] != 1 {
+			t.Fatalf("expected no retries without a policy, got %v after %d", err, coll.calls["FindOne"])
+		}
+	})
+
+	t.Run("Waiting stops when the context is done", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(t.Context(), 20*time.Millisecond)
+		defer cancel()
+
+		coll := flaky(errNetwork, 5)
+		start := time.Now()
+		err := orm(coll, &mongorm.RetryPolicy{MaxAttempts: 5, Backoff: time.Hour}).First(ctx)
+		if !mongo.IsNetworkError(err) || time.Since(start) > time.Second {
+			t.Fatalf("expected the last error once the context expired, got %v", err)
+		}
+	})
+
+	t.Run("IsRetryableError", func(t *testing.T) {
+		cases := map[string]struct {
+			err      error
+			expected bool
+		}{
+			"nil":               {nil, false},
+			"network":           {errNetwork, true},
+			"transient":         {mongo.CommandError{Labels: []string{"TransientTransactionError"}}, true},
+			"unknown commit":    {mongo.CommandError{Labels: []string{"UnknownTransactionCommitResult"}}, true},
+			"write conflict":    {mongo.WriteException{WriteErrors: mongo.WriteErrors{{Code: 112}}}, true},
+			"duplicate key":     {mongo.WriteException{WriteErrors: mongo.WriteErrors{{Code: 11000}}}, false},
+			"not found":         {mongorm.ErrNotFound, false},
+			"canceled":          {context.Canceled, false},
+			"wrapped transient": {errors.Join(mongorm.ErrTransactionUnsupported, errNetwork), true},
+		}
+		for name, c := range cases {
+			if got := mongorm.IsRetryableError(c.err); got != c.expected {
+				t.Fatalf("%s: expected %v, got %v", name, c.expected, got)
+			}
+		}
+	})
+}
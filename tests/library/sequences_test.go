@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/azayn-labs/mongorm"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type SequencedInvoice struct {
+	ID     *bson.ObjectID `bson:"_id,omitempty" mongorm:"primary"`
+	Number int64          `bson:"number,omitempty" mongorm:"sequence:invoice"`
+	Ticket *int64         `bson:"ticket,omitempty" mongorm:"sequence:ticket"`
+	Label  *string        `bson:"label,omitempty"`
+
+	connectionString *string `mongorm:"mongodb://localhost:27017,connection:url"`
+	database         *string `mongorm:"orm-test,connection:database"`
+	collection       *string `mongorm:"invoice_library,connection:collection"`
+}
+
+func ValidateLibrarySequences(t *testing.T) {
+	counters := fmt.Sprintf("counters_library_%d", time.Now().UnixNano())
+	options := &mongorm.MongORMOptions{CountersCollection: mongorm.String(counters)}
+
+	t.Run("Save fills sequence fields", func(t *testing.T) {
+		first := &SequencedInvoice{Label: mongorm.String("first")}
+		if err := mongorm.FromOptions(first, options).Save(t.Context()); err != nil {
+			t.Fatal(err)
+		}
+		defer func() { _ = mongorm.New(&SequencedInvoice{ID: first.ID}).Delete(t.Context()) }()
+
+		second := &SequencedInvoice{Label: mongorm.String("second")}
+		if err := mongorm.FromOptions(second, options).Save(t.Context()); err != nil {
+			t.Fatal(err)
+		}
+		defer func() { _ = mongorm.New(&SequencedInvoice{ID: second.ID}).Delete(t.Context()) }()
+
+		if first.Number != 1 || second.Number != 2 {
+			t.Fatalf("expected invoice numbers 1 and 2, got %d and %d", first.Number, second.Number)
+		}
+
+		if mongorm.Int64Val(first.Ticket) != 1 || mongorm.Int64Val(second.Ticket) != 2 {
+			t.Fatalf("expected independent ticket counter, got %v and %v", first.Ticket, second.Ticket)
+		}
+	})
+
+	t.Run("Explicit values are kept", func(t *testing.T) {
+		explicit := &SequencedInvoice{Number: 9000, Label: mongorm.String("explicit")}
+		if err := mongorm.FromOptions(explicit, options).Save(t.Context()); err != nil {
+			t.Fatal(err)
+		}
+		defer func() { _ = mongorm.New(&SequencedInvoice{ID: explicit.ID}).Delete(t.Context()) }()
+
+		if explicit.Number != 9000 {
+			t.Fatalf("expected explicit number to be kept, got %d", explicit.Number)
+		}
+	})
+
+	t.Run("Bulk write InsertOne fills sequence fields", func(t *testing.T) {
+		a := &SequencedInvoice{Label: mongorm.String("bulk-a")}
+		b := &SequencedInvoice{Label: mongorm.String("bulk-b")}
+		models := mongorm.NewBulkWriteBuilder[SequencedInvoice]().InsertOne(a).InsertOne(b).Models()
+
+		if _, err := mongorm.FromOptions(&SequencedInvoice{}, options).BulkWrite(t.Context(), models); err != nil {
+			t.Fatal(err)
+		}
+		defer func() {
+			_, _ = mongorm.New(&SequencedInvoice{}).
+				Where(bson.M{"label": bson.M{"$in": bson.A{"bulk-a", "bulk-b"}}}).
+				DeleteMulti(t.Context())
+		}()
+
+		if a.Number == 0 || b.Number != a.Number+1 {
+			t.Fatalf("expected consecutive bulk numbers, got %d and %d", a.Number, b.Number)
+		}
+	})
+
+	t.Run("Sequence increments roll back with transactions", func(t *testing.T) {
+		orm := mongorm.FromOptions(&SequencedInvoice{}, options)
+		before, err := orm.NextSequence(t.Context(), "tx")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = orm.WithTransaction(t.Context(), func(txCtx context.Context) error {
+			if _, err := orm.NextSequence(txCtx, "tx"); err != nil {
+				return err
+			}
+			return fmt.Errorf("rollback")
+		})
+		if mongorm.IsTransactionUnsupported(err) {
+			t.Skipf("transactions unsupported by current mongodb setup: %v", err)
+		}
+
+		after, err := orm.NextSequence(t.Context(), "tx")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if after != before+1 {
+			t.Fatalf("expected rolled back increment to be discarded, got before=%d after=%d", before, after)
+		}
+	})
+}
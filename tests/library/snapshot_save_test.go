@@ -0,0 +1,136 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/azayn-labs/mongorm"
+	"github.com/azayn-labs/mongorm/primitives"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type SnapshotToDo struct {
+	ID    *bson.ObjectID `bson:"_id,omitempty" mongorm:"primary"`
+	Text  *string        `bson:"text,omitempty"`
+	Done  *bool          `bson:"done,omitempty"`
+	Meta  *ToDoMeta      `bson:"meta,omitempty"`
+	Count int64          `bson:"count"`
+
+	connectionString *string `mongorm:"mongodb://localhost:27017,connection:url"`
+	database         *string `mongorm:"orm-test,connection:database"`
+	collection       *string `mongorm:"todo_library_snapshot,connection:collection"`
+}
+
+type SnapshotToDoSchema struct {
+	ID    *primitives.ObjectIDField
+	Text  *primitives.StringField
+	Done  *primitives.BoolField
+	Meta  *ToDoMetaSchema
+	Count *primitives.Int64Field
+}
+
+var SnapshotToDoFields = mongorm.FieldsOf[SnapshotToDo, SnapshotToDoSchema]()
+
+var snapshotTextOld, snapshotTextNew any
+var snapshotSaveFields []string
+
+func (t *SnapshotToDo) BeforeSave(m *mongorm.MongORM[SnapshotToDo], _ *bson.M) error {
+	snapshotTextOld, snapshotTextNew, _ = m.ModifiedValue(SnapshotToDoFields.Text)
+
+	snapshotSaveFields = snapshotSaveFields[:0]
+	for _, field := range m.ModifiedFields() {
+		snapshotSaveFields = append(snapshotSaveFields, field.BSONName())
+	}
+
+	return nil
+}
+
+func ValidateLibrarySnapshotSave(t *testing.T) {
+	text := "snapshot-" + time.Now().Format(time.RFC3339Nano)
+
+	seed := &SnapshotToDo{
+		Text:  mongorm.String(text),
+		Done:  mongorm.Bool(false),
+		Meta:  &ToDoMeta{Source: mongorm.String("api"), Priority: mongorm.Int64(1)},
+		Count: 5,
+	}
+	if err := mongorm.New(seed).Save(t.Context()); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = mongorm.New(&SnapshotToDo{ID: seed.ID}).Delete(t.Context())
+	}()
+
+	t.Run("Save writes only changed fields", func(t *testing.T) {
+		loaded := &SnapshotToDo{}
+		orm := mongorm.New(loaded)
+		if err := orm.Where(SnapshotToDoFields.ID.Eq(*seed.ID)).First(t.Context()); err != nil {
+			t.Fatal(err)
+		}
+
+		// A concurrent writer changes a field this instance does not touch.
+		if err := mongorm.New(&SnapshotToDo{}).
+			Where(SnapshotToDoFields.ID.Eq(*seed.ID)).
+			SetData(SnapshotToDoFields.Count, int64(42)).
+			FindOneAndUpdate(t.Context()); err != nil {
+			t.Fatal(err)
+		}
+
+		doc := orm.Document()
+		doc.Text = mongorm.String(text + "-updated")
+		doc.Done = nil
+		doc.Meta.Priority = mongorm.Int64(2)
+
+		if err := orm.Save(t.Context()); err != nil {
+			t.Fatal(err)
+		}
+
+		if snapshotTextOld != text || snapshotTextNew != text+"-updated" {
+			t.Fatalf("expected ModifiedValue(text) to be %q -> %q, got %v -> %v", text, text+"-updated", snapshotTextOld, snapshotTextNew)
+		}
+
+		expected := []string{"done", "meta.priority", "text"}
+		if len(snapshotSaveFields) != len(expected) {
+			t.Fatalf("expected modified fields %v, got %v", expected, snapshotSaveFields)
+		}
+		for i, name := range expected {
+			if snapshotSaveFields[i] != name {
+				t.Fatalf("expected modified fields %v, got %v", expected, snapshotSaveFields)
+			}
+		}
+
+		verify := &SnapshotToDo{}
+		if err := mongorm.New(verify).Where(SnapshotToDoFields.ID.Eq(*seed.ID)).First(t.Context()); err != nil {
+			t.Fatal(err)
+		}
+
+		if mongorm.StringVal(verify.Text) != text+"-updated" {
+			t.Fatalf("expected text to be updated, got %q", mongorm.StringVal(verify.Text))
+		}
+		if verify.Done != nil {
+			t.Fatalf("expected done to be unset, got %v", *verify.Done)
+		}
+		if verify.Meta == nil || mongorm.Int64Val(verify.Meta.Priority) != 2 || mongorm.StringVal(verify.Meta.Source) != "api" {
+			t.Fatalf("expected only meta.priority to change, got %+v", verify.Meta)
+		}
+		if verify.Count != 42 {
+			t.Fatalf("expected untouched count to keep the concurrent value 42, got %d", verify.Count)
+		}
+	})
+
+	t.Run("Save without changes is a no-op", func(t *testing.T) {
+		loaded := &SnapshotToDo{}
+		orm := mongorm.New(loaded)
+		if err := orm.Where(SnapshotToDoFields.ID.Eq(*seed.ID)).First(t.Context()); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := orm.Save(t.Context()); err != nil {
+			t.Fatalf("expected unchanged save to succeed, got %v", err)
+		}
+
+		if len(snapshotSaveFields) != 0 {
+			t.Fatalf("expected no modified fields, got %v", snapshotSaveFields)
+		}
+	})
+}
@@ -0,0 +1,266 @@
+package main
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/azayn-labs/mongorm"
+	"github.com/azayn-labs/mongorm/memdb"
+	"github.com/azayn-labs/mongorm/primitives"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type SoftDeleteToDo struct {
+	ID        *bson.ObjectID `bson:"_id,omitempty" mongorm:"primary"`
+	Text      *string        `bson:"text,omitempty"`
+	DeletedAt *time.Time     `bson:"deleted_at,omitempty" mongorm:"soft_delete"`
+
+	connectionString *string `mongorm:"mongodb://localhost:27017,connection:url"`
+	database         *string `mongorm:"orm-test,connection:database"`
+	collection       *string `mongorm:"todo_library_soft_delete,connection:collection"`
+}
+
+type SoftDeleteToDoSchema struct {
+	ID        *primitives.ObjectIDField
+	Text      *primitives.StringField
+	DeletedAt *primitives.TimestampField
+}
+
+var SoftDeleteToDoFields = mongorm.FieldsOf[SoftDeleteToDo, SoftDeleteToDoSchema]()
+
+type InvalidSoftDeleteToDo struct {
+	ID        *bson.ObjectID `bson:"_id,omitempty" mongorm:"primary"`
+	DeletedAt bool           `bson:"deleted_at,omitempty" mongorm:"soft_delete"`
+
+	connectionString *string `mongorm:"mongodb://localhost:27017,connection:url"`
+	database         *string `mongorm:"orm-test,connection:database"`
+	collection       *string `mongorm:"todo_library_soft_delete,connection:collection"`
+}
+
+func TestSoftDeleteFieldMustBeTimePointer(t *testing.T) {
+	err := mongorm.New(&InvalidSoftDeleteToDo{}).First(t.Context())
+	if !errors.Is(err, mongorm.ErrInvalidConfig) {
+		t.Fatalf("expected invalid config error, got %v", err)
+	}
+}
+
+func TestSoftDeleteScopeAppliesToOneCall(t *testing.T) {
+	db := memdb.New("orm-test")
+	for _, text := range []string{"kept", "trashed"} {
+		todo := &SoftDeleteToDo{Text: mongorm.String(text)}
+		if err := mongorm.FromOptions(todo, &mongorm.MongORMOptions{Backend: db}).Save(t.Context()); err != nil {
+			t.Fatal(err)
+		}
+		if text == "trashed" {
+			if err := mongorm.FromOptions(todo, &mongorm.MongORMOptions{Backend: db}).Delete(t.Context()); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	orm := mongorm.FromOptions(&SoftDeleteToDo{}, &mongorm.MongORMOptions{Backend: db})
+
+	if got, err := orm.WithTrashed().Count(t.Context()); err != nil || got != 2 {
+		t.Fatalf("expected WithTrashed to count both documents, got %d %v", got, err)
+	}
+	if got, err := orm.Count(t.Context()); err != nil || got != 1 {
+		t.Fatalf("expected the next Count to hide trashed documents, got %d %v", got, err)
+	}
+
+	if texts, err := orm.OnlyTrashed().Distinct(t.Context(), SoftDeleteToDoFields.Text); err != nil || len(texts) != 1 || texts[0] != "trashed" {
+		t.Fatalf("expected OnlyTrashed to see the trashed document, got %v %v", texts, err)
+	}
+	if texts, err := orm.Distinct(t.Context(), SoftDeleteToDoFields.Text); err != nil || len(texts) != 1 || texts[0] != "kept" {
+		t.Fatalf("expected the next Distinct to hide trashed documents, got %v %v", texts, err)
+	}
+
+	cursor, err := orm.WithTrashed().FindAll(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+	all, err := cursor.All(t.Context())
+	if err != nil || len(all) != 2 {
+		t.Fatalf("expected WithTrashed to find both documents, got %d %v", len(all), err)
+	}
+
+	cursor, err = orm.FindAll(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+	kept, err := cursor.All(t.Context())
+	if err != nil || len(kept) != 1 {
+		t.Fatalf("expected the next FindAll to hide trashed documents, got %d %v", len(kept), err)
+	}
+}
+
+func TestSoftDeleteScopeAppliesToUpdates(t *testing.T) {
+	db := memdb.New("orm-test")
+	todo := &SoftDeleteToDo{Text: mongorm.String("trashed")}
+	if err := mongorm.FromOptions(todo, &mongorm.MongORMOptions{Backend: db}).Save(t.Context()); err != nil {
+		t.Fatal(err)
+	}
+	if err := mongorm.FromOptions(todo, &mongorm.MongORMOptions{Backend: db}).Delete(t.Context()); err != nil {
+		t.Fatal(err)
+	}
+
+	orm := func() *mongorm.MongORM[SoftDeleteToDo] {
+		return mongorm.FromOptions(&SoftDeleteToDo{}, &mongorm.MongORMOptions{Backend: db}).
+			Where(SoftDeleteToDoFields.ID.Eq(*todo.ID))
+	}
+
+	if _, err := orm().Set(&SoftDeleteToDo{Text: mongorm.String("updated")}).Update(t.Context()); !errors.Is(err, mongorm.ErrNotFound) {
+		t.Fatalf("expected Update to skip the trashed document, got %v", err)
+	}
+	if err := orm().Set(&SoftDeleteToDo{Text: mongorm.String("updated")}).FindOneAndUpdate(t.Context()); !errors.Is(err, mongorm.ErrNotFound) {
+		t.Fatalf("expected FindOneAndUpdate to skip the trashed document, got %v", err)
+	}
+
+	if _, err := orm().WithTrashed().Set(&SoftDeleteToDo{Text: mongorm.String("updated")}).Update(t.Context()); err != nil {
+		t.Fatalf("expected WithTrashed to update the trashed document, got %v", err)
+	}
+
+	updated := &SoftDeleteToDo{}
+	model := mongorm.FromOptions(updated, &mongorm.MongORMOptions{Backend: db}).Where(SoftDeleteToDoFields.ID.Eq(*todo.ID))
+	if err := model.OnlyTrashed().Set(&SoftDeleteToDo{Text: mongorm.String("again")}).FindOneAndUpdate(t.Context()); err != nil {
+		t.Fatalf("expected OnlyTrashed to update the trashed document, got %v", err)
+	}
+	if mongorm.StringVal(updated.Text) != "again" || updated.DeletedAt == nil {
+		t.Fatalf("expected the trashed document to stay trashed, got %+v", updated)
+	}
+}
+
+func ValidateLibrarySoftDelete(t *testing.T) {
+	text := "soft-delete-" + time.Now().Format(time.RFC3339Nano)
+
+	create := func(t *testing.T) *SoftDeleteToDo {
+		model := &SoftDeleteToDo{Text: mongorm.String(text)}
+		if err := mongorm.New(model).Save(t.Context()); err != nil {
+			t.Fatal(err)
+		}
+		return model
+	}
+
+	count := func(t *testing.T, scope func(*mongorm.MongORM[SoftDeleteToDo])) int64 {
+		orm := mongorm.New(&SoftDeleteToDo{}).Where(SoftDeleteToDoFields.Text.Eq(text))
+		if scope != nil {
+			scope(orm)
+		}
+
+		total, err := orm.Count(t.Context())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return total
+	}
+
+	first := create(t)
+	second := create(t)
+	defer func() {
+		_ = mongorm.New(&SoftDeleteToDo{ID: first.ID}).ForceDelete(t.Context())
+		_ = mongorm.New(&SoftDeleteToDo{ID: second.ID}).ForceDelete(t.Context())
+	}()
+
+	t.Run("Delete sets the timestamp", func(t *testing.T) {
+		if err := mongorm.New(&SoftDeleteToDo{ID: first.ID}).Delete(t.Context()); err != nil {
+			t.Fatal(err)
+		}
+
+		err := mongorm.New(&SoftDeleteToDo{ID: first.ID}).First(t.Context())
+		if !errors.Is(err, mongorm.ErrNotFound) {
+			t.Fatalf("expected soft-deleted document to be hidden, got %v", err)
+		}
+
+		trashed := &SoftDeleteToDo{ID: first.ID}
+		if err := mongorm.New(trashed).OnlyTrashed().First(t.Context()); err != nil {
+			t.Fatal(err)
+		}
+		if trashed.DeletedAt == nil || trashed.DeletedAt.IsZero() {
+			t.Fatal("expected deleted_at to be set")
+		}
+
+		err = mongorm.New(&SoftDeleteToDo{ID: first.ID}).Delete(t.Context())
+		if !errors.Is(err, mongorm.ErrNotFound) {
+			t.Fatalf("expected deleting a trashed document to return not found, got %v", err)
+		}
+	})
+
+	t.Run("Reads exclude trashed documents", func(t *testing.T) {
+		if got := count(t, nil); got != 1 {
+			t.Fatalf("expected 1 live document, got %d", got)
+		}
+		if got := count(t, func(m *mongorm.MongORM[SoftDeleteToDo]) { m.WithTrashed() }); got != 2 {
+			t.Fatalf("expected 2 documents with trashed, got %d", got)
+		}
+		if got := count(t, func(m *mongorm.MongORM[SoftDeleteToDo]) { m.OnlyTrashed() }); got != 1 {
+			t.Fatalf("expected 1 trashed document, got %d", got)
+		}
+
+		ids, err := mongorm.New(&SoftDeleteToDo{}).
+			Where(SoftDeleteToDoFields.Text.Eq(text)).
+			DistinctObjectIDs(t.Context(), SoftDeleteToDoFields.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(ids) != 1 || ids[0] != *second.ID {
+			t.Fatalf("expected distinct to return only the live document, got %v", ids)
+		}
+
+		results, err := mongorm.AggregateAs[SoftDeleteToDo, bson.M](
+			mongorm.New(&SoftDeleteToDo{}).Where(SoftDeleteToDoFields.Text.Eq(text)),
+			t.Context(),
+			bson.A{},
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(results) != 1 {
+			t.Fatalf("expected aggregate to return 1 live document, got %d", len(results))
+		}
+	})
+
+	t.Run("Restore clears the timestamp", func(t *testing.T) {
+		restored := &SoftDeleteToDo{ID: first.ID}
+		if err := mongorm.New(restored).Restore(t.Context()); err != nil {
+			t.Fatal(err)
+		}
+		if restored.DeletedAt != nil {
+			t.Fatalf("expected deleted_at to be cleared, got %v", restored.DeletedAt)
+		}
+
+		if got := count(t, nil); got != 2 {
+			t.Fatalf("expected 2 live documents after restore, got %d", got)
+		}
+
+		err := mongorm.New(&SoftDeleteToDo{ID: first.ID}).Restore(t.Context())
+		if !errors.Is(err, mongorm.ErrNotFound) {
+			t.Fatalf("expected restoring a live document to return not found, got %v", err)
+		}
+	})
+
+	t.Run("DeleteMulti marks all matches", func(t *testing.T) {
+		res, err := mongorm.New(&SoftDeleteToDo{}).
+			Where(SoftDeleteToDoFields.Text.Eq(text)).
+			DeleteMulti(t.Context())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.DeletedCount != 2 {
+			t.Fatalf("expected 2 soft-deleted documents, got %d", res.DeletedCount)
+		}
+
+		if got := count(t, func(m *mongorm.MongORM[SoftDeleteToDo]) { m.OnlyTrashed() }); got != 2 {
+			t.Fatalf("expected 2 trashed documents, got %d", got)
+		}
+	})
+
+	t.Run("ForceDelete removes trashed documents", func(t *testing.T) {
+		if err := mongorm.New(&SoftDeleteToDo{ID: second.ID}).ForceDelete(t.Context()); err != nil {
+			t.Fatal(err)
+		}
+
+		if got := count(t, func(m *mongorm.MongORM[SoftDeleteToDo]) { m.WithTrashed() }); got != 1 {
+			t.Fatalf("expected 1 remaining document, got %d", got)
+		}
+	})
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/azayn-labs/mongorm"
+	"github.com/azayn-labs/mongorm/memdb"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type recordedSpan struct {
+	name  string
+	err   error
+	attrs map[string]any
+}
+
+type spanNameKey struct{}
+
+// recordingTelemetry implements mongorm.Tracer, mongorm.Span and mongorm.Metrics.
+type recordingTelemetry struct {
+	mu       sync.Mutex
+	spans    []*recordedSpan
+	counters map[string]float64
+	observed map[string][]float64
+}
+
+func newRecordingTelemetry() *recordingTelemetry {
+	return &recordingTelemetry{counters: map[string]float64{}, observed: map[string][]float64{}}
+}
+
+type recordingSpan struct {
+	telemetry *recordingTelemetry
+	span      *recordedSpan
+}
+
+func (r *recordingTelemetry) StartSpan(ctx context.Context, name string, attrs ...mongorm.Attribute) (context.Context, mongorm.Span) {
+	span := &recordedSpan{name: name, attrs: map[string]any{}}
+	for _, attr := range attrs {
+		span.attrs[attr.Key] = attr.Value
+	}
+
+	return context.WithValue(ctx, spanNameKey{}, name), recordingSpan{telemetry: r, span: span}
+}
+
+func (s recordingSpan) End(err error, attrs ...mongorm.Attribute) {
+	s.span.err = err
+	for _, attr := range attrs {
+		s.span.attrs[attr.Key] = attr.Value
+	}
+
+	s.telemetry.mu.Lock()
+	defer s.telemetry.mu.Unlock()
+	s.telemetry.spans = append(s.telemetry.spans, s.span)
+}
+
+func (r *recordingTelemetry) AddCounter(name string, value float64, attrs ...mongorm.Attribute) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.counters[name] += value
+}
+
+func (r *recordingTelemetry) ObserveHistogram(name string, value float64, attrs ...mongorm.Attribute) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.observed[name] = append(r.observed[name], value)
+}
+
+func (r *recordingTelemetry) last(t *testing.T) *recordedSpan {
+	t.Helper()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.spans) == 0 {
+		t.Fatal("expected a span")
+	}
+	return r.spans[len(r.spans)-1]
+}
+
+func TestTelemetry(t *testing.T) {
+	db := memdb.New("orm-test")
+	telemetry := newRecordingTelemetry()
+	orm := func(doc *MemToDo) *mongorm.MongORM[MemToDo] {
+		return mongorm.FromOptions(doc, &mongorm.MongORMOptions{Backend: db, Tracer: telemetry, Metrics: telemetry})
+	}
+
+	if _, err := orm(&MemToDo{}).EnsureIndexes(t.Context(), []mongo.IndexModel{
+		{Keys: bson.D{{Key: "text", Value: 1}}, Options: options.Index().SetUnique(true)},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Operations report spans and metrics", func(t *testing.T) {
+		if _, err := orm(&MemToDo{Text: mongorm.String("traced")}).Create(t.Context()); err != nil {
+			t.Fatal(err)
+		}
+
+		span := telemetry.last(t)
+		if span.name != "mongorm.Create" ||
+			span.attrs[mongorm.AttributeOperation] != "Create" ||
+			span.attrs[mongorm.AttributeCollection] != "todo_memdb" ||
+			span.attrs[mongorm.AttributeDocuments] != int64(1) ||
+			span.attrs[mongorm.AttributeErrorClass] != string(mongorm.ErrorClassNone) ||
+			span.err != nil {
+			t.Fatalf("expected a successful Create span, got %+v", span)
+		}
+		if telemetry.counters[mongorm.MetricOperations] != 1 || len(telemetry.observed[mongorm.MetricOperationDuration]) != 1 {
+			t.Fatalf("expected the operation to be counted and timed, got %v %v", telemetry.counters, telemetry.observed)
+		}
+
+		res, err := orm(&MemToDo{}).Where(MemToDoFields.Text.Eq("traced")).Set(&MemToDo{Count: 3}).SaveMulti(t.Context())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if span := telemetry.last(t); span.name != "mongorm.SaveMulti" || span.attrs[mongorm.AttributeDocuments] != res.MatchedCount {
+			t.Fatalf("expected SaveMulti to report its matched documents, got %+v", span)
+		}
+	})
+
+	t.Run("Errors are classified", func(t *testing.T) {
+		err := orm(&MemToDo{}).Where(MemToDoFields.Text.Eq("missing")).First(t.Context())
+		if span := telemetry.last(t); !errors.Is(err, mongorm.ErrNotFound) || span.attrs[mongorm.AttributeErrorClass] != string(mongorm.ErrorClassNotFound) {
+			t.Fatalf("expected a NotFound span, got %+v", span)
+		}
+		if _, ok := telemetry.last(t).attrs[mongorm.AttributeDocuments]; ok {
+			t.Fatal("expected failed operations to report no document count")
+		}
+
+		_, err = orm(&MemToDo{Text: mongorm.String("traced")}).Create(t.Context())
+		if span := telemetry.last(t); !errors.Is(err, mongorm.ErrDuplicateKey) || span.attrs[mongorm.AttributeErrorClass] != string(mongorm.ErrorClassDuplicateKey) {
+			t.Fatalf("expected a DuplicateKey span, got %+v", span)
+		}
+
+		versioned := func(doc *StringKeyToDo) *mongorm.MongORM[StringKeyToDo] {
+			return mongorm.FromOptions(doc, &mongorm.MongORMOptions{
+				Backend:        db,
+				CollectionName: mongorm.String("todo_versioned"),
+				Tracer:         telemetry,
+			})
+		}
+		if err := versioned(&StringKeyToDo{ID: mongorm.String("a"), Text: mongorm.String("v1")}).Save(t.Context()); err != nil {
+			t.Fatal(err)
+		}
+		err = versioned(&StringKeyToDo{ID: mongorm.String("a"), Version: 7}).Set(&StringKeyToDo{Text: mongorm.String("v2")}).Save(t.Context())
+		if span := telemetry.last(t); !errors.Is(err, mongorm.ErrOptimisticLockConflict) || span.attrs[mongorm.AttributeErrorClass] != string(mongorm.ErrorClassOptimisticLockConflict) {
+			t.Fatalf("expected an OptimisticLockConflict span, got %v %+v", err, span)
+		}
+	})
+
+	t.Run("Operations run inside their span", func(t *testing.T) {
+		var parent string
+		inspect := func(next mongorm.OperationFunc) mongorm.OperationFunc {
+			return func(ctx context.Context, op *mongorm.Operation) (any, error) {
+				parent, _ = ctx.Value(spanNameKey{}).(string)
+				return next(ctx, op)
+			}
+		}
+
+		traced := mongorm.FromOptions(&MemToDo{}, &mongorm.MongORMOptions{Backend: db, Tracer: telemetry, Middleware: []mongorm.Middleware{inspect}})
+		if _, err := traced.Count(t.Context()); err != nil {
+			t.Fatal(err)
+		}
+		if parent != "mongorm.Count" {
+			t.Fatalf("expected the collection call to receive the span context, got %q", parent)
+		}
+	})
+
+	t.Run("ClassifyError", func(t *testing.T) {
+		cases := map[error]mongorm.ErrorClass{
+			nil:                               mongorm.ErrorClassNone,
+			mongorm.ErrNotFound:               mongorm.ErrorClassNotFound,
+			mongorm.ErrInvalidConfig:          mongorm.ErrorClassInvalidConfig,
+			context.Canceled:                  mongorm.ErrorClassCanceled,
+			context.DeadlineExceeded:          mongorm.ErrorClassTimeout,
+			errors.New("connection refused"):  mongorm.ErrorClassOther,
+			mongorm.ErrTransactionUnsupported: mongorm.ErrorClassTransactionUnsupported,
+		}
+		for err, expected := range cases {
+			if class := mongorm.ClassifyError(err); class != expected {
+				t.Fatalf("expected %v to be classified as %s, got %s", err, expected, class)
+			}
+		}
+	})
+}
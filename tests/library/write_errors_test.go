@@ -0,0 +1,115 @@
+package main
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/azayn-labs/mongorm"
+	"github.com/azayn-labs/mongorm/memdb"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+func TestWriteErrors(t *testing.T) {
+	db := memdb.New("orm-test")
+	orm := func(doc *MemToDo) *mongorm.MongORM[MemToDo] {
+		return mongorm.FromOptions(doc, &mongorm.MongORMOptions{Backend: db})
+	}
+	withSource := func(text, source string) *MemToDo {
+		return &MemToDo{Text: mongorm.String(text), Meta: &ToDoMeta{Source: mongorm.String(source)}}
+	}
+
+	if _, err := orm(&MemToDo{}).EnsureIndexes(t.Context(), []mongo.IndexModel{{
+		Keys:    bson.D{{Key: "text", Value: 1}, {Key: "meta.source", Value: 1}},
+		Options: options.Index().SetName("text_source").SetUnique(true),
+	}}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, text := range []string{"taken", "other"} {
+		if _, err := orm(withSource(text, "api")).Create(t.Context()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	assertDuplicate := func(t *testing.T, err error) {
+		t.Helper()
+
+		var dup *mongorm.DuplicateKeyError
+		if !errors.As(err, &dup) {
+			t.Fatalf("expected a DuplicateKeyError, got %v", err)
+		}
+		if !errors.Is(err, mongorm.ErrDuplicateKey) || !mongo.IsDuplicateKeyError(err) {
+			t.Fatalf("expected the error to match ErrDuplicateKey and the driver error, got %v", err)
+		}
+		if dup.Index != "text_source" {
+			t.Fatalf("expected the index name, got %q", dup.Index)
+		}
+		if len(dup.KeyPattern) != 2 || dup.KeyPattern[0].Key != "text" || dup.KeyPattern[1].Key != "meta.source" {
+			t.Fatalf("expected the key pattern, got %v", dup.KeyPattern)
+		}
+		if len(dup.KeyValues) != 2 || dup.KeyValues[0].Value != "taken" || dup.KeyValues[1].Value != "api" {
+			t.Fatalf("expected the conflicting values, got %v", dup.KeyValues)
+		}
+		if !slices.Equal(dup.Fields, []string{"Text", "Meta.Source"}) {
+			t.Fatalf("expected the struct fields, got %v", dup.Fields)
+		}
+	}
+
+	t.Run("Single writes report the duplicate key", func(t *testing.T) {
+		_, err := orm(withSource("taken", "api")).Create(t.Context())
+		assertDuplicate(t, err)
+
+		err = orm(&MemToDo{}).Where(MemToDoFields.Text.Eq("other")).Set(&MemToDo{Text: mongorm.String("taken")}).Save(t.Context())
+		assertDuplicate(t, err)
+
+		_, err = orm(&MemToDo{}).Where(MemToDoFields.Text.Eq("other")).Set(&MemToDo{Text: mongorm.String("taken")}).Update(t.Context())
+		assertDuplicate(t, err)
+
+		res, err := orm(&MemToDo{}).CreateMany(t.Context(), []*MemToDo{withSource("new", "api"), withSource("taken", "api")})
+		if err == nil {
+			t.Fatal("expected CreateMany to fail")
+		}
+		assertDuplicate(t, res.Errors[1])
+	})
+
+	t.Run("Bulk writes map errors to model positions", func(t *testing.T) {
+		models := mongorm.NewBulkWriteBuilder[MemToDo]().
+			InsertOne(withSource("bulk", "api")).
+			InsertOne(withSource("taken", "api")).
+			UpdateOne(bson.M{"text": "bulk"}, bson.M{"$set": bson.M{"count": 1}}, false).
+			InsertOne(withSource("other", "api")).
+			Models()
+
+		_, err := orm(&MemToDo{}).BulkWrite(t.Context(), models, options.BulkWrite().SetOrdered(false))
+
+		var writeErrs *mongorm.WriteErrors
+		if !errors.As(err, &writeErrs) {
+			t.Fatalf("expected WriteErrors, got %v", err)
+		}
+		if !slices.Equal(writeErrs.Indexes(), []int{1, 3}) {
+			t.Fatalf("expected the failed model positions, got %v", writeErrs.Indexes())
+		}
+		assertDuplicate(t, writeErrs.Errors[1])
+		if !errors.Is(err, mongorm.ErrDuplicateKey) {
+			t.Fatalf("expected WriteErrors to match ErrDuplicateKey, got %v", err)
+		}
+
+		var bulkErr mongo.BulkWriteException
+		if !errors.As(err, &bulkErr) {
+			t.Fatalf("expected WriteErrors to unwrap to the driver error, got %v", err)
+		}
+	})
+
+	t.Run("Multi-document writes report WriteErrors", func(t *testing.T) {
+		_, err := orm(&MemToDo{}).Where(MemToDoFields.Text.Eq("other")).Set(&MemToDo{Text: mongorm.String("taken")}).SaveMulti(t.Context())
+
+		var writeErrs *mongorm.WriteErrors
+		if !errors.As(err, &writeErrs) {
+			t.Fatalf("expected WriteErrors, got %v", err)
+		}
+		assertDuplicate(t, writeErrs.Errors[0])
+	})
+}
@@ -2,6 +2,7 @@ package mongorm
 
 import (
 	"context"
+	"errors"
 
 	"go.mongodb.org/mongo-driver/v2/mongo"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
@@ -11,6 +12,12 @@ import (
 //
 // The callback receives a transaction-bound context. Any MongORM operation
 // using that context will run in the same transaction session.
+//
+// Without a RetryPolicy, the driver's Session.WithTransaction retries transient errors
+// for up to two minutes. With MongORMOptions.RetryPolicy set, the transaction is run
+// manually instead and the policy alone bounds the attempts: the callback is re-run in a
+// new transaction when it or the commit fails with a retryable error (see
+// IsRetryableError), so it must be safe to run more than once.
 func (m *MongORM[T]) WithTransaction(
 	ctx context.Context,
 	fn func(txCtx context.Context) error,
@@ -29,6 +36,22 @@ func (m *MongORM[T]) WithTransaction(
 		return err
 	}
 
+	if m.options == nil || m.options.RetryPolicy == nil {
+		return runInTransaction(ctx, client, fn, opts...)
+	}
+
+	return retryInTransaction(ctx, client, m.options.RetryPolicy, fn, opts...)
+}
+
+// runInTransaction runs fn inside a transaction on a new session of client.
+//
+// > NOTE: This function is internal only.
+func runInTransaction(
+	ctx context.Context,
+	client *mongo.Client,
+	fn func(txCtx context.Context) error,
+	opts ...options.Lister[options.TransactionOptions],
+) error {
 	session, err := client.StartSession()
 	if err != nil {
 		return normalizeError(err)
@@ -50,6 +73,48 @@ func (m *MongORM[T]) WithTransaction(
 	return normalizeError(err)
 }
 
+// retryInTransaction runs fn inside a transaction on a new session of client, starting
+// and committing the transaction itself so that policy is the only retry loop. A commit
+// with an unknown result is committed again rather than re-running fn.
+//
+// > NOTE: This function is internal only.
+func retryInTransaction(
+	ctx context.Context,
+	client *mongo.Client,
+	policy *RetryPolicy,
+	fn func(txCtx context.Context) error,
+	opts ...options.Lister[options.TransactionOptions],
+) error {
+	session, err := client.StartSession()
+	if err != nil {
+		return normalizeError(err)
+	}
+	defer session.EndSession(ctx)
+
+	committing := false
+	_, err = retry(ctx, policy, func() (any, error) {
+		if !committing {
+			if err := session.StartTransaction(opts...); err != nil {
+				return nil, err
+			}
+
+			if err := fn(mongo.NewSessionContext(ctx, session)); err != nil {
+				_ = session.AbortTransaction(context.WithoutCancel(ctx))
+				return nil, err
+			}
+		}
+
+		err := session.CommitTransaction(ctx)
+
+		var serverErr mongo.ServerError
+		committing = errors.As(err, &serverErr) && serverErr.HasErrorLabel("UnknownTransactionCommitResult")
+
+		return nil, err
+	})
+
+	return normalizeError(err)
+}
+
 func (m *MongORM[T]) client() (*mongo.Client, error) {
 	if m == nil {
 		return nil, configErrorf("mongorm instance is nil")
@@ -1,8 +1,6 @@
 package mongorm
 
 import (
-	"maps"
-
 	"go.mongodb.org/mongo-driver/v2/bson"
 )
 
@@ -15,25 +13,24 @@ func (m *MongORM[T]) appendQueryExpression(operator string, expr bson.M) *MongOR
 		m.operations.query = bson.M{}
 	}
 
-	if m.operations.query[operator] == nil {
-		m.operations.query[operator] = bson.A{}
-	}
-
-	if clauses, ok := m.operations.query[operator].(bson.A); ok {
-		m.operations.query[operator] = append(clauses, expr)
-	}
+	clauses := expressionClauses(m.operations.query[operator])
+	m.operations.query[operator] = append(clauses, expr)
 
 	return m
 }
 
 // Where adds a query filter to the MongORM instance as plain top-level query fields.
-// Repeated calls merge into the same query document.
+// Repeated calls merge into the same query document. When a field (or a logical operator
+// such as $or) is already present, the new condition is added under $and instead of
+// overwriting the previous one, so both conditions apply.
 //
 // Example usage:
 //
 //	orm.Where(bson.M{"age": bson.M{"$gt": 30}}).Where(bson.M{"name": "John"})
 //	// OR
 //	orm.Where(fielType.Age.Gt(30)).Where(fieldType.Name.Eq("John"))
+//	// Same field twice => {"age": {"$gt": 30}, "$and": [{"age": {"$lt": 60}}]}
+//	orm.Where(fieldType.Age.Gt(30)).Where(fieldType.Age.Lt(60))
 func (m *MongORM[T]) Where(expr bson.M) *MongORM[T] {
 	if expr == nil {
 		return m
@@ -43,7 +40,20 @@ func (m *MongORM[T]) Where(expr bson.M) *MongORM[T] {
 		m.operations.query = bson.M{}
 	}
 
-	maps.Copy(m.operations.query, expr)
+	for key, value := range expr {
+		if _, exists := m.operations.query[key]; !exists {
+			m.operations.query[key] = value
+			continue
+		}
+
+		if key == "$and" {
+			clauses := expressionClauses(m.operations.query["$and"])
+			m.operations.query["$and"] = append(clauses, expressionClauses(value)...)
+			continue
+		}
+
+		m.appendQueryExpression("$and", bson.M{key: value})
+	}
 
 	return m
 }